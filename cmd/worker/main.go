@@ -4,6 +4,7 @@ import (
 	"errors"
 	"kosis/internal/config"
 	"kosis/internal/db"
+	"kosis/internal/pkg/dart"
 	"kosis/internal/tasks"
 	"log"
 	"os"
@@ -59,6 +60,29 @@ func main() {
 	}
 	log.Printf("Registered periodic task: %s (EntryID: %s)", fetchCompaniesTask.Type(), entryID)
 
+	// periodic reports are due 90 days after the business year and 45 days after a quarter,
+	// dividends are fetched about two weeks after each deadline
+	for _, season := range []struct {
+		cron       string
+		reportCode dart.ReportType
+	}{
+		{"0 3 15 4 *", dart.BUSINESS_REPORT},
+		{"0 3 1 6 *", dart.FIRST_QUARTER},
+		{"0 3 1 9 *", dart.HALF_YEAR},
+		{"0 3 1 12 *", dart.THIRD_QUARTER},
+	} {
+		fetchDividendsTask, err := tasks.NewFetchDividendsTask(nil, nil, &season.reportCode)
+		if err != nil {
+			log.Fatalf("Failed to create fetch dividends task: %v", err)
+		}
+
+		entryID, err = scheduler.Register(season.cron, fetchDividendsTask, asynq.Queue("default"))
+		if err != nil {
+			log.Fatalf("Failed to register periodic task: %v", err)
+		}
+		log.Printf("Registered periodic task: %s %s (EntryID: %s)", fetchDividendsTask.Type(), season.reportCode, entryID)
+	}
+
 	// every hour
 	// entryID, err = scheduler.Register("0 * * * *", fetchReportsTask, asynq.Queue("default"))
 	// if err != nil {
@@ -73,11 +97,17 @@ func main() {
 			Queues: map[string]int{
 				"default": 1,
 			},
-			Concurrency: 1, // Max 10 concurrent jobs
+			// filings are fetched and analyzed in parallel, one task per receipt number
+			Concurrency: 4,
+			// tasks out of DART quota wait for the next day
+			RetryDelayFunc: tasks.RetryDelay,
 		},
 	)
 
-	taskProcessor := tasks.NewTaskProcessor(db, cfg)
+	taskProcessor, err := tasks.NewTaskProcessor(db, cfg, tasks.AsynqEnqueuer{Client: asynqClient, Inspector: inspector})
+	if err != nil {
+		log.Fatalf("Failed to create task processor: %v", err)
+	}
 
 	mux := asynq.NewServeMux()
 	mux.HandleFunc(
@@ -85,6 +115,21 @@ func main() {
 		taskProcessor.HandleFetchReportsTask,
 	)
 
+	mux.HandleFunc(
+		tasks.TypeTaskAnalyzeReport,
+		taskProcessor.HandleAnalyzeReportTask,
+	)
+
+	mux.HandleFunc(
+		tasks.TypeTaskFetchFinancials,
+		taskProcessor.HandleFetchFinancialsTask,
+	)
+
+	mux.HandleFunc(
+		tasks.TypeTaskFetchDividends,
+		taskProcessor.HandleFetchDividendsTask,
+	)
+
 	mux.HandleFunc(
 		tasks.TypeTaskFetchCompanies,
 		taskProcessor.HandleFetchCompaniesTask,
@@ -105,7 +150,7 @@ func main() {
 		log.Printf("Deleted %d existing fetch reports tasks", deleted)
 	}
 
-	if _, err := asynqClient.Enqueue(fetchReportsTask, asynq.Queue("default"), asynq.Timeout(2*time.Hour)); err != nil {
+	if _, err := asynqClient.Enqueue(fetchReportsTask, asynq.Queue("default"), asynq.Timeout(30*time.Minute)); err != nil {
 		log.Fatalf("Failed to enqueue fetch reports task: %v", err)
 	}
 
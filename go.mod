@@ -11,6 +11,7 @@ require (
 	github.com/onsi/gomega v1.34.2
 	github.com/openai/openai-go/v3 v3.15.0
 	golang.org/x/net v0.46.0
+	golang.org/x/time v0.8.0
 	gorm.io/driver/postgres v1.6.0
 	gorm.io/gorm v1.31.1
 )
@@ -65,7 +66,6 @@ require (
 	golang.org/x/sync v0.17.0 // indirect
 	golang.org/x/sys v0.37.0 // indirect
 	golang.org/x/text v0.30.0 // indirect
-	golang.org/x/time v0.8.0 // indirect
 	golang.org/x/tools v0.37.0 // indirect
 	google.golang.org/protobuf v1.36.9 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
@@ -15,6 +15,8 @@ type Config struct {
 	OpenAIAPIKey string
 	// Comma-separated origins, or exactly "*" for open CORS (no credentials). For credentialed CORS, list explicit origins only.
 	AllowedOrigins string
+	// Comma-separated report name=parser strategy rules, e.g. "재무제표=instance". Other reports use the html strategy.
+	XBRLParsers string
 }
 
 // LoadConfig reads configuration from environment variables (.env file)
@@ -32,6 +34,7 @@ func LoadConfig() (*Config, error) {
 		KosisAPIKey:    getEnv("KOSIS_API_KEY", ""),
 		OpenAIAPIKey:   getEnv("OPENAI_API_KEY", ""),
 		AllowedOrigins: getEnv("ALLOWED_ORIGINS", ""),
+		XBRLParsers:    getEnv("XBRL_PARSERS", ""),
 	}, nil
 }
 
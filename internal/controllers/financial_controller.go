@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"kosis/internal/models"
 	"log"
+	"math"
 	"net/http"
 	"strconv"
 	"strings"
@@ -23,6 +24,8 @@ type CompanyResponse struct {
 	CorpCode         string `json:"corp_code"`
 	CorpName         string `json:"corp_name"`
 	CorpEngName      string `json:"corp_name_eng"`
+	StockCode        string `json:"stock_code"`
+	CorpCls          string `json:"corp_cls"`
 	LastModifiedDate string `json:"last_modified_date"`
 	CreatedAt        string `json:"created_at"`
 	UpdatedAt        string `json:"updated_at"`
@@ -47,8 +50,121 @@ type ReportSummaryResponse struct {
 	RawReport     string          `json:"raw_report"`
 }
 
+// CorrectionResponse is a correction of a filing and the fields it changed in the analysis
+type CorrectionResponse struct {
+	ReceiptNumber string          `json:"receipt_number"`
+	ReportName    string          `json:"report_name"`
+	Diff          json.RawMessage `json:"diff"`
+}
+
+// ReportFileResponse is a file of the document archive of a filing
+type ReportFileResponse struct {
+	Filename string `json:"filename"`
+	Main     bool   `json:"main"`
+	Size     int    `json:"size"`
+}
+
+type FinancialFactPoint struct {
+	PeriodEnd     string `json:"period_end"`
+	PeriodType    string `json:"period_type"`
+	Value         int64  `json:"value"`
+	Unit          string `json:"unit"`
+	Source        string `json:"source"`
+	ReceiptNumber string `json:"receipt_number"`
+}
+
+type FinancialSeriesResponse struct {
+	Account      string               `json:"account"`
+	Statement    string               `json:"statement"`
+	Consolidated bool                 `json:"consolidated"`
+	Series       []FinancialFactPoint `json:"series"`
+}
+
+type DividendResponse struct {
+	Year              int      `json:"year"`
+	StockKind         string   `json:"stock_kind"`
+	DividendPerShare  *int64   `json:"dividend_per_share"`
+	DividendYield     *float64 `json:"dividend_yield"`
+	PayoutRatio       *float64 `json:"payout_ratio"`
+	EPS               *int64   `json:"eps"`
+	TotalCashDividend *int64   `json:"total_cash_dividend"`
+	SettlementDate    *string  `json:"settlement_date"`
+	ReceiptNumber     string   `json:"receipt_number"`
+}
+
+type InsiderNetBuyingResponse struct {
+	CorpCode     string `json:"corp_code"`
+	CorpName     string `json:"corp_name"`
+	NetShares    int64  `json:"net_shares"`
+	BoughtShares int64  `json:"bought_shares"`
+	SoldShares   int64  `json:"sold_shares"`
+	NetAmount    int64  `json:"net_amount"` // KRW, changes without a price are not counted
+	Transactions int    `json:"transactions"`
+}
+
+type TreasuryStockEventResponse struct {
+	ReceiptNumber  string  `json:"receipt_number"`
+	Event          string  `json:"event"`
+	CommonShares   *int64  `json:"common_shares"`
+	OtherShares    *int64  `json:"other_shares"`
+	Amount         *int64  `json:"amount"`
+	ReferencePrice *int64  `json:"reference_price"`
+	PeriodFrom     *string `json:"period_from"`
+	PeriodTo       *string `json:"period_to"`
+	Purpose        string  `json:"purpose"`
+	Method         string  `json:"method"`
+	BoardDate      *string `json:"board_date"`
+	MarketCap      *int64  `json:"market_cap"`
+}
+
+// TreasuryStockSummary adds up the events, buyback authorizations are acquisitions and trust contracts
+type TreasuryStockSummary struct {
+	AuthorizedAmount      int64    `json:"authorized_amount"`
+	AuthorizedShares      int64    `json:"authorized_shares"`
+	DisposedAmount        int64    `json:"disposed_amount"`
+	DisposedShares        int64    `json:"disposed_shares"`
+	CancelledAmount       int64    `json:"cancelled_amount"`
+	CancelledShares       int64    `json:"cancelled_shares"`
+	MarketCap             *int64   `json:"market_cap"`               // of the latest event with a reference price
+	AuthorizedToMarketCap *float64 `json:"authorized_to_market_cap"` // %
+}
+
 const maxPageLimit = 100
 
+// latestVersionOnly hides raw reports that a later correction supersedes, so lists show the effective version of a filing
+const latestVersionOnly = `NOT EXISTS (SELECT 1 FROM raw_reports corrections
+	WHERE corrections.corrects_receipt_number = COALESCE(raw_reports.corrects_receipt_number, raw_reports.receipt_number)
+	AND corrections.receipt_number > raw_reports.receipt_number)`
+
+// latestVersionOf hides the rows of a table that come from a filing a later correction supersedes
+func latestVersionOf(table string) string {
+	return `NOT EXISTS (SELECT 1 FROM raw_reports superseded JOIN raw_reports corrections
+	ON corrections.corrects_receipt_number = COALESCE(superseded.corrects_receipt_number, superseded.receipt_number)
+	AND corrections.receipt_number > superseded.receipt_number
+	WHERE superseded.receipt_number = ` + table + `.receipt_number)`
+}
+
+// insiderTradeReportedLater hides a trade a later filing reports again: a correction whose original is not known,
+// or a major holder whose trade is in both the 임원ㆍ주요주주 report and the 대량보유 report
+var insiderTradeReportedLater = `NOT EXISTS (SELECT 1 FROM insider_transactions later
+	WHERE later.corp_code = insider_transactions.corp_code
+	AND later.holder_name = insider_transactions.holder_name
+	AND later.change_date = insider_transactions.change_date
+	AND later.shares_change = insider_transactions.shares_change
+	AND later.receipt_number > insider_transactions.receipt_number
+	AND ` + latestVersionOf("later") + `)`
+
+// defaultInsiderWindow is the window of GetInsiderNetBuying without start_date
+const defaultInsiderWindow = 90 * 24 * time.Hour
+
+// sourcePriority decides which fact wins when several sources report the same period
+var sourcePriority = map[string]int{
+	models.FinancialFactSourceDartAPI:     0,
+	models.FinancialFactSourceXBRL:        1,
+	models.FinancialFactSourceReportTable: 2,
+	models.FinancialFactSourceLLM:         3,
+}
+
 // GetCompanies returns a list of all companies
 func (fc *FinancialController) GetCompanies(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -78,6 +194,8 @@ func (fc *FinancialController) GetCompanies(c *gin.Context) {
 			CorpCode:         company.CorpCode,
 			CorpName:         company.CorpName,
 			CorpEngName:      company.CorpEngName,
+			StockCode:        company.StockCode,
+			CorpCls:          company.Category,
 			LastModifiedDate: company.LastModifiedDate.Format("2006-01-02 15:04:05"),
 			CreatedAt:        company.CreatedAt.Format("2006-01-02 15:04:05"),
 			UpdatedAt:        company.UpdatedAt.Format("2006-01-02 15:04:05"),
@@ -108,7 +226,7 @@ func (fc *FinancialController) GetReportsByCorpCode(c *gin.Context) {
 
 	limit := getLimitWithDefault(c, 10)
 
-	baseQuery := fc.DB.Model(&models.Analysis{}).Joins("JOIN raw_reports ON analyses.raw_report_id = raw_reports.id").Where("raw_reports.corp_code = ?", corpCode).Order("created_at DESC").Limit(limit)
+	baseQuery := fc.DB.Model(&models.Analysis{}).Joins("JOIN raw_reports ON analyses.raw_report_id = raw_reports.id").Where("raw_reports.corp_code = ?", corpCode).Where(latestVersionOnly).Order("created_at DESC").Limit(limit)
 
 	var analyses []models.Analysis
 	if err := baseQuery.Find(&analyses).Error; err != nil {
@@ -146,6 +264,7 @@ func (fc *FinancialController) GetRawReport(c *gin.Context) {
 }
 
 // GetReportSummaryByReceiptNumber returns a summary and raw report for a receipt number.
+// Both the original filing and any of its corrections resolve to the latest version, along with the history of corrections.
 // TODO: incase raw report is too large, we should handle it by streaming the raw report.
 func (fc *FinancialController) GetReportSummaryByReceiptNumber(c *gin.Context) {
 	receiptNumber := strings.TrimSpace(c.Param("receipt_number"))
@@ -169,6 +288,32 @@ func (fc *FinancialController) GetReportSummaryByReceiptNumber(c *gin.Context) {
 		return
 	}
 
+	originalReceiptNumber := rawReport.ReceiptNumber
+	if rawReport.CorrectsReceiptNumber != nil {
+		originalReceiptNumber = *rawReport.CorrectsReceiptNumber
+	}
+
+	var corrections []models.RawReport
+	err = fc.DB.Model(&models.RawReport{}).
+		Select("id", "receipt_number", "report_name", "correction_diff").
+		Where("corrects_receipt_number = ?", originalReceiptNumber).
+		Order("receipt_number").
+		Find(&corrections).Error
+	if err != nil {
+		log.Printf("failed to get corrections: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+
+	if len(corrections) > 0 && corrections[len(corrections)-1].ReceiptNumber != rawReport.ReceiptNumber {
+		err = fc.DB.Model(&models.RawReport{}).Where("id = ?", corrections[len(corrections)-1].ID).First(&rawReport).Error
+		if err != nil {
+			log.Printf("failed to get latest correction: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+			return
+		}
+	}
+
 	var analysis models.Analysis
 	err = fc.DB.Model(&models.Analysis{}).Where("raw_report_id = ?", rawReport.ID).First(&analysis).Error
 	if err != nil {
@@ -184,9 +329,92 @@ func (fc *FinancialController) GetReportSummaryByReceiptNumber(c *gin.Context) {
 		return
 	}
 
+	history := []CorrectionResponse{}
+	for _, correction := range corrections {
+		history = append(history, CorrectionResponse{
+			ReceiptNumber: correction.ReceiptNumber,
+			ReportName:    correction.ReportName,
+			Diff:          correction.CorrectionDiff,
+		})
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"summary":    analysis.Analysis,
-		"raw_report": base64.StdEncoding.EncodeToString(rawReport.BlobData),
+		"receipt_number":          rawReport.ReceiptNumber,
+		"original_receipt_number": originalReceiptNumber,
+		"corrections":             history,
+		"summary":                 analysis.Analysis,
+		"raw_report":              base64.StdEncoding.EncodeToString(rawReport.BlobData),
+	})
+}
+
+// GetReportFiles lists the files of the document archive of a filing, the main document first
+func (fc *FinancialController) GetReportFiles(c *gin.Context) {
+	receiptNumber := strings.TrimSpace(c.Param("receipt_number"))
+
+	var files []models.RawReportFile
+	err := fc.DB.Model(&models.RawReportFile{}).
+		Select("filename", "main", "blob_size").
+		Where("receipt_number = ?", receiptNumber).
+		Order("main DESC, filename").
+		Find(&files).Error
+	if err != nil {
+		log.Printf("failed to get report files: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+
+	if len(files) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Report files not found"})
+		return
+	}
+
+	res := []ReportFileResponse{}
+	for _, f := range files {
+		res = append(res, ReportFileResponse{Filename: f.Filename, Main: f.Main, Size: f.BlobSize})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"receipt_number": receiptNumber,
+		"files":          res,
+	})
+}
+
+// GetReportFile returns a file of the document archive of a filing, parsed and raw
+func (fc *FinancialController) GetReportFile(c *gin.Context) {
+	receiptNumber := strings.TrimSpace(c.Param("receipt_number"))
+	filename := c.Param("filename")
+
+	var file models.RawReportFile
+	err := fc.DB.Model(&models.RawReportFile{}).Where("receipt_number = ? AND filename = ?", receiptNumber, filename).First(&file).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Report file not found"})
+			return
+		}
+
+		log.Printf("failed to get report file: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+
+	// the main document is kept on the raw report
+	if file.Main {
+		var rawReport models.RawReport
+		if err := fc.DB.Where("receipt_number = ?", receiptNumber).First(&rawReport).Error; err != nil {
+			log.Printf("failed to get raw report of main file: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+			return
+		}
+		file.BlobData = rawReport.BlobData
+		file.JSONData = rawReport.JSONData
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"receipt_number": file.ReceiptNumber,
+		"filename":       file.Filename,
+		"main":           file.Main,
+		"parsed":         file.JSONData,
+		"raw_report":     base64.StdEncoding.EncodeToString(file.BlobData),
 	})
 }
 
@@ -207,6 +435,7 @@ func (fc *FinancialController) GetReportsByCorpName(c *gin.Context) {
 		Joins("JOIN raw_reports ON analyses.raw_report_id = raw_reports.id").
 		Joins("JOIN companies ON companies.corp_code = raw_reports.corp_code").
 		Where("companies.corp_name ILIKE ?", "%"+corpName+"%").
+		Where(latestVersionOnly).
 		Order("raw_reports.receipt_number DESC").
 		Limit(limit).
 		Find(&analyses).Error
@@ -239,6 +468,7 @@ func (fc *FinancialController) GetAllReports(c *gin.Context) {
 		Select("analyses.raw_report_id, raw_reports.receipt_number, raw_reports.corp_code, companies.corp_name, raw_reports.report_name, analyses.analysis").
 		Joins("JOIN raw_reports ON analyses.raw_report_id = raw_reports.id").
 		Joins("JOIN companies ON companies.corp_code = raw_reports.corp_code").
+		Where(latestVersionOnly).
 		Order("raw_reports.receipt_number DESC")
 
 	if corpCode != "" {
@@ -308,6 +538,287 @@ func (fc *FinancialController) GetAllReports(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"reports": res})
 }
 
+// GetFinancials returns a company's financial facts as one time series per account
+// Possible query parameters:
+// - account: only return the given account, e.g. sales
+// - statement: only return the given statement, BS, IS or CF
+// - period_type: only return the given period type, Q1, H1, Q3 or FY
+// - consolidated: true or false, both when missing
+func (fc *FinancialController) GetFinancials(c *gin.Context) {
+	ctx := c.Request.Context()
+	corpCode := c.Param("corp_code")
+
+	query := gorm.G[models.FinancialFact](fc.DB).Where("corp_code = ?", corpCode).Where(latestVersionOf("financial_facts"))
+
+	if account := c.Query("account"); account != "" {
+		query = query.Where("account = ?", account)
+	}
+
+	if statement := c.Query("statement"); statement != "" {
+		query = query.Where("statement = ?", strings.ToUpper(statement))
+	}
+
+	if periodType := c.Query("period_type"); periodType != "" {
+		query = query.Where("period_type = ?", strings.ToUpper(periodType))
+	}
+
+	if consolidatedStr := c.Query("consolidated"); consolidatedStr != "" {
+		consolidated, err := strconv.ParseBool(consolidatedStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "consolidated must be true or false"})
+			return
+		}
+		query = query.Where("consolidated = ?", consolidated)
+	}
+
+	facts, err := query.Order("statement, account, consolidated DESC, period_end, period_type").Find(ctx)
+	if err != nil {
+		log.Printf("failed to get financial facts: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+
+	res := []FinancialSeriesResponse{}
+	for _, fact := range facts {
+		if len(res) == 0 || res[len(res)-1].Account != fact.Account || res[len(res)-1].Statement != fact.Statement || res[len(res)-1].Consolidated != fact.Consolidated {
+			res = append(res, FinancialSeriesResponse{
+				Account:      fact.Account,
+				Statement:    fact.Statement,
+				Consolidated: fact.Consolidated,
+				Series:       []FinancialFactPoint{},
+			})
+		}
+
+		series := &res[len(res)-1]
+		point := FinancialFactPoint{
+			PeriodEnd:     fact.PeriodEnd.Format("2006-01-02"),
+			PeriodType:    fact.PeriodType,
+			Value:         fact.Value,
+			Unit:          fact.Unit,
+			Source:        fact.Source,
+			ReceiptNumber: fact.ReceiptNumber,
+		}
+
+		// facts of the same period are adjacent, keep the most reliable source
+		if n := len(series.Series); n > 0 && series.Series[n-1].PeriodEnd == point.PeriodEnd && series.Series[n-1].PeriodType == point.PeriodType {
+			if sourcePriority[point.Source] < sourcePriority[series.Series[n-1].Source] {
+				series.Series[n-1] = point
+			}
+			continue
+		}
+
+		series.Series = append(series.Series, point)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"corp_code":  corpCode,
+		"financials": res,
+	})
+}
+
+// GetDividends returns a company's dividends by year, most recent first
+// Possible query parameters:
+// - stock_kind: only return the given stock kind, e.g. 보통주
+// - limit: limit the number of years to return
+func (fc *FinancialController) GetDividends(c *gin.Context) {
+	ctx := c.Request.Context()
+	corpCode := c.Param("corp_code")
+	limit := getLimitWithDefault(c, 10)
+
+	query := gorm.G[models.Dividend](fc.DB).Where("corp_code = ?", corpCode)
+	if stockKind := c.Query("stock_kind"); stockKind != "" {
+		query = query.Where("stock_kind = ?", stockKind)
+	}
+
+	// limit applies to years, a year has a row per stock kind
+	query = query.Where("year IN (SELECT DISTINCT year FROM dividends WHERE corp_code = ? ORDER BY year DESC LIMIT ?)", corpCode, limit)
+
+	dividends, err := query.Order("year DESC, stock_kind").Find(ctx)
+	if err != nil {
+		log.Printf("failed to get dividends: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+
+	res := []DividendResponse{}
+	for _, dividend := range dividends {
+		var settlementDate *string
+		if dividend.SettlementDate != nil {
+			d := dividend.SettlementDate.Format("2006-01-02")
+			settlementDate = &d
+		}
+
+		res = append(res, DividendResponse{
+			Year:              dividend.Year,
+			StockKind:         dividend.StockKind,
+			DividendPerShare:  dividend.DividendPerShare,
+			DividendYield:     dividend.DividendYield,
+			PayoutRatio:       dividend.PayoutRatio,
+			EPS:               dividend.EPS,
+			TotalCashDividend: dividend.TotalCashDividend,
+			SettlementDate:    settlementDate,
+			ReceiptNumber:     dividend.ReceiptNumber,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"corp_code": corpCode,
+		"dividends": res,
+	})
+}
+
+// GetInsiderNetBuying lists the net buying of insiders and major holders per company, largest first. A trade
+// filed more than once, by a correction or by another report of the holder, is counted once.
+// Possible query parameters:
+// - start_date, end_date: window of change dates (YYYY-MM-DD), the last 90 days by default
+// - corp_code: only the given company
+// - limit: limit the number of companies to return
+func (fc *FinancialController) GetInsiderNetBuying(c *gin.Context) {
+	limit := getLimitWithDefault(c, 20)
+
+	endDate := time.Now().UTC().Truncate(24 * time.Hour)
+	if s := c.Query("end_date"); s != "" {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "end_date must be YYYY-MM-DD"})
+			return
+		}
+		endDate = d
+	}
+
+	startDate := endDate.Add(-defaultInsiderWindow)
+	if s := c.Query("start_date"); s != "" {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "start_date must be YYYY-MM-DD"})
+			return
+		}
+		startDate = d
+	}
+
+	query := fc.DB.
+		Model(&models.InsiderTransaction{}).
+		Select(`insider_transactions.corp_code,
+			COALESCE(MAX(companies.corp_name), '') AS corp_name,
+			SUM(insider_transactions.shares_change) AS net_shares,
+			SUM(CASE WHEN insider_transactions.shares_change > 0 THEN insider_transactions.shares_change ELSE 0 END) AS bought_shares,
+			SUM(CASE WHEN insider_transactions.shares_change < 0 THEN -insider_transactions.shares_change ELSE 0 END) AS sold_shares,
+			COALESCE(SUM(insider_transactions.shares_change * insider_transactions.price), 0) AS net_amount,
+			COUNT(*) AS transactions`).
+		Joins("LEFT JOIN companies ON companies.corp_code = insider_transactions.corp_code").
+		Where("insider_transactions.change_date BETWEEN ? AND ?", startDate.Format("2006-01-02"), endDate.Format("2006-01-02")).
+		Where(latestVersionOf("insider_transactions")).
+		Where(insiderTradeReportedLater)
+
+	if corpCode := c.Query("corp_code"); corpCode != "" {
+		query = query.Where("insider_transactions.corp_code = ?", corpCode)
+	}
+
+	res := []InsiderNetBuyingResponse{}
+	err := query.
+		Group("insider_transactions.corp_code").
+		Order("net_amount DESC, net_shares DESC").
+		Limit(limit).
+		Scan(&res).Error
+	if err != nil {
+		log.Printf("failed to get insider net buying: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"start_date": startDate.Format("2006-01-02"),
+		"end_date":   endDate.Format("2006-01-02"),
+		"companies":  res,
+	})
+}
+
+// GetTreasuryStock returns the treasury stock events of a company, most recent first, and the
+// cumulative buyback authorizations against the market cap
+// Possible query parameters:
+// - start_date: only events decided on or after the date (YYYY-MM-DD)
+func (fc *FinancialController) GetTreasuryStock(c *gin.Context) {
+	ctx := c.Request.Context()
+	corpCode := c.Param("corp_code")
+
+	query := gorm.G[models.TreasuryStockEvent](fc.DB).Where("corp_code = ?", corpCode).Where(latestVersionOf("treasury_stock_events"))
+	if s := c.Query("start_date"); s != "" {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "start_date must be YYYY-MM-DD"})
+			return
+		}
+		query = query.Where("board_date >= ?", d.Format("2006-01-02"))
+	}
+
+	events, err := query.Order("board_date DESC NULLS LAST, receipt_number DESC").Find(ctx)
+	if err != nil {
+		log.Printf("failed to get treasury stock events: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+
+	formatDate := func(t *time.Time) *string {
+		if t == nil {
+			return nil
+		}
+		d := t.Format("2006-01-02")
+		return &d
+	}
+	valueOf := func(v *int64) int64 {
+		if v == nil {
+			return 0
+		}
+		return *v
+	}
+
+	summary := TreasuryStockSummary{}
+	res := []TreasuryStockEventResponse{}
+	for _, event := range events {
+		shares := valueOf(event.CommonShares) + valueOf(event.OtherShares)
+		switch event.Event {
+		case models.TreasuryStockAcquisition, models.TreasuryStockTrust:
+			summary.AuthorizedAmount += valueOf(event.Amount)
+			summary.AuthorizedShares += shares
+		case models.TreasuryStockDisposal:
+			summary.DisposedAmount += valueOf(event.Amount)
+			summary.DisposedShares += shares
+		case models.TreasuryStockCancellation:
+			summary.CancelledAmount += valueOf(event.Amount)
+			summary.CancelledShares += shares
+		}
+		if summary.MarketCap == nil && event.MarketCap != nil {
+			summary.MarketCap = event.MarketCap
+		}
+
+		res = append(res, TreasuryStockEventResponse{
+			ReceiptNumber:  event.ReceiptNumber,
+			Event:          event.Event,
+			CommonShares:   event.CommonShares,
+			OtherShares:    event.OtherShares,
+			Amount:         event.Amount,
+			ReferencePrice: event.ReferencePrice,
+			PeriodFrom:     formatDate(event.PeriodFrom),
+			PeriodTo:       formatDate(event.PeriodTo),
+			Purpose:        event.Purpose,
+			Method:         event.Method,
+			BoardDate:      formatDate(event.BoardDate),
+			MarketCap:      event.MarketCap,
+		})
+	}
+
+	if summary.MarketCap != nil && *summary.MarketCap > 0 {
+		ratio := math.Round(float64(summary.AuthorizedAmount)/float64(*summary.MarketCap)*10000) / 100
+		summary.AuthorizedToMarketCap = &ratio
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"corp_code": corpCode,
+		"summary":   summary,
+		"events":    res,
+	})
+}
+
 func getLimitWithDefault(c *gin.Context, defaultValue int) int {
 	var err error
 	limit := defaultValue
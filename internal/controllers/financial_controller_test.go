@@ -468,6 +468,152 @@ var _ = Describe("FinancialController", func() {
 			Expect(resp.Code).To(Equal(http.StatusNotFound))
 			Expect(resp.Body.String()).To(MatchJSON(`{"error": "Raw report not found"}`))
 		})
+
+		Context("when the filing was corrected", func() {
+			BeforeEach(func() {
+				ctx := context.Background()
+				original := receiptNumber
+				correction := &models.RawReport{
+					ReceiptNumber:         "20251201000005",
+					CorpCode:              "10000001",
+					ReportName:            "[기재정정]Report A",
+					BlobData:              []byte("doc2"),
+					BlobSize:              4,
+					JSONData:              json.RawMessage(`{}`),
+					CorrectsReceiptNumber: &original,
+					CorrectionDiff:        json.RawMessage(`[{"path":"summary","before":"a","after":"b"}]`),
+				}
+				createRawReport(dbConn, ctx, correction)
+				createAnalysis(dbConn, ctx, &models.Analysis{
+					RawReportID: correction.ID,
+					Analysis:    json.RawMessage(`{"summary":"b"}`),
+				})
+			})
+
+			It("returns the latest version with its correction history", func() {
+				for _, rn := range []string{receiptNumber, "20251201000005"} {
+					req := httptest.NewRequest(http.MethodGet, "/api/v1/reports/receipt/"+rn, nil)
+					resp := httptest.NewRecorder()
+
+					router.ServeHTTP(resp, req)
+					Expect(resp.Code).To(Equal(http.StatusOK))
+
+					var body struct {
+						ReceiptNumber         string                           `json:"receipt_number"`
+						OriginalReceiptNumber string                           `json:"original_receipt_number"`
+						Corrections           []controllers.CorrectionResponse `json:"corrections"`
+						Summary               json.RawMessage                  `json:"summary"`
+						RawReport             string                           `json:"raw_report"`
+					}
+					Expect(json.Unmarshal(resp.Body.Bytes(), &body)).To(Succeed())
+					Expect(body.ReceiptNumber).To(Equal("20251201000005"))
+					Expect(body.OriginalReceiptNumber).To(Equal(receiptNumber))
+					Expect(string(body.Summary)).To(MatchJSON(`{"summary":"b"}`))
+					Expect(body.RawReport).To(Equal(base64.StdEncoding.EncodeToString([]byte("doc2"))))
+					Expect(body.Corrections).To(HaveLen(1))
+					Expect(body.Corrections[0].ReceiptNumber).To(Equal("20251201000005"))
+					Expect(string(body.Corrections[0].Diff)).To(MatchJSON(`[{"path":"summary","before":"a","after":"b"}]`))
+				}
+			})
+
+			It("lists only the latest version", func() {
+				ctx := context.Background()
+				createCompany(dbConn, ctx, &models.Company{CorpCode: "10000001", CorpName: "A 회사"})
+
+				req := httptest.NewRequest(http.MethodGet, "/api/v1/reports", nil)
+				resp := httptest.NewRecorder()
+
+				router.ServeHTTP(resp, req)
+				Expect(resp.Code).To(Equal(http.StatusOK))
+
+				var body struct {
+					Reports []controllers.ReportResponse `json:"reports"`
+				}
+				Expect(json.Unmarshal(resp.Body.Bytes(), &body)).To(Succeed())
+				Expect(body.Reports).To(HaveLen(1))
+				Expect(body.Reports[0].ReceiptNumber).To(Equal("20251201000005"))
+			})
+		})
+	})
+
+	Describe("GET /api/v1/reports/receipt/:receipt_number/files", func() {
+		receiptNumber := "20250314000123"
+
+		BeforeEach(func() {
+			ctx := context.Background()
+			files := []models.RawReportFile{
+				{ReceiptNumber: receiptNumber, Filename: "20250314000123_00760.xml", BlobData: []byte("audit"), BlobSize: 5, JSONData: json.RawMessage(`{"report_title":"감사보고서"}`)},
+				{ReceiptNumber: receiptNumber, Filename: "20250314000123.xml", Main: true, BlobSize: 6},
+			}
+			Expect(gorm.G[models.RawReportFile](dbConn).CreateInBatches(ctx, &files, 10)).To(Succeed())
+			Expect(gorm.G[models.RawReport](dbConn).Create(ctx, &models.RawReport{
+				ReceiptNumber: receiptNumber,
+				CorpCode:      "00126380",
+				ReportName:    "사업보고서 (2024.12)",
+				BlobData:      []byte("report"),
+				BlobSize:      6,
+				JSONData:      json.RawMessage(`{"report_title":"사업보고서"}`),
+			})).To(Succeed())
+		})
+
+		It("lists the main document first and then the attachments", func() {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/reports/receipt/"+receiptNumber+"/files", nil)
+			resp := httptest.NewRecorder()
+
+			router.ServeHTTP(resp, req)
+			Expect(resp.Code).To(Equal(http.StatusOK))
+			Expect(resp.Body.String()).To(MatchJSON(`{
+				"receipt_number": "20250314000123",
+				"files": [
+					{"filename": "20250314000123.xml", "main": true, "size": 6},
+					{"filename": "20250314000123_00760.xml", "main": false, "size": 5}
+				]
+			}`))
+		})
+
+		It("returns an attachment parsed on its own", func() {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/reports/receipt/"+receiptNumber+"/files/20250314000123_00760.xml", nil)
+			resp := httptest.NewRecorder()
+
+			router.ServeHTTP(resp, req)
+			Expect(resp.Code).To(Equal(http.StatusOK))
+
+			var body struct {
+				Main      bool            `json:"main"`
+				Parsed    json.RawMessage `json:"parsed"`
+				RawReport string          `json:"raw_report"`
+			}
+			Expect(json.Unmarshal(resp.Body.Bytes(), &body)).To(Succeed())
+			Expect(body.Main).To(BeFalse())
+			Expect(string(body.Parsed)).To(MatchJSON(`{"report_title":"감사보고서"}`))
+			Expect(body.RawReport).To(Equal(base64.StdEncoding.EncodeToString([]byte("audit"))))
+		})
+
+		It("returns the main document from its raw report", func() {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/reports/receipt/"+receiptNumber+"/files/20250314000123.xml", nil)
+			resp := httptest.NewRecorder()
+
+			router.ServeHTTP(resp, req)
+			Expect(resp.Code).To(Equal(http.StatusOK))
+
+			var body struct {
+				Main      bool            `json:"main"`
+				Parsed    json.RawMessage `json:"parsed"`
+				RawReport string          `json:"raw_report"`
+			}
+			Expect(json.Unmarshal(resp.Body.Bytes(), &body)).To(Succeed())
+			Expect(body.Main).To(BeTrue())
+			Expect(string(body.Parsed)).To(MatchJSON(`{"report_title":"사업보고서"}`))
+			Expect(body.RawReport).To(Equal(base64.StdEncoding.EncodeToString([]byte("report"))))
+		})
+
+		It("returns 404 for an unknown file", func() {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/reports/receipt/"+receiptNumber+"/files/unknown.xml", nil)
+			resp := httptest.NewRecorder()
+
+			router.ServeHTTP(resp, req)
+			Expect(resp.Code).To(Equal(http.StatusNotFound))
+		})
 	})
 
 	Describe("GET /api/v1/mcp/reports/by-corp-name", func() {
@@ -615,4 +761,357 @@ var _ = Describe("FinancialController", func() {
 			Expect(len(body.Reports)).To(BeNumerically("<=", 100)) // Capped at 100
 		})
 	})
+
+	Describe("GET /api/v1/companies/:corp_code/financials", func() {
+		BeforeEach(func() {
+			ctx := context.Background()
+
+			facts := []models.FinancialFact{
+				{CorpCode: "10000001", PeriodEnd: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), PeriodType: models.PeriodTypeFY, Statement: models.StatementIncomeStatement, Account: "sales", Consolidated: true, Value: 1000, Unit: "KRW", ReceiptNumber: "20250311000001", Source: models.FinancialFactSourceLLM},
+				{CorpCode: "10000001", PeriodEnd: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), PeriodType: models.PeriodTypeFY, Statement: models.StatementIncomeStatement, Account: "sales", Consolidated: true, Value: 1200, Unit: "KRW", ReceiptNumber: "20250311000001", Source: models.FinancialFactSourceDartAPI},
+				{CorpCode: "10000001", PeriodEnd: time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC), PeriodType: models.PeriodTypeH1, Statement: models.StatementIncomeStatement, Account: "sales", Consolidated: true, Value: 700, Unit: "KRW", ReceiptNumber: "20250814000001", Source: models.FinancialFactSourceLLM},
+				{CorpCode: "10000001", PeriodEnd: time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC), PeriodType: models.PeriodTypeH1, Statement: models.StatementBalanceSheet, Account: "total_assets", Consolidated: false, Value: 5000, Unit: "KRW", ReceiptNumber: "20250814000001", Source: models.FinancialFactSourceLLM},
+				{CorpCode: "10000002", PeriodEnd: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), PeriodType: models.PeriodTypeFY, Statement: models.StatementIncomeStatement, Account: "sales", Consolidated: true, Value: 1, Unit: "KRW", ReceiptNumber: "20250311000002", Source: models.FinancialFactSourceLLM},
+			}
+			for i := range facts {
+				Expect(gorm.G[models.FinancialFact](dbConn).Create(ctx, &facts[i])).To(Succeed())
+			}
+		})
+
+		It("returns one series per account preferring DART figures", func() {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/companies/10000001/financials", nil)
+			resp := httptest.NewRecorder()
+
+			router.ServeHTTP(resp, req)
+
+			Expect(resp.Code).To(Equal(http.StatusOK))
+
+			var body struct {
+				Financials []controllers.FinancialSeriesResponse `json:"financials"`
+			}
+			Expect(json.Unmarshal(resp.Body.Bytes(), &body)).To(Succeed())
+			Expect(body.Financials).To(HaveLen(2))
+
+			Expect(body.Financials[0].Account).To(Equal("total_assets"))
+			Expect(body.Financials[0].Consolidated).To(BeFalse())
+
+			sales := body.Financials[1]
+			Expect(sales.Account).To(Equal("sales"))
+			Expect(sales.Consolidated).To(BeTrue())
+			Expect(sales.Series).To(HaveLen(2))
+			Expect(sales.Series[0].PeriodEnd).To(Equal("2024-12-31"))
+			Expect(sales.Series[0].Value).To(Equal(int64(1200)))
+			Expect(sales.Series[0].Source).To(Equal(models.FinancialFactSourceDartAPI))
+			Expect(sales.Series[1].PeriodEnd).To(Equal("2025-06-30"))
+			Expect(sales.Series[1].PeriodType).To(Equal(models.PeriodTypeH1))
+		})
+
+		It("filters by account and period type", func() {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/companies/10000001/financials?account=sales&period_type=fy", nil)
+			resp := httptest.NewRecorder()
+
+			router.ServeHTTP(resp, req)
+
+			Expect(resp.Code).To(Equal(http.StatusOK))
+
+			var body struct {
+				Financials []controllers.FinancialSeriesResponse `json:"financials"`
+			}
+			Expect(json.Unmarshal(resp.Body.Bytes(), &body)).To(Succeed())
+			Expect(body.Financials).To(HaveLen(1))
+			Expect(body.Financials[0].Series).To(HaveLen(1))
+			Expect(body.Financials[0].Series[0].Value).To(Equal(int64(1200)))
+		})
+
+		It("leaves out the facts of a corrected filing", func() {
+			ctx := context.Background()
+			original := "20250814000001"
+			Expect(gorm.G[models.RawReport](dbConn).Create(ctx, &models.RawReport{ReceiptNumber: original, CorpCode: "10000001", ReportName: "반기보고서 (2025.06)", JSONData: json.RawMessage(`{}`)})).To(Succeed())
+			Expect(gorm.G[models.RawReport](dbConn).Create(ctx, &models.RawReport{ReceiptNumber: "20250901000001", CorpCode: "10000001", ReportName: "[기재정정]반기보고서 (2025.06)", CorrectsReceiptNumber: &original, JSONData: json.RawMessage(`{}`)})).To(Succeed())
+			Expect(gorm.G[models.FinancialFact](dbConn).Create(ctx, &models.FinancialFact{CorpCode: "10000001", PeriodEnd: time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC), PeriodType: models.PeriodTypeH1, Statement: models.StatementIncomeStatement, Account: "sales", Consolidated: true, Value: 750, Unit: "KRW", ReceiptNumber: "20250901000001", Source: models.FinancialFactSourceXBRL})).To(Succeed())
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/companies/10000001/financials", nil)
+			resp := httptest.NewRecorder()
+
+			router.ServeHTTP(resp, req)
+
+			Expect(resp.Code).To(Equal(http.StatusOK))
+
+			var body struct {
+				Financials []controllers.FinancialSeriesResponse `json:"financials"`
+			}
+			Expect(json.Unmarshal(resp.Body.Bytes(), &body)).To(Succeed())
+			Expect(body.Financials).To(HaveLen(1))
+
+			sales := body.Financials[0]
+			Expect(sales.Account).To(Equal("sales"))
+			Expect(sales.Series).To(HaveLen(2))
+			Expect(sales.Series[1].Value).To(Equal(int64(750)))
+			Expect(sales.Series[1].ReceiptNumber).To(Equal("20250901000001"))
+		})
+
+		It("returns 400 for an invalid consolidated flag", func() {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/companies/10000001/financials?consolidated=maybe", nil)
+			resp := httptest.NewRecorder()
+
+			router.ServeHTTP(resp, req)
+
+			Expect(resp.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Describe("GET /api/v1/companies/:corp_code/dividends", func() {
+		BeforeEach(func() {
+			ctx := context.Background()
+
+			for year := 2020; year <= 2024; year++ {
+				for _, stockKind := range []string{"보통주", "우선주"} {
+					dps := int64(year)
+					payoutRatio := 25.5
+					Expect(gorm.G[models.Dividend](dbConn).Create(ctx, &models.Dividend{
+						CorpCode:         "10000001",
+						Year:             year,
+						StockKind:        stockKind,
+						DividendPerShare: &dps,
+						PayoutRatio:      &payoutRatio,
+						SourceYear:       2024,
+						ReceiptNumber:    "20250311000001",
+					})).To(Succeed())
+				}
+			}
+		})
+
+		It("returns dividends by year, most recent first", func() {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/companies/10000001/dividends?limit=2", nil)
+			resp := httptest.NewRecorder()
+
+			router.ServeHTTP(resp, req)
+
+			Expect(resp.Code).To(Equal(http.StatusOK))
+
+			var body struct {
+				Dividends []controllers.DividendResponse `json:"dividends"`
+			}
+			Expect(json.Unmarshal(resp.Body.Bytes(), &body)).To(Succeed())
+			Expect(body.Dividends).To(HaveLen(4))
+			Expect(body.Dividends[0].Year).To(Equal(2024))
+			Expect(body.Dividends[0].StockKind).To(Equal("보통주"))
+			Expect(*body.Dividends[0].DividendPerShare).To(Equal(int64(2024)))
+			Expect(*body.Dividends[0].PayoutRatio).To(Equal(25.5))
+			Expect(body.Dividends[3].Year).To(Equal(2023))
+		})
+
+		It("filters by stock kind", func() {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/companies/10000001/dividends?stock_kind=우선주", nil)
+			resp := httptest.NewRecorder()
+
+			router.ServeHTTP(resp, req)
+
+			Expect(resp.Code).To(Equal(http.StatusOK))
+
+			var body struct {
+				Dividends []controllers.DividendResponse `json:"dividends"`
+			}
+			Expect(json.Unmarshal(resp.Body.Bytes(), &body)).To(Succeed())
+			Expect(body.Dividends).To(HaveLen(5))
+			for _, d := range body.Dividends {
+				Expect(d.StockKind).To(Equal("우선주"))
+			}
+		})
+	})
+
+	Describe("GET /api/v1/insider-trading", func() {
+		BeforeEach(func() {
+			ctx := context.Background()
+
+			createCompany(dbConn, ctx, &models.Company{CorpCode: "10000001", CorpName: "테스트기업1"})
+			createCompany(dbConn, ctx, &models.Company{CorpCode: "10000002", CorpName: "테스트기업2"})
+
+			price := func(v int64) *int64 { return &v }
+			transactions := []models.InsiderTransaction{
+				{CorpCode: "10000001", ReceiptNumber: "20250616000001", Seq: 0, HolderName: "홍길동", ChangeDate: time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC), SharesChange: 10000, Price: price(15000)},
+				{CorpCode: "10000001", ReceiptNumber: "20250616000001", Seq: 1, HolderName: "홍길동", ChangeDate: time.Date(2025, 6, 13, 0, 0, 0, 0, time.UTC), SharesChange: -2000, Price: price(16000)},
+				{CorpCode: "10000002", ReceiptNumber: "20250616000002", Seq: 0, HolderName: "김철수", ChangeDate: time.Date(2025, 6, 11, 0, 0, 0, 0, time.UTC), SharesChange: -5000, Price: price(20000)},
+				{CorpCode: "10000002", ReceiptNumber: "20250102000001", Seq: 0, HolderName: "김철수", ChangeDate: time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC), SharesChange: 100000, Price: price(20000)},
+			}
+			for i := range transactions {
+				Expect(gorm.G[models.InsiderTransaction](dbConn).Create(ctx, &transactions[i])).To(Succeed())
+			}
+		})
+
+		It("returns net buying per company within the window, largest first", func() {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/insider-trading?start_date=2025-06-01&end_date=2025-06-30", nil)
+			resp := httptest.NewRecorder()
+
+			router.ServeHTTP(resp, req)
+
+			Expect(resp.Code).To(Equal(http.StatusOK))
+
+			var body struct {
+				Companies []controllers.InsiderNetBuyingResponse `json:"companies"`
+			}
+			Expect(json.Unmarshal(resp.Body.Bytes(), &body)).To(Succeed())
+			Expect(body.Companies).To(HaveLen(2))
+
+			Expect(body.Companies[0].CorpCode).To(Equal("10000001"))
+			Expect(body.Companies[0].CorpName).To(Equal("테스트기업1"))
+			Expect(body.Companies[0].NetShares).To(Equal(int64(8000)))
+			Expect(body.Companies[0].BoughtShares).To(Equal(int64(10000)))
+			Expect(body.Companies[0].SoldShares).To(Equal(int64(2000)))
+			Expect(body.Companies[0].NetAmount).To(Equal(int64(118000000)))
+			Expect(body.Companies[0].Transactions).To(Equal(2))
+
+			Expect(body.Companies[1].CorpCode).To(Equal("10000002"))
+			Expect(body.Companies[1].NetShares).To(Equal(int64(-5000)))
+		})
+
+		It("counts a trade filed again once", func() {
+			ctx := context.Background()
+			price := func(v int64) *int64 { return &v }
+
+			// the correction of the first filing and the 대량보유 report of the same holder repeat its trades
+			original := "20250616000001"
+			Expect(gorm.G[models.RawReport](dbConn).Create(ctx, &models.RawReport{ReceiptNumber: original, CorpCode: "10000001", ReportName: "임원ㆍ주요주주특정증권등소유상황보고서", JSONData: json.RawMessage(`{}`)})).To(Succeed())
+			Expect(gorm.G[models.RawReport](dbConn).Create(ctx, &models.RawReport{ReceiptNumber: "20250620000001", CorpCode: "10000001", ReportName: "[기재정정]임원ㆍ주요주주특정증권등소유상황보고서", CorrectsReceiptNumber: &original, JSONData: json.RawMessage(`{}`)})).To(Succeed())
+			transactions := []models.InsiderTransaction{
+				{CorpCode: "10000001", ReceiptNumber: "20250620000001", Seq: 0, HolderName: "홍길동", ChangeDate: time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC), SharesChange: 10000, Price: price(15000)},
+				{CorpCode: "10000001", ReceiptNumber: "20250620000001", Seq: 1, HolderName: "홍길동", ChangeDate: time.Date(2025, 6, 13, 0, 0, 0, 0, time.UTC), SharesChange: -3000, Price: price(16000)},
+				{CorpCode: "10000001", ReceiptNumber: "20250620000002", Seq: 0, HolderName: "홍길동", ChangeDate: time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC), SharesChange: 10000, Price: price(15000)},
+			}
+			for i := range transactions {
+				Expect(gorm.G[models.InsiderTransaction](dbConn).Create(ctx, &transactions[i])).To(Succeed())
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/insider-trading?start_date=2025-06-01&end_date=2025-06-30&corp_code=10000001", nil)
+			resp := httptest.NewRecorder()
+
+			router.ServeHTTP(resp, req)
+
+			Expect(resp.Code).To(Equal(http.StatusOK))
+
+			var body struct {
+				Companies []controllers.InsiderNetBuyingResponse `json:"companies"`
+			}
+			Expect(json.Unmarshal(resp.Body.Bytes(), &body)).To(Succeed())
+			Expect(body.Companies).To(HaveLen(1))
+			Expect(body.Companies[0].NetShares).To(Equal(int64(7000)))
+			Expect(body.Companies[0].BoughtShares).To(Equal(int64(10000)))
+			Expect(body.Companies[0].SoldShares).To(Equal(int64(3000)))
+			Expect(body.Companies[0].Transactions).To(Equal(2))
+		})
+
+		It("filters by corp code", func() {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/insider-trading?start_date=2024-12-01&end_date=2025-06-30&corp_code=10000002", nil)
+			resp := httptest.NewRecorder()
+
+			router.ServeHTTP(resp, req)
+
+			Expect(resp.Code).To(Equal(http.StatusOK))
+
+			var body struct {
+				Companies []controllers.InsiderNetBuyingResponse `json:"companies"`
+			}
+			Expect(json.Unmarshal(resp.Body.Bytes(), &body)).To(Succeed())
+			Expect(body.Companies).To(HaveLen(1))
+			Expect(body.Companies[0].NetShares).To(Equal(int64(95000)))
+		})
+
+		It("returns 400 for an invalid date", func() {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/insider-trading?start_date=2025/06/01", nil)
+			resp := httptest.NewRecorder()
+
+			router.ServeHTTP(resp, req)
+
+			Expect(resp.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Describe("GET /api/v1/companies/:corp_code/treasury-stock", func() {
+		BeforeEach(func() {
+			ctx := context.Background()
+
+			ptr := func(v int64) *int64 { return &v }
+			date := func(y int, m time.Month, d int) *time.Time {
+				t := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+				return &t
+			}
+			events := []models.TreasuryStockEvent{
+				{CorpCode: "10000001", ReceiptNumber: "20250318000001", Event: models.TreasuryStockAcquisition, CommonShares: ptr(1000000), Amount: ptr(70000000000), ReferencePrice: ptr(70000), BoardDate: date(2025, 3, 18), MarketCap: ptr(3500000000000)},
+				{CorpCode: "10000001", ReceiptNumber: "20250430000001", Event: models.TreasuryStockTrust, Amount: ptr(10000000000), BoardDate: date(2025, 4, 30)},
+				{CorpCode: "10000001", ReceiptNumber: "20250703000001", Event: models.TreasuryStockCancellation, CommonShares: ptr(1000000), Amount: ptr(71200000000), ReferencePrice: ptr(71200), BoardDate: date(2025, 7, 3), MarketCap: ptr(4000000000000)},
+				{CorpCode: "10000002", ReceiptNumber: "20250318000002", Event: models.TreasuryStockAcquisition, CommonShares: ptr(10), Amount: ptr(1000), BoardDate: date(2025, 3, 18)},
+			}
+			for i := range events {
+				Expect(gorm.G[models.TreasuryStockEvent](dbConn).Create(ctx, &events[i])).To(Succeed())
+			}
+		})
+
+		It("returns events and cumulative authorizations against the latest market cap", func() {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/companies/10000001/treasury-stock", nil)
+			resp := httptest.NewRecorder()
+
+			router.ServeHTTP(resp, req)
+
+			Expect(resp.Code).To(Equal(http.StatusOK))
+
+			var body struct {
+				Summary controllers.TreasuryStockSummary         `json:"summary"`
+				Events  []controllers.TreasuryStockEventResponse `json:"events"`
+			}
+			Expect(json.Unmarshal(resp.Body.Bytes(), &body)).To(Succeed())
+			Expect(body.Events).To(HaveLen(3))
+			Expect(body.Events[0].Event).To(Equal(models.TreasuryStockCancellation))
+			Expect(*body.Events[0].BoardDate).To(Equal("2025-07-03"))
+
+			Expect(body.Summary.AuthorizedAmount).To(Equal(int64(80000000000)))
+			Expect(body.Summary.AuthorizedShares).To(Equal(int64(1000000)))
+			Expect(body.Summary.CancelledShares).To(Equal(int64(1000000)))
+			Expect(*body.Summary.MarketCap).To(Equal(int64(4000000000000)))
+			Expect(*body.Summary.AuthorizedToMarketCap).To(Equal(2.0))
+		})
+
+		It("leaves a corrected decision out of the events and the totals", func() {
+			ctx := context.Background()
+			original := "20250318000001"
+			Expect(gorm.G[models.RawReport](dbConn).Create(ctx, &models.RawReport{ReceiptNumber: original, CorpCode: "10000001", ReportName: "주요사항보고서(자기주식취득결정)", JSONData: json.RawMessage(`{}`)})).To(Succeed())
+			Expect(gorm.G[models.RawReport](dbConn).Create(ctx, &models.RawReport{ReceiptNumber: "20250320000001", CorpCode: "10000001", ReportName: "[기재정정]주요사항보고서(자기주식취득결정)", CorrectsReceiptNumber: &original, JSONData: json.RawMessage(`{}`)})).To(Succeed())
+			amount := int64(80000000000)
+			Expect(gorm.G[models.TreasuryStockEvent](dbConn).Create(ctx, &models.TreasuryStockEvent{CorpCode: "10000001", ReceiptNumber: "20250320000001", Event: models.TreasuryStockAcquisition, Amount: &amount})).To(Succeed())
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/companies/10000001/treasury-stock", nil)
+			resp := httptest.NewRecorder()
+
+			router.ServeHTTP(resp, req)
+
+			Expect(resp.Code).To(Equal(http.StatusOK))
+
+			var body struct {
+				Summary controllers.TreasuryStockSummary         `json:"summary"`
+				Events  []controllers.TreasuryStockEventResponse `json:"events"`
+			}
+			Expect(json.Unmarshal(resp.Body.Bytes(), &body)).To(Succeed())
+			Expect(body.Events).To(HaveLen(3))
+			for _, event := range body.Events {
+				Expect(event.ReceiptNumber).NotTo(Equal(original))
+			}
+			Expect(body.Summary.AuthorizedAmount).To(Equal(int64(90000000000)))
+		})
+
+		It("filters events by start date", func() {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/companies/10000001/treasury-stock?start_date=2025-04-01", nil)
+			resp := httptest.NewRecorder()
+
+			router.ServeHTTP(resp, req)
+
+			Expect(resp.Code).To(Equal(http.StatusOK))
+
+			var body struct {
+				Summary controllers.TreasuryStockSummary         `json:"summary"`
+				Events  []controllers.TreasuryStockEventResponse `json:"events"`
+			}
+			Expect(json.Unmarshal(resp.Body.Bytes(), &body)).To(Succeed())
+			Expect(body.Events).To(HaveLen(2))
+			Expect(body.Summary.AuthorizedAmount).To(Equal(int64(10000000000)))
+		})
+	})
 })
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS filing_states;
\ No newline at end of file
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS filing_states (
+  id              BIGSERIAL PRIMARY KEY,
+  receipt_number  VARCHAR(64) UNIQUE NOT NULL,
+  corp_code       VARCHAR(64) NOT NULL,
+  report_name     VARCHAR(1024),
+  state           VARCHAR(16) NOT NULL,
+  previous_state  VARCHAR(16),
+  failure_reason  TEXT,
+  analysis        JSONB,
+  used_tokens     BIGINT NOT NULL DEFAULT 0,
+  attempts        INTEGER NOT NULL DEFAULT 0,
+  created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_filing_states_state ON filing_states (state);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS financial_facts (
+  id              BIGSERIAL PRIMARY KEY,
+  corp_code       VARCHAR(64) NOT NULL,
+  period_end      DATE NOT NULL,
+  period_type     VARCHAR(8) NOT NULL,
+  statement       VARCHAR(8) NOT NULL,
+  account         VARCHAR(255) NOT NULL,
+  consolidated    BOOLEAN NOT NULL,
+  value           BIGINT NOT NULL,
+  unit            VARCHAR(16) NOT NULL DEFAULT 'KRW',
+  receipt_number  VARCHAR(64) NOT NULL,
+  source          VARCHAR(16) NOT NULL,
+  created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX idx_financial_facts_unique ON financial_facts (corp_code, period_end, period_type, statement, account, consolidated, source);
+CREATE INDEX idx_financial_facts_corp_code_account ON financial_facts (corp_code, account, period_end);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS dividends (
+  id                   BIGSERIAL PRIMARY KEY,
+  corp_code            VARCHAR(64) NOT NULL,
+  year                 INTEGER NOT NULL,
+  stock_kind           VARCHAR(32) NOT NULL,
+  dividend_per_share   BIGINT,
+  dividend_yield       NUMERIC(10, 2),
+  payout_ratio         NUMERIC(10, 2),
+  eps                  BIGINT,
+  total_cash_dividend  BIGINT,
+  settlement_date      DATE,
+  source_year          INTEGER NOT NULL,
+  receipt_number       VARCHAR(64) NOT NULL,
+  created_at           TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at           TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX idx_dividends_corp_code_year_stock_kind ON dividends (corp_code, year, stock_kind);
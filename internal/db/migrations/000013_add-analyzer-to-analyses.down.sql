@@ -0,0 +1,2 @@
+ALTER TABLE filing_states DROP COLUMN IF EXISTS analyzer;
+ALTER TABLE analyses DROP COLUMN IF EXISTS analyzer;
@@ -0,0 +1,2 @@
+ALTER TABLE analyses ADD COLUMN analyzer VARCHAR(32) NOT NULL DEFAULT 'openai';
+ALTER TABLE filing_states ADD COLUMN analyzer VARCHAR(32);
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS insider_transactions (
+  id              BIGSERIAL PRIMARY KEY,
+  corp_code       VARCHAR(64) NOT NULL,
+  receipt_number  VARCHAR(64) NOT NULL,
+  seq             INTEGER NOT NULL,
+  holder_name     VARCHAR(255) NOT NULL,
+  relation        VARCHAR(255) NOT NULL DEFAULT '',
+  change_date     DATE NOT NULL,
+  reason          VARCHAR(255) NOT NULL DEFAULT '',
+  share_kind      VARCHAR(128) NOT NULL DEFAULT '',
+  shares_before   BIGINT,
+  shares_change   BIGINT NOT NULL,
+  shares_after    BIGINT,
+  price           BIGINT,
+  created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX idx_insider_transactions_receipt_number_seq ON insider_transactions (receipt_number, seq);
+CREATE INDEX idx_insider_transactions_corp_code_change_date ON insider_transactions (corp_code, change_date);
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS treasury_stock_events (
+  id                  BIGSERIAL PRIMARY KEY,
+  corp_code           VARCHAR(64) NOT NULL,
+  receipt_number      VARCHAR(64) NOT NULL,
+  event               VARCHAR(32) NOT NULL,
+  common_shares       BIGINT,
+  other_shares        BIGINT,
+  amount              BIGINT,
+  reference_price     BIGINT,
+  period_from         DATE,
+  period_to           DATE,
+  purpose             TEXT NOT NULL DEFAULT '',
+  method              TEXT NOT NULL DEFAULT '',
+  board_date          DATE,
+  outstanding_shares  BIGINT,
+  market_cap          BIGINT,
+  created_at          TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at          TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX idx_treasury_stock_events_receipt_number ON treasury_stock_events (receipt_number);
+CREATE INDEX idx_treasury_stock_events_corp_code ON treasury_stock_events (corp_code);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_raw_reports_corrects_receipt_number;
+
+ALTER TABLE raw_reports DROP COLUMN IF EXISTS correction_diff;
+ALTER TABLE raw_reports DROP COLUMN IF EXISTS corrects_receipt_number;
@@ -0,0 +1,4 @@
+ALTER TABLE raw_reports ADD COLUMN corrects_receipt_number VARCHAR(64);
+ALTER TABLE raw_reports ADD COLUMN correction_diff JSONB;
+
+CREATE INDEX idx_raw_reports_corrects_receipt_number ON raw_reports (corrects_receipt_number);
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_companies_stock_code;
+
+ALTER TABLE companies DROP COLUMN IF EXISTS info_updated_at;
+ALTER TABLE companies DROP COLUMN IF EXISTS address;
+ALTER TABLE companies DROP COLUMN IF EXISTS fiscal_month;
+ALTER TABLE companies DROP COLUMN IF EXISTS homepage;
+ALTER TABLE companies DROP COLUMN IF EXISTS industry_code;
+ALTER TABLE companies DROP COLUMN IF EXISTS ceo_name;
+ALTER TABLE companies DROP COLUMN IF EXISTS stock_code;
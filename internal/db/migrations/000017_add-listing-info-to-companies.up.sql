@@ -0,0 +1,9 @@
+ALTER TABLE companies ADD COLUMN stock_code VARCHAR(16) NOT NULL DEFAULT '';
+ALTER TABLE companies ADD COLUMN ceo_name VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE companies ADD COLUMN industry_code VARCHAR(16) NOT NULL DEFAULT '';
+ALTER TABLE companies ADD COLUMN homepage VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE companies ADD COLUMN fiscal_month VARCHAR(2) NOT NULL DEFAULT '';
+ALTER TABLE companies ADD COLUMN address TEXT NOT NULL DEFAULT '';
+ALTER TABLE companies ADD COLUMN info_updated_at TIMESTAMPTZ;
+
+CREATE INDEX idx_companies_stock_code ON companies (stock_code);
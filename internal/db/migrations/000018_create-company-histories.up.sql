@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS company_histories (
+  id          BIGSERIAL PRIMARY KEY,
+  corp_code   VARCHAR(64) NOT NULL,
+  field       VARCHAR(32) NOT NULL,
+  old_value   TEXT NOT NULL DEFAULT '',
+  new_value   TEXT NOT NULL DEFAULT '',
+  created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_company_histories_corp_code ON company_histories (corp_code, created_at);
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS dart_quotas (
+  day         DATE PRIMARY KEY,
+  calls       INTEGER NOT NULL DEFAULT 0,
+  exhausted   BOOLEAN NOT NULL DEFAULT false,
+  created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
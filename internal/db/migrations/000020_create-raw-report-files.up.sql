@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS raw_report_files (
+  id              BIGSERIAL PRIMARY KEY,
+  receipt_number  VARCHAR(64) NOT NULL,
+  filename        VARCHAR(255) NOT NULL,
+  main            BOOLEAN NOT NULL DEFAULT false,
+  blob_data       BYTEA,
+  blob_size       INTEGER NOT NULL DEFAULT 0,
+  json_data       JSONB,
+  created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (receipt_number, filename)
+);
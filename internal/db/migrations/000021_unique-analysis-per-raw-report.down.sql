@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_analyses_raw_report_id;
@@ -0,0 +1,5 @@
+-- a filing retried after its analysis was written could store a second one, the latest is kept
+DELETE FROM analyses a USING analyses b
+WHERE a.raw_report_id = b.raw_report_id AND a.id < b.id;
+
+CREATE UNIQUE INDEX idx_analyses_raw_report_id ON analyses (raw_report_id);
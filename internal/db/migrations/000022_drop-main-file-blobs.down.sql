@@ -0,0 +1,3 @@
+UPDATE raw_report_files f SET blob_data = r.blob_data, json_data = r.json_data
+FROM raw_reports r
+WHERE f.main AND f.receipt_number = r.receipt_number;
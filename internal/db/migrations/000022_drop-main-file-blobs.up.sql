@@ -0,0 +1,2 @@
+-- the main document is kept on raw_reports, its file only lists it
+UPDATE raw_report_files SET blob_data = NULL, json_data = NULL WHERE main;
@@ -5,10 +5,17 @@ import (
 	"time"
 )
 
+// Analyzers that produce an analysis
+const (
+	AnalyzerOpenAI = "openai" // generic analysis by the LLM
+	AnalyzerParser = "parser" // typed output of a dedicated DART parser, costs no tokens
+)
+
 type Analysis struct {
 	ID          uint `gorm:"primaryKey"`
 	RawReportID uint
 	UsedTokens  int64
+	Analyzer    string          `gorm:"default:openai"`
 	Analysis    json.RawMessage `gorm:"type:jsonb"`
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
@@ -7,8 +7,25 @@ type Company struct {
 	CorpCode         string
 	CorpName         string
 	CorpEngName      string
+	StockCode        string // empty for unlisted companies
 	LastModifiedDate time.Time
 	Category         string // Y: Kospi, K: Kosdaq, N: Konex, E: etc
+	CEOName          string `gorm:"column:ceo_name"`
+	IndustryCode     string
+	Homepage         string
+	FiscalMonth      string // MM
+	Address          string
+	InfoUpdatedAt    *time.Time // last sync from company.json
 	CreatedAt        time.Time
 	UpdatedAt        time.Time
 }
+
+// CompanyHistory records a change of a company's name, stock code or classification found by the sync
+type CompanyHistory struct {
+	ID        uint `gorm:"primaryKey"`
+	CorpCode  string
+	Field     string
+	OldValue  string
+	NewValue  string
+	CreatedAt time.Time
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// DartQuota counts the DART calls of a day in KST, shared by every worker using the key
+type DartQuota struct {
+	Day       time.Time `gorm:"primaryKey;type:date"`
+	Calls     int
+	Exhausted bool // DART answered 020, no more calls until the next day
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+type Dividend struct {
+	ID                uint `gorm:"primaryKey"`
+	CorpCode          string
+	Year              int
+	StockKind         string     // 보통주, 우선주
+	DividendPerShare  *int64     // KRW
+	DividendYield     *float64   // %
+	PayoutRatio       *float64   // %
+	EPS               *int64     `gorm:"column:eps"` // KRW
+	TotalCashDividend *int64     // million KRW
+	SettlementDate    *time.Time `gorm:"type:date"`
+	SourceYear        int        // business year of the report the values come from
+	ReceiptNumber     string
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Filing states, in the order a filing moves through them
+const (
+	FilingStateFetched  = "fetched"  // document downloaded and stored as a raw report
+	FilingStateParsed   = "parsed"   // document converted to JSON
+	FilingStateAnalyzed = "analyzed" // analysis received and kept in Analysis
+	FilingStateStored   = "stored"   // analysis stored in analyses
+	FilingStateFailed   = "failed"   // see FailureReason, resumes from PreviousState
+)
+
+type FilingState struct {
+	ID            uint `gorm:"primaryKey"`
+	ReceiptNumber string
+	CorpCode      string
+	ReportName    string
+	State         string
+	PreviousState string
+	FailureReason string
+	Analysis      json.RawMessage `gorm:"type:jsonb"`
+	UsedTokens    int64
+	Analyzer      string
+	Attempts      int
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
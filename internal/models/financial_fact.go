@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Financial fact sources, in order of preference when the same fact has several
+const (
+	FinancialFactSourceDartAPI     = "dart_api"     // DART OpenAPI financial statements
+	FinancialFactSourceXBRL        = "xbrl"         // XBRL instance document
+	FinancialFactSourceReportTable = "report_table" // read from the statement tables of a periodic report
+	FinancialFactSourceLLM         = "llm"          // extracted by the analysis of a periodic report
+)
+
+// Statements a financial fact belongs to
+const (
+	StatementBalanceSheet    = "BS"
+	StatementIncomeStatement = "IS"
+	StatementCashFlow        = "CF"
+)
+
+// Period types, income statement facts are cumulative from the start of the fiscal year
+const (
+	PeriodTypeQ1 = "Q1"
+	PeriodTypeH1 = "H1"
+	PeriodTypeQ3 = "Q3"
+	PeriodTypeFY = "FY"
+)
+
+type FinancialFact struct {
+	ID            uint `gorm:"primaryKey"`
+	CorpCode      string
+	PeriodEnd     time.Time `gorm:"type:date"`
+	PeriodType    string
+	Statement     string
+	Account       string
+	Consolidated  bool
+	Value         int64
+	Unit          string
+	ReceiptNumber string
+	Source        string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// InsiderTransaction is a change of holdings reported by an insider or a major holder,
+// one row per row of the filing's 세부변동내역
+type InsiderTransaction struct {
+	ID            uint `gorm:"primaryKey"`
+	CorpCode      string
+	ReceiptNumber string
+	Seq           int // position in the filing
+	HolderName    string
+	Relation      string
+	ChangeDate    time.Time `gorm:"type:date"`
+	Reason        string    // 장내매수(+), 장외매도(-) 등
+	ShareKind     string
+	SharesBefore  *int64
+	SharesChange  int64 // negative for disposals
+	SharesAfter   *int64
+	Price         *int64 // KRW per share
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
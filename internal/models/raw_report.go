@@ -13,6 +13,26 @@ type RawReport struct {
 	BlobData      []byte
 	BlobSize      int
 	JSONData      json.RawMessage `gorm:"type:jsonb"`
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
+
+	// set on a correction ([기재정정] etc.), the filing it corrects and what changed in the analysis
+	CorrectsReceiptNumber *string
+	CorrectionDiff        json.RawMessage `gorm:"type:jsonb"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// RawReportFile is a file of the document archive of a filing, the main document or one of its attachments.
+// The main document is kept on the RawReport, its file only has its name and size.
+type RawReportFile struct {
+	ID            uint `gorm:"primaryKey"`
+	ReceiptNumber string
+	Filename      string
+	Main          bool
+	BlobData      []byte
+	BlobSize      int
+	JSONData      json.RawMessage `gorm:"type:jsonb"` // the attachment parsed on its own
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
 }
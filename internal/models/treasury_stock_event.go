@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Treasury stock events, see dart.TreasuryStockDoc
+const (
+	TreasuryStockAcquisition  = "acquisition"
+	TreasuryStockDisposal     = "disposal"
+	TreasuryStockCancellation = "cancellation"
+	TreasuryStockTrust        = "trust_contract"
+)
+
+// TreasuryStockEvent is a treasury stock decision of a company
+type TreasuryStockEvent struct {
+	ID                uint `gorm:"primaryKey"`
+	CorpCode          string
+	ReceiptNumber     string
+	Event             string
+	CommonShares      *int64
+	OtherShares       *int64
+	Amount            *int64     // KRW
+	ReferencePrice    *int64     // KRW per share the decision is based on
+	PeriodFrom        *time.Time `gorm:"type:date"`
+	PeriodTo          *time.Time `gorm:"type:date"`
+	Purpose           string
+	Method            string
+	BoardDate         *time.Time `gorm:"type:date"`
+	OutstandingShares *int64     // from the latest periodic report at the time of the decision
+	MarketCap         *int64     // KRW, outstanding shares at the reference price
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
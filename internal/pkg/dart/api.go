@@ -3,10 +3,12 @@ package dart
 import (
 	"crypto/tls"
 	"encoding/json"
-	"fmt"
 	"net/http"
 	"net/url"
+	"sort"
 	"time"
+
+	"golang.org/x/time/rate"
 )
 
 type ReportType string
@@ -34,14 +36,28 @@ type GetAlotmentItem struct {
 	StlmDt   string `json:"stlm_dt"`
 }
 
+// YearlyDividend is the dividend of one fiscal year and stock kind.
+// Payout ratio, EPS and the total cash dividend are per company and repeated for every stock kind.
+type YearlyDividend struct {
+	Year              int        `json:"year"`
+	StockKind         string     `json:"stock_kind"`          // 보통주, 우선주
+	DividendPerShare  *int64     `json:"dividend_per_share"`  // 원
+	DividendYield     *float64   `json:"dividend_yield"`      // %
+	PayoutRatio       *float64   `json:"payout_ratio"`        // %, 연결 우선
+	EPS               *int64     `json:"eps"`                 // 원, 연결 우선
+	TotalCashDividend *int64     `json:"total_cash_dividend"` // 백만원
+	SettlementDate    *time.Time `json:"settlement_date"`     // only known for the business year
+	ReceiptNumber     string     `json:"receipt_number"`
+}
+
 // 배당에 관한 사항 개발가이드
 // https://opendart.fss.or.kr/guide/detail.do?apiGrpCd=DS002&apiId=2019005
-func (c *DartClient) getAlotment(corpCode, bisnsYear string, reportCode ReportType) ([]GetAlotmentItem, error) {
+func (c *DartClient) GetDividends(corpCode, bsnsYear string, reportCode ReportType) ([]GetAlotmentItem, error) {
 	u, _ := url.Parse(baseURL + "/alotMatter.json")
 	q := u.Query()
 	q.Set("crtfc_key", c.key)               // API Key
 	q.Set("corp_code", corpCode)            // 8자리 기업코드(예: 삼성전자 00126380)
-	q.Set("bsns_year", bisnsYear)           // 사업연도
+	q.Set("bsns_year", bsnsYear)            // 사업연도
 	q.Set("reprt_code", string(reportCode)) // 보고서 코드
 
 	u.RawQuery = q.Encode()
@@ -57,40 +73,240 @@ func (c *DartClient) getAlotment(corpCode, bisnsYear string, reportCode ReportTy
 		return nil, err
 	}
 
-	if out.Status != "000" { // 000: 정상
-		return nil, fmt.Errorf("DART error %s: %s", out.Status, out.Message)
+	if err := statusError(out.Status, out.Message); err != nil {
+		return nil, err
+	}
+
+	return out.List, nil
+}
+
+// DividendsByYear turns the rows of GetDividends for bsnsYear into one dividend per year and stock kind.
+// Every row carries the business year (thstrm) and the two years before it (frmtrm, lwfr).
+func DividendsByYear(items []GetAlotmentItem, bsnsYear int) []YearlyDividend {
+	type key struct {
+		year      int
+		stockKind string
+	}
+
+	dividends := map[key]*YearlyDividend{}
+	order := []key{}
+	get := func(year int, stockKind string) *YearlyDividend {
+		k := key{year, stockKind}
+		if d, ok := dividends[k]; ok {
+			return d
+		}
+		d := &YearlyDividend{Year: year, StockKind: stockKind}
+		dividends[k] = d
+		order = append(order, k)
+		return d
+	}
+
+	type companyValues struct {
+		payoutRatio, separatePayoutRatio *float64
+		eps, separateEPS, total          *int64
+	}
+	company := map[int]*companyValues{}
+	receiptNumber := ""
+	var settlementDate *time.Time
+
+	for _, item := range items {
+		if receiptNumber == "" {
+			receiptNumber = item.RceptNo
+		}
+		if settlementDate == nil {
+			settlementDate = toDatePtr(item.StlmDt)
+		}
+
+		values := map[int]string{bsnsYear: item.Thstrm, bsnsYear - 1: item.Frmtrm, bsnsYear - 2: item.Lwfr}
+		for year, value := range values {
+			if company[year] == nil {
+				company[year] = &companyValues{}
+			}
+			cv := company[year]
+
+			switch norm(item.Se) {
+			case "주당현금배당금원":
+				get(year, item.StockKnd).DividendPerShare = toInt64Ptr(value)
+			case "현금배당수익률%":
+				get(year, item.StockKnd).DividendYield = toFloat64Ptr(value)
+			case "연결현금배당성향%":
+				cv.payoutRatio = toFloat64Ptr(value)
+			case "별도현금배당성향%", "현금배당성향%":
+				cv.separatePayoutRatio = toFloat64Ptr(value)
+			case "연결주당순이익원":
+				cv.eps = toInt64Ptr(value)
+			case "별도주당순이익원", "주당순이익원":
+				cv.separateEPS = toInt64Ptr(value)
+			case "현금배당금총액백만원":
+				cv.total = toInt64Ptr(value)
+			}
+		}
+	}
+
+	out := []YearlyDividend{}
+	for _, k := range order {
+		d := dividends[k]
+		d.ReceiptNumber = receiptNumber
+		if d.Year == bsnsYear {
+			d.SettlementDate = settlementDate
+		}
+
+		if cv := company[d.Year]; cv != nil {
+			d.PayoutRatio = cv.payoutRatio
+			if d.PayoutRatio == nil {
+				d.PayoutRatio = cv.separatePayoutRatio
+			}
+			d.EPS = cv.eps
+			if d.EPS == nil {
+				d.EPS = cv.separateEPS
+			}
+			d.TotalCashDividend = cv.total
+		}
+
+		out = append(out, *d)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Year != out[j].Year {
+			return out[i].Year > out[j].Year
+		}
+		return out[i].StockKind < out[j].StockKind
+	})
+
+	return out
+}
+
+type StockTotalReport struct {
+	Status  string           `json:"status"`
+	Message string           `json:"message"`
+	List    []StockTotalItem `json:"list"`
+}
+
+// StockTotalItem is a row of 주식의 총수 현황, one per stock kind and one for their sum
+type StockTotalItem struct {
+	RceptNo      string `json:"rcept_no"`
+	CorpCode     string `json:"corp_code"`
+	CorpName     string `json:"corp_name"`
+	Se           string `json:"se"`             // 보통주, 우선주, 합계
+	IstcTotqy    string `json:"istc_totqy"`     // 발행주식의 총수
+	TesstkCo     string `json:"tesstk_co"`      // 자기주식수
+	DistbStockCo string `json:"distb_stock_co"` // 유통주식수
+	StlmDt       string `json:"stlm_dt"`        // 결산기준일
+}
+
+// 주식의 총수 현황 개발가이드
+// https://opendart.fss.or.kr/guide/detail.do?apiGrpCd=DS002&apiId=2020002
+func (c *DartClient) GetStockTotals(corpCode, bsnsYear string, reportCode ReportType) ([]StockTotalItem, error) {
+	u, _ := url.Parse(baseURL + "/stockTotqySttus.json")
+	q := u.Query()
+	q.Set("crtfc_key", c.key)               // API Key
+	q.Set("corp_code", corpCode)            // 8자리 기업코드(예: 삼성전자 00126380)
+	q.Set("bsns_year", bsnsYear)            // 사업연도
+	q.Set("reprt_code", string(reportCode)) // 보고서 코드
+
+	u.RawQuery = q.Encode()
+
+	resp, err := c.client.Get(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var out StockTotalReport
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, err
+	}
+
+	if err := statusError(out.Status, out.Message); err != nil {
+		return nil, err
 	}
 
 	return out.List, nil
 }
 
+// Where the share count of a filing was read from
+const (
+	ShareSourceReport  = "periodic_report" // the analysis of the company's periodic report
+	ShareSourceDartAPI = "dart_api"        // 주식의 총수 현황 of the DART API
+)
+
+// ShareCount is the common shares a company had outstanding at a date
+type ShareCount struct {
+	Outstanding   int64     `json:"outstanding"`
+	AsOf          time.Time `json:"as_of"`
+	Source        string    `json:"source"`
+	ReceiptNumber string    `json:"receipt_number"` // the periodic report the count comes from
+}
+
+// CommonShareCount returns the outstanding common shares of the rows of GetStockTotals, the shares issued
+// when DART does not tell the shares in circulation
+func CommonShareCount(items []StockTotalItem) (*ShareCount, bool) {
+	for _, item := range items {
+		if norm(item.Se) != "보통주" {
+			continue
+		}
+
+		outstanding := toInt64Ptr(item.DistbStockCo)
+		if outstanding == nil || *outstanding <= 0 {
+			outstanding = toInt64Ptr(item.IstcTotqy)
+		}
+		asOf := toDatePtr(item.StlmDt)
+		if outstanding == nil || *outstanding <= 0 || asOf == nil {
+			return nil, false
+		}
+
+		return &ShareCount{
+			Outstanding:   *outstanding,
+			AsOf:          *asOf,
+			Source:        ShareSourceDartAPI,
+			ReceiptNumber: item.RceptNo,
+		}, true
+	}
+	return nil, false
+}
+
 func New(apiKey string) *DartClient {
 	return &DartClient{
 		key: apiKey,
 		client: &http.Client{
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{
-					// DART는 TLS1.2 호환이 확실 — TLS1.2로 고정해서 협상 단순화
-					MinVersion: tls.VersionTLS12,
-					MaxVersion: tls.VersionTLS12,
-
-					// SNI를 명시 (보통 자동이지만, 명시로 문제 회피)
-					ServerName: "opendart.fss.or.kr",
-
-					// 일부 구형 서버 대비 호환 암호군 지정 (필요 시)
-					CipherSuites: []uint16{
-						tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
-						tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
-						tls.TLS_RSA_WITH_AES_128_GCM_SHA256,
-						tls.TLS_RSA_WITH_AES_256_GCM_SHA384,
+			Transport: &Transport{
+				Base: &http.Transport{
+					TLSClientConfig: &tls.Config{
+						// DART는 TLS1.2 호환이 확실 — TLS1.2로 고정해서 협상 단순화
+						MinVersion: tls.VersionTLS12,
+						MaxVersion: tls.VersionTLS12,
+
+						// SNI를 명시 (보통 자동이지만, 명시로 문제 회피)
+						ServerName: "opendart.fss.or.kr",
+
+						// 일부 구형 서버 대비 호환 암호군 지정 (필요 시)
+						CipherSuites: []uint16{
+							tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+							tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+							tls.TLS_RSA_WITH_AES_128_GCM_SHA256,
+							tls.TLS_RSA_WITH_AES_256_GCM_SHA384,
+						},
 					},
 				},
+				// DART blocks keys calling more than 1,000 times a minute
+				Limiter:    rate.NewLimiter(rate.Every(100*time.Millisecond), 5),
+				DailyLimit: DefaultDailyLimit,
+				MaxRetries: 3,
+				Backoff:    time.Second,
 			},
 			Timeout: 20 * time.Second,
 		},
 	}
 }
 
+// UseQuota counts every call of the client against dailyLimit calls a day in store
+func (c *DartClient) UseQuota(store QuotaStore, dailyLimit int) {
+	if t, ok := c.client.Transport.(*Transport); ok {
+		t.Quota = store
+		t.DailyLimit = dailyLimit
+	}
+}
+
 // only for testing
 func (c *DartClient) UseDefaultClient() {
 	c.client = http.DefaultClient
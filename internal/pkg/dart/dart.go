@@ -48,12 +48,30 @@ type Company struct {
 	CorpCode    string `xml:"corp_code"`
 	CorpName    string `xml:"corp_name"`
 	CorpEngName string `xml:"corp_eng_name"`
+	StockCode   string `xml:"stock_code"` // 6자리 종목코드, 비상장은 공백
 	ModifyDate  string `xml:"modify_date"`
 }
 
-type CorpCodeXML struct {
-	XMLName   xml.Name  `xml:"result"`
-	Companies []Company `xml:"list"`
+// CompanyInfo is the response of company.json
+type CompanyInfo struct {
+	Status       string `json:"status"`
+	Message      string `json:"message"`
+	CorpName     string `json:"corp_name"`
+	CorpNameEng  string `json:"corp_name_eng"`
+	StockName    string `json:"stock_name"`
+	StockCode    string `json:"stock_code"`
+	CEOName      string `json:"ceo_nm"`
+	CorpCls      string `json:"corp_cls"` // Y: 유가, K: 코스닥, N: 코넥스, E: 기타
+	JurirNo      string `json:"jurir_no"` // 법인등록번호
+	BizrNo       string `json:"bizr_no"`  // 사업자등록번호
+	Address      string `json:"adres"`
+	Homepage     string `json:"hm_url"`
+	IRURL        string `json:"ir_url"`
+	PhoneNo      string `json:"phn_no"`
+	FaxNo        string `json:"fax_no"`
+	IndustryCode string `json:"induty_code"`
+	EstDate      string `json:"est_dt"`
+	AccMonth     string `json:"acc_mt"` // 결산월(MM)
 }
 
 type PageInfo struct {
@@ -61,9 +79,6 @@ type PageInfo struct {
 	EndDate   time.Time
 }
 
-// defined error that document not found
-var ErrDocumentNotFound = errors.New("document not found")
-
 // 공시 목록 조회
 // https://opendart.fss.or.kr/guide/detail.do?apiGrpCd=DS001&apiId=2019001
 func (c *DartClient) getDisclosureList(corpCode, bgnDe, endDe string, pageNo, pageCount int) (*ListResp, error) {
@@ -104,124 +119,132 @@ func (c *DartClient) getDisclosureList(corpCode, bgnDe, endDe string, pageNo, pa
 		return nil, err
 	}
 
-	if out.Status != "000" { // 000: 정상
-		return nil, fmt.Errorf("DART error %s: %s", out.Status, out.Message)
+	if err := statusError(out.Status, out.Message); err != nil {
+		return nil, err
 	}
 
 	return &out, nil
 }
 
-// 공시서류원본파일
-// https://opendart.fss.or.kr/guide/detail.do?apiGrpCd=DS001&apiId=2019003
-func (c *DartClient) GetDocument(rceptNo string) (string, error) {
-	u, _ := url.Parse(baseURL + "/document.xml")
-	q := u.Query()
-	q.Set("crtfc_key", c.key)  // API Key
-	q.Set("rcept_no", rceptNo) // 접수번호
+func (c *DartClient) GetRecentRawReports(pageInfo ...PageInfo) ([]List, error) {
+	code := ""
 
-	u.RawQuery = q.Encode()
+	var startDate string
+	var endDate string
 
-	resp, err := c.client.Get(u.String())
-	if err != nil {
-		return "", err
+	if len(pageInfo) > 0 {
+		startDate = pageInfo[0].StartDate.Format("20060102")
+		endDate = pageInfo[0].EndDate.Format("20060102")
+	} else {
+		today := time.Now()
+		startDate = today.AddDate(0, 0, -5).Format("20060102")
+		endDate = today.Format("20060102")
 	}
-	defer resp.Body.Close()
 
-	buf, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", err
-	}
+	return c.getAllDisclosurePages(code, startDate, endDate, 0)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("DART error %d: %s", resp.StatusCode, string(buf))
+// GetAllRawReports returns every disclosure filed between startDate and endDate.
+// The range is split into windows of at most three months because list.json
+// rejects longer periods. An empty corpCode searches the whole market and a
+// limit of zero or less means no limit.
+func (c *DartClient) GetAllRawReports(corpCode string, startDate, endDate time.Time, limit int) ([]List, error) {
+	if endDate.Before(startDate) {
+		return nil, fmt.Errorf("end date %s is before start date %s", endDate.Format("2006-01-02"), startDate.Format("2006-01-02"))
 	}
 
-	// check if mime type is application/zip
-	mimeType := resp.Header.Get("Content-Type")
-	if mimeType == "application/xml;charset=UTF-8" {
-		if strings.Contains(string(buf), "<status>014</status>") {
-			return "", ErrDocumentNotFound
+	var out []List
+	for _, window := range SplitDateRange(startDate, endDate) {
+		remaining := 0
+		if limit > 0 {
+			remaining = limit - len(out)
 		}
-		return string(buf), nil
-	}
 
-	zr, err := zip.NewReader(bytes.NewReader(buf), int64(len(buf)))
-	if err != nil {
-		return "", err
-	}
-
-	// SECURITY: Prevent Zip Bomb / memory exhaustion by limiting decompressed data to 100MB across all files
-	if len(zr.File) > 100 {
-		return "", fmt.Errorf("too many files in archive")
-	}
-
-	remainingBytes := int64(100 * 1024 * 1024)
-	outBuf := new(bytes.Buffer)
-	for _, f := range zr.File {
-		rc, err := f.Open()
+		list, err := c.getAllDisclosurePages(corpCode, window.StartDate.Format("20060102"), window.EndDate.Format("20060102"), remaining)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
+		out = append(out, list...)
 
-		limitReader := io.LimitReader(rc, remainingBytes+1)
-		copied, err := io.Copy(outBuf, limitReader)
-		rc.Close()
-
-		if copied > remainingBytes {
-			return "", fmt.Errorf("decompressed data exceeds maximum allowed size")
+		if limit > 0 && len(out) >= limit {
+			return out[:limit], nil
 		}
-		if err != nil {
-			return "", err
+	}
+
+	return out, nil
+}
+
+// SplitDateRange splits [startDate, endDate] into consecutive windows that
+// each span at most three months, the maximum period list.json accepts.
+func SplitDateRange(startDate, endDate time.Time) []PageInfo {
+	var windows []PageInfo
+	for start := startDate; !start.After(endDate); {
+		end := addMonths(start, 3).AddDate(0, 0, -1)
+		if end.After(endDate) {
+			end = endDate
 		}
-		remainingBytes -= copied
+		windows = append(windows, PageInfo{StartDate: start, EndDate: end})
+		start = end.AddDate(0, 0, 1)
 	}
+	return windows
+}
 
-	return outBuf.String(), nil
+// addMonths adds months to t, clamping the day to the end of the month, e.g. Nov 30 + 3 months is Feb 28.
+// time.AddDate normalizes past the end of the month instead, Nov 30 + 3 months would be Mar 2.
+func addMonths(t time.Time, months int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	lastDay := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(t.Day(), lastDay)-1)
 }
 
-func (c *DartClient) GetRecentRawReports(pageInfo ...PageInfo) ([]List, error) {
-	code := ""
+// getAllDisclosurePages follows list.json pagination until every page is read
+// or limit items are collected. A limit of zero or less means no limit.
+func (c *DartClient) getAllDisclosurePages(corpCode, startDate, endDate string, limit int) ([]List, error) {
 	size := 100
 	page := 1
 
-	var startDate string
-	var endDate string
-
-	if len(pageInfo) > 0 {
-		startDate = pageInfo[0].StartDate.Format("20060102")
-		endDate = pageInfo[0].EndDate.Format("20060102")
-	} else {
-		today := time.Now()
-		startDate = today.AddDate(0, 0, -5).Format("20060102")
-		endDate = today.Format("20060102")
-	}
-
-	log.Printf("Getting recent raw reports. Page: %d, Size: %d, StartDate: %s, EndDate: %s", page, size, startDate, endDate)
+	log.Printf("Getting raw reports. CorpCode: %s, Page: %d, Size: %d, StartDate: %s, EndDate: %s", corpCode, page, size, startDate, endDate)
 
-	res, err := c.getDisclosureList(code, startDate, endDate, page, size)
+	res, err := c.getDisclosureList(corpCode, startDate, endDate, page, size)
 	if err != nil {
 		return nil, err
 	}
 
-	for page < res.TotalPage {
-		log.Printf("Getting next page of recent raw reports. Page: %d, Size: %d, StartDate: %s, EndDate: %s", page, size, startDate, endDate)
-
+	for page < res.TotalPage && (limit <= 0 || len(res.List) < limit) {
 		page++
-		nextPageRes, err := c.getDisclosureList(code, startDate, endDate, page, size)
+		log.Printf("Getting next page of raw reports. CorpCode: %s, Page: %d, Size: %d, StartDate: %s, EndDate: %s", corpCode, page, size, startDate, endDate)
+
+		nextPageRes, err := c.getDisclosureList(corpCode, startDate, endDate, page, size)
 		if err != nil {
 			return nil, err
 		}
 		res.List = append(res.List, nextPageRes.List...)
 	}
 
+	if limit > 0 && len(res.List) > limit {
+		return res.List[:limit], nil
+	}
+
 	return res.List, nil
 }
 
-func (c *DartClient) GetAllRawReports() ([]List, error) {
-	return nil, fmt.Errorf("GetAllRawReports not implemented")
+// GetCompanies returns every company of corpCode.xml
+func (c *DartClient) GetCompanies() ([]Company, error) {
+	var companies []Company
+	err := c.EachCompany(func(company Company) error {
+		companies = append(companies, company)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return companies, nil
 }
 
-func (c *DartClient) GetCompanies() ([]Company, error) {
+// EachCompany calls fn for every company of corpCode.xml as it is decoded, so the
+// hundred thousand entries are never held in memory at once. An error of fn stops the walk.
+func (c *DartClient) EachCompany(fn func(Company) error) error {
 	fmt.Println("Getting companies")
 	u, _ := url.Parse(baseURL + "/corpCode.xml")
 	q := u.Query()
@@ -229,66 +252,124 @@ func (c *DartClient) GetCompanies() ([]Company, error) {
 	u.RawQuery = q.Encode()
 	resp, err := c.client.Get(u.String())
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer resp.Body.Close()
 
 	buf, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("DART error %d: %s", resp.StatusCode, string(buf))
+		return fmt.Errorf("DART error %d: %s", resp.StatusCode, string(buf))
+	}
+
+	// an invalid key is answered with an XML error instead of the archive
+	if err := parseErrorResponse(buf); err != nil {
+		return err
 	}
 
 	zr, err := zip.NewReader(bytes.NewReader(buf), int64(len(buf)))
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	// SECURITY: Prevent Zip Bomb / memory exhaustion by limiting decompressed data to 100MB across all files
 	if len(zr.File) > 100 {
-		return nil, fmt.Errorf("too many files in archive")
+		return fmt.Errorf("too many files in archive")
 	}
-	remainingBytes := int64(100 * 1024 * 1024)
-	outBuf := new(bytes.Buffer)
+	remaining := &cappedReader{remaining: 100 * 1024 * 1024}
 	for _, f := range zr.File {
 		rc, err := f.Open()
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		limitReader := io.LimitReader(rc, remainingBytes+1)
-		copied, err := io.Copy(outBuf, limitReader)
+		remaining.r = rc
+		err = decodeCompanies(xml.NewDecoder(remaining), fn)
 		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
 
-		if copied > remainingBytes {
-			return nil, fmt.Errorf("decompressed data exceeds maximum allowed size")
+func decodeCompanies(dec *xml.Decoder, fn func(Company) error) error {
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil
 		}
 		if err != nil {
-			return nil, err
+			return err
+		}
+
+		se, ok := tok.(xml.StartElement)
+		if !ok || se.Name.Local != "list" {
+			continue
+		}
+
+		var company Company
+		if err := dec.DecodeElement(&company, &se); err != nil {
+			return err
 		}
-		remainingBytes -= copied
+
+		// normalize a bit (optional but practical)
+		company.CorpCode = strings.TrimSpace(company.CorpCode)
+		company.CorpName = strings.TrimSpace(company.CorpName)
+		company.CorpEngName = strings.TrimSpace(company.CorpEngName)
+		company.StockCode = strings.TrimSpace(company.StockCode)
+		company.ModifyDate = strings.TrimSpace(company.ModifyDate)
+
+		if err := fn(company); err != nil {
+			return err
+		}
+	}
+}
+
+// cappedReader fails once more than remaining bytes are read across all the readers it wraps
+type cappedReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (c *cappedReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.remaining -= int64(n)
+	if c.remaining < 0 {
+		return n, fmt.Errorf("decompressed data exceeds maximum allowed size")
 	}
+	return n, err
+}
 
-	dec := xml.NewDecoder(bytes.NewReader(outBuf.Bytes()))
-	var file CorpCodeXML
+// 기업개황
+// https://opendart.fss.or.kr/guide/detail.do?apiGrpCd=DS001&apiId=2019002
+func (c *DartClient) GetCompanyInfo(corpCode string) (*CompanyInfo, error) {
+	u, _ := url.Parse(baseURL + "/company.json")
+	q := u.Query()
+	q.Set("crtfc_key", c.key)    // API Key
+	q.Set("corp_code", corpCode) // 8자리 기업코드
+	u.RawQuery = q.Encode()
+
+	resp, err := c.client.Get(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
 
-	if err := dec.Decode(&file); err != nil {
+	var out CompanyInfo
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
 		return nil, err
 	}
 
-	// normalize a bit (optional but practical)
-	for i := range file.Companies {
-		c := &file.Companies[i]
-		c.CorpCode = strings.TrimSpace(c.CorpCode)
-		c.CorpName = strings.TrimSpace(c.CorpName)
-		c.CorpEngName = strings.TrimSpace(c.CorpEngName)
-		c.ModifyDate = strings.TrimSpace(c.ModifyDate)
+	if err := statusError(out.Status, out.Message); err != nil {
+		return nil, err
 	}
 
-	return file.Companies, nil
+	return &out, nil
 }
 
 func (c *DartClient) GetList() error {
@@ -306,7 +387,7 @@ func (c *DartClient) GetList() error {
 
 	for _, it := range res.List {
 		if err := c.processDoc(it); err != nil {
-			if err == ErrDocumentNotFound {
+			if errors.Is(err, ErrDocumentNotFound) {
 				log.Printf("document not found: %s %s %s %s", it.RceptDt, it.RceptNo, it.CorpName, it.ReportNm)
 				continue
 			}
@@ -153,4 +153,430 @@ var _ = Describe("DartClient", func() {
 			Expect(list[0].ReportNm).To(Equal("분기보고서 (2025.03)"))
 		})
 	})
+
+	Describe("GetAllRawReports", func() {
+		listPayload := func(rceptNo string) string {
+			return fmt.Sprintf(`{
+				"status":"000",
+				"message":"OK",
+				"page_no":1,
+				"total_count":1,
+				"page_count":1,
+				"total_page":1,
+				"list":[
+					{
+						"rcept_no":"%s",
+						"corp_code":"00126380",
+						"corp_name":"삼성전자",
+						"report_nm":"분기보고서 (2025.03)",
+						"rcept_dt":"20250515",
+						"flr_nm":"삼성전자",
+						"rm":""
+					}
+				]
+			}`, rceptNo)
+		}
+
+		It("splits the date range into three month windows", func() {
+			testhelpers.New("https://opendart.fss.or.kr").
+				Get(fmt.Sprintf("/api/list.json?crtfc_key=%s&corp_code=00126380&bgn_de=20250101&end_de=20250331&page_no=1&page_count=100", apiKey)).
+				Reply(200).
+				BodyString(listPayload("20250301000001"))
+
+			testhelpers.New("https://opendart.fss.or.kr").
+				Get(fmt.Sprintf("/api/list.json?crtfc_key=%s&corp_code=00126380&bgn_de=20250401&end_de=20250615&page_no=1&page_count=100", apiKey)).
+				Reply(200).
+				BodyString(listPayload("20250515000002"))
+
+			startDate := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+			endDate := time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)
+			list, err := client.GetAllRawReports("00126380", startDate, endDate, 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testhelpers.IsDone()).To(BeTrue())
+
+			Expect(list).To(HaveLen(2))
+			Expect(list[0].RceptNo).To(Equal("20250301000001"))
+			Expect(list[1].RceptNo).To(Equal("20250515000002"))
+		})
+
+		It("stops fetching once the limit is reached", func() {
+			testhelpers.New("https://opendart.fss.or.kr").
+				Get(fmt.Sprintf("/api/list.json?crtfc_key=%s&corp_code=00126380&bgn_de=20250101&end_de=20250331&page_no=1&page_count=100", apiKey)).
+				Reply(200).
+				BodyString(listPayload("20250301000001"))
+
+			startDate := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+			endDate := time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)
+			list, err := client.GetAllRawReports("00126380", startDate, endDate, 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testhelpers.IsDone()).To(BeTrue())
+
+			Expect(list).To(HaveLen(1))
+			Expect(list[0].RceptNo).To(Equal("20250301000001"))
+		})
+
+		It("returns an error if the end date is before the start date", func() {
+			startDate := time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)
+			endDate := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+			_, err := client.GetAllRawReports("00126380", startDate, endDate, 0)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("SplitDateRange", func() {
+		It("keeps a short range as a single window", func() {
+			startDate := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+			endDate := time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)
+			Expect(dart.SplitDateRange(startDate, endDate)).To(Equal([]dart.PageInfo{
+				{StartDate: startDate, EndDate: endDate},
+			}))
+		})
+
+		It("splits a year into four windows", func() {
+			windows := dart.SplitDateRange(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC))
+			Expect(windows).To(HaveLen(4))
+			Expect(windows[0].EndDate).To(Equal(time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)))
+			Expect(windows[1].StartDate).To(Equal(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)))
+			Expect(windows[3].EndDate).To(Equal(time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)))
+		})
+
+		It("keeps windows starting at the end of a month within three months", func() {
+			windows := dart.SplitDateRange(time.Date(2024, 11, 30, 0, 0, 0, 0, time.UTC), time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC))
+			Expect(windows[0].EndDate).To(Equal(time.Date(2025, 2, 27, 0, 0, 0, 0, time.UTC)))
+			Expect(windows[1].StartDate).To(Equal(time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC)))
+			Expect(windows[1].EndDate).To(Equal(time.Date(2025, 5, 27, 0, 0, 0, 0, time.UTC)))
+			for _, w := range windows {
+				Expect(w.EndDate.Before(w.StartDate.AddDate(0, 3, 0))).To(BeTrue())
+			}
+		})
+	})
+
+	Describe("GetMajorAccounts", func() {
+		It("returns account rows keyed by year, report and statement division", func() {
+			testhelpers.New("https://opendart.fss.or.kr").
+				Get(fmt.Sprintf("/api/fnlttSinglAcnt.json?crtfc_key=%s&corp_code=00126380&bsns_year=2024&reprt_code=11011", apiKey)).
+				Reply(200).
+				BodyString(`{
+					"status": "000",
+					"message": "정상",
+					"list": [
+						{
+							"rcept_no": "20250311001085",
+							"bsns_year": "2024",
+							"corp_code": "00126380",
+							"stock_code": "005930",
+							"reprt_code": "11011",
+							"account_nm": "자산총계",
+							"fs_div": "CFS",
+							"fs_nm": "연결재무제표",
+							"sj_div": "BS",
+							"sj_nm": "재무상태표",
+							"thstrm_nm": "제 56 기",
+							"thstrm_dt": "2024.12.31 현재",
+							"thstrm_amount": "514,531,948,000,000",
+							"frmtrm_nm": "제 55 기",
+							"frmtrm_dt": "2023.12.31 현재",
+							"frmtrm_amount": "455,905,980,000,000",
+							"bfefrmtrm_nm": "제 54 기",
+							"bfefrmtrm_dt": "2022.12.31 현재",
+							"bfefrmtrm_amount": "448,424,507,000,000",
+							"ord": "11",
+							"currency": "KRW"
+						},
+						{
+							"rcept_no": "20250311001085",
+							"bsns_year": "2024",
+							"corp_code": "00126380",
+							"stock_code": "005930",
+							"reprt_code": "11011",
+							"account_nm": "당기순이익(손실)",
+							"fs_div": "OFS",
+							"fs_nm": "재무제표",
+							"sj_div": "IS",
+							"sj_nm": "손익계산서",
+							"thstrm_amount": "-1,234",
+							"frmtrm_amount": "-",
+							"ord": "29",
+							"currency": "KRW"
+						}
+					]
+				}`)
+
+			items, err := client.GetMajorAccounts("00126380", "2024", dart.BUSINESS_REPORT)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testhelpers.IsDone()).To(BeTrue())
+
+			Expect(items).To(HaveLen(2))
+			Expect(items[0].BsnsYear).To(Equal("2024"))
+			Expect(items[0].ReprtCode).To(Equal(dart.BUSINESS_REPORT))
+			Expect(items[0].FsDiv).To(Equal(dart.CONSOLIDATED))
+			Expect(items[0].AccountNm).To(Equal("자산총계"))
+			Expect(*items[0].CurrentAmount()).To(Equal(int64(514531948000000)))
+			Expect(*items[0].BeforePreviousAmount()).To(Equal(int64(448424507000000)))
+			Expect(items[1].FsDiv).To(Equal(dart.SEPARATE))
+			Expect(*items[1].CurrentAmount()).To(Equal(int64(-1234)))
+			Expect(items[1].PreviousAmount()).To(BeNil())
+		})
+
+		It("returns an error for a DART error status", func() {
+			testhelpers.New("https://opendart.fss.or.kr").
+				Get("/api/fnlttSinglAcnt.json").
+				Reply(200).
+				BodyString(`{"status": "013", "message": "조회된 데이타가 없습니다."}`)
+
+			_, err := client.GetMajorAccounts("00126380", "2024", dart.BUSINESS_REPORT)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("GetFullFinancialStatements", func() {
+		It("fills fs_div on every row", func() {
+			testhelpers.New("https://opendart.fss.or.kr").
+				Get(fmt.Sprintf("/api/fnlttSinglAcntAll.json?crtfc_key=%s&corp_code=00126380&bsns_year=2025&reprt_code=11012&fs_div=OFS", apiKey)).
+				Reply(200).
+				BodyString(`{
+					"status": "000",
+					"message": "정상",
+					"list": [
+						{
+							"rcept_no": "20250814003156",
+							"reprt_code": "11012",
+							"bsns_year": "2025",
+							"corp_code": "00126380",
+							"sj_div": "IS",
+							"sj_nm": "손익계산서",
+							"account_id": "ifrs-full_Revenue",
+							"account_nm": "수익(매출액)",
+							"account_detail": "-",
+							"thstrm_nm": "제 57 기 반기",
+							"thstrm_amount": "61,547,203,000,000",
+							"thstrm_add_amount": "122,360,044,000,000",
+							"ord": "1",
+							"currency": "KRW"
+						}
+					]
+				}`)
+
+			items, err := client.GetFullFinancialStatements("00126380", "2025", dart.HALF_YEAR, dart.SEPARATE)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testhelpers.IsDone()).To(BeTrue())
+
+			Expect(items).To(HaveLen(1))
+			Expect(items[0].FsDiv).To(Equal(dart.SEPARATE))
+			Expect(items[0].ReprtCode).To(Equal(dart.HALF_YEAR))
+			Expect(items[0].AccountID).To(Equal("ifrs-full_Revenue"))
+			Expect(*items[0].CurrentAmount()).To(Equal(int64(61547203000000)))
+		})
+	})
+
+	Describe("GetMultiMajorAccounts", func() {
+		It("joins the corp codes", func() {
+			testhelpers.New("https://opendart.fss.or.kr").
+				Get(fmt.Sprintf("/api/fnlttMultiAcnt.json?crtfc_key=%s&corp_code=00126380,00356361&bsns_year=2024&reprt_code=11014", apiKey)).
+				Reply(200).
+				BodyString(`{"status": "000", "message": "정상", "list": []}`)
+
+			items, err := client.GetMultiMajorAccounts([]string{"00126380", "00356361"}, "2024", dart.THIRD_QUARTER)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testhelpers.IsDone()).To(BeTrue())
+			Expect(items).To(BeEmpty())
+		})
+
+		It("rejects more than 100 corp codes", func() {
+			corpCodes := make([]string, 101)
+			_, err := client.GetMultiMajorAccounts(corpCodes, "2024", dart.THIRD_QUARTER)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("GetStockTotals", func() {
+		It("returns the outstanding common shares", func() {
+			testhelpers.New("https://opendart.fss.or.kr").
+				Get(fmt.Sprintf("/api/stockTotqySttus.json?crtfc_key=%s&corp_code=00126380&bsns_year=2024&reprt_code=11011", apiKey)).
+				Reply(200).
+				BodyString(`{
+					"status": "000",
+					"message": "정상",
+					"list": [
+						{"rcept_no": "20250311001085", "corp_cls": "Y", "corp_code": "00126380", "corp_name": "삼성전자", "se": "보통주", "istc_totqy": "5,919,637,922", "tesstk_co": "97,149,467", "distb_stock_co": "5,822,488,455", "stlm_dt": "2024-12-31"},
+						{"rcept_no": "20250311001085", "corp_cls": "Y", "corp_code": "00126380", "corp_name": "삼성전자", "se": "우선주", "istc_totqy": "815,974,664", "tesstk_co": "15,574,729", "distb_stock_co": "800,399,935", "stlm_dt": "2024-12-31"},
+						{"rcept_no": "20250311001085", "corp_cls": "Y", "corp_code": "00126380", "corp_name": "삼성전자", "se": "합계", "istc_totqy": "6,735,612,586", "tesstk_co": "112,724,196", "distb_stock_co": "6,622,888,390", "stlm_dt": "2024-12-31"}
+					]
+				}`)
+
+			items, err := client.GetStockTotals("00126380", "2024", dart.BUSINESS_REPORT)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testhelpers.IsDone()).To(BeTrue())
+
+			shares, ok := dart.CommonShareCount(items)
+			Expect(ok).To(BeTrue())
+			Expect(shares.Outstanding).To(Equal(int64(5822488455)))
+			Expect(shares.AsOf.Format("2006-01-02")).To(Equal("2024-12-31"))
+			Expect(shares.Source).To(Equal(dart.ShareSourceDartAPI))
+			Expect(shares.ReceiptNumber).To(Equal("20250311001085"))
+
+			_, ok = dart.CommonShareCount(items[1:2])
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("GetDividends", func() {
+		It("returns dividends grouped by year and stock kind", func() {
+			testhelpers.New("https://opendart.fss.or.kr").
+				Get(fmt.Sprintf("/api/alotMatter.json?crtfc_key=%s&corp_code=00126380&bsns_year=2024&reprt_code=11011", apiKey)).
+				Reply(200).
+				BodyString(`{
+					"status": "000",
+					"message": "정상",
+					"list": [
+						{"rcept_no": "20250311001085", "corp_cls": "Y", "corp_code": "00126380", "corp_name": "삼성전자", "se": "주당액면가액(원)", "thstrm": "100", "frmtrm": "100", "lwfr": "100", "stlm_dt": "2024-12-31"},
+						{"rcept_no": "20250311001085", "corp_cls": "Y", "corp_code": "00126380", "corp_name": "삼성전자", "se": "(연결)주당순이익(원)", "thstrm": "4,950", "frmtrm": "2,131", "lwfr": "8,057", "stlm_dt": "2024-12-31"},
+						{"rcept_no": "20250311001085", "corp_cls": "Y", "corp_code": "00126380", "corp_name": "삼성전자", "se": "현금배당금총액(백만원)", "thstrm": "9,810,767", "frmtrm": "9,809,438", "lwfr": "9,809,438", "stlm_dt": "2024-12-31"},
+						{"rcept_no": "20250311001085", "corp_cls": "Y", "corp_code": "00126380", "corp_name": "삼성전자", "se": "(연결)현금배당성향(%)", "thstrm": "29.20", "frmtrm": "67.80", "lwfr": "17.90", "stlm_dt": "2024-12-31"},
+						{"rcept_no": "20250311001085", "corp_cls": "Y", "corp_code": "00126380", "corp_name": "삼성전자", "se": "현금배당수익률(%)", "stock_knd": "보통주", "thstrm": "2.70", "frmtrm": "1.90", "lwfr": "2.50", "stlm_dt": "2024-12-31"},
+						{"rcept_no": "20250311001085", "corp_cls": "Y", "corp_code": "00126380", "corp_name": "삼성전자", "se": "현금배당수익률(%)", "stock_knd": "우선주", "thstrm": "3.40", "frmtrm": "2.40", "lwfr": "2.80", "stlm_dt": "2024-12-31"},
+						{"rcept_no": "20250311001085", "corp_cls": "Y", "corp_code": "00126380", "corp_name": "삼성전자", "se": "주당 현금배당금(원)", "stock_knd": "보통주", "thstrm": "1,446", "frmtrm": "1,444", "lwfr": "1,444", "stlm_dt": "2024-12-31"},
+						{"rcept_no": "20250311001085", "corp_cls": "Y", "corp_code": "00126380", "corp_name": "삼성전자", "se": "주당 현금배당금(원)", "stock_knd": "우선주", "thstrm": "1,447", "frmtrm": "1,445", "lwfr": "1,445", "stlm_dt": "2024-12-31"}
+					]
+				}`)
+
+			items, err := client.GetDividends("00126380", "2024", dart.BUSINESS_REPORT)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testhelpers.IsDone()).To(BeTrue())
+			Expect(items).To(HaveLen(8))
+
+			dividends := dart.DividendsByYear(items, 2024)
+			Expect(dividends).To(HaveLen(6))
+
+			Expect(dividends[0].Year).To(Equal(2024))
+			Expect(dividends[0].StockKind).To(Equal("보통주"))
+			Expect(*dividends[0].DividendPerShare).To(Equal(int64(1446)))
+			Expect(*dividends[0].DividendYield).To(Equal(2.7))
+			Expect(*dividends[0].PayoutRatio).To(Equal(29.2))
+			Expect(*dividends[0].EPS).To(Equal(int64(4950)))
+			Expect(*dividends[0].TotalCashDividend).To(Equal(int64(9810767)))
+			Expect(dividends[0].SettlementDate.Format("2006-01-02")).To(Equal("2024-12-31"))
+			Expect(dividends[0].ReceiptNumber).To(Equal("20250311001085"))
+
+			Expect(dividends[1].Year).To(Equal(2024))
+			Expect(dividends[1].StockKind).To(Equal("우선주"))
+			Expect(*dividends[1].DividendPerShare).To(Equal(int64(1447)))
+
+			Expect(dividends[4].Year).To(Equal(2022))
+			Expect(dividends[4].StockKind).To(Equal("보통주"))
+			Expect(*dividends[4].PayoutRatio).To(Equal(17.9))
+			Expect(dividends[4].SettlementDate).To(BeNil())
+		})
+
+		It("returns ErrNoData when DART has no dividends", func() {
+			testhelpers.New("https://opendart.fss.or.kr").
+				Get("/api/alotMatter.json").
+				Reply(200).
+				BodyString(`{"status": "013", "message": "조회된 데이타가 없습니다."}`)
+
+			_, err := client.GetDividends("00126380", "2024", dart.BUSINESS_REPORT)
+			Expect(err).To(MatchError(dart.ErrNoData))
+		})
+	})
+
+	Describe("GetCompanyInfo", func() {
+		It("returns the overview of a company", func() {
+			testhelpers.New("https://opendart.fss.or.kr").
+				Get(fmt.Sprintf("/api/company.json?crtfc_key=%s&corp_code=00126380", apiKey)).
+				Reply(200).
+				BodyString(`{
+					"status": "000",
+					"message": "정상",
+					"corp_code": "00126380",
+					"corp_name": "삼성전자(주)",
+					"corp_name_eng": "SAMSUNG ELECTRONICS CO,.LTD",
+					"stock_name": "삼성전자",
+					"stock_code": "005930",
+					"ceo_nm": "전영현, 노태문",
+					"corp_cls": "Y",
+					"jurir_no": "1301110006246",
+					"bizr_no": "1248100998",
+					"adres": "경기도 수원시 영통구  삼성로 129 (매탄동)",
+					"hm_url": "www.samsung.com/sec",
+					"ir_url": "",
+					"phn_no": "02-2255-0114",
+					"fax_no": "031-200-7538",
+					"induty_code": "264",
+					"est_dt": "19690113",
+					"acc_mt": "12"
+				}`)
+
+			info, err := client.GetCompanyInfo("00126380")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testhelpers.IsDone()).To(BeTrue())
+			Expect(info.StockCode).To(Equal("005930"))
+			Expect(info.CorpCls).To(Equal("Y"))
+			Expect(info.CEOName).To(Equal("전영현, 노태문"))
+			Expect(info.IndustryCode).To(Equal("264"))
+			Expect(info.Homepage).To(Equal("www.samsung.com/sec"))
+			Expect(info.AccMonth).To(Equal("12"))
+		})
+
+		It("returns an error for a DART error status", func() {
+			testhelpers.New("https://opendart.fss.or.kr").
+				Get("/api/company.json").
+				Reply(200).
+				BodyString(`{"status": "100", "message": "필드의 부적절한 값입니다."}`)
+
+			_, err := client.GetCompanyInfo("00000000")
+			Expect(err).To(MatchError(ContainSubstring("DART error 100")))
+		})
+	})
+
+	Describe("EachCompany", func() {
+		corpCodeXML := `<?xml version="1.0" encoding="UTF-8"?>
+<result>
+    <list>
+        <corp_code>00434003</corp_code>
+        <corp_name>다코</corp_name>
+        <corp_eng_name>Daco corporation</corp_eng_name>
+        <stock_code> </stock_code>
+        <modify_date>20170630</modify_date>
+    </list>
+    <list>
+        <corp_code>00126380</corp_code>
+        <corp_name>삼성전자</corp_name>
+        <corp_eng_name>SAMSUNG ELECTRONICS CO,.LTD</corp_eng_name>
+        <stock_code>005930</stock_code>
+        <modify_date>20250301</modify_date>
+    </list>
+</result>`
+
+		BeforeEach(func() {
+			zipDocument, err := testhelpers.CreateMockZipArchive("CORPCODE.xml", []byte(corpCodeXML))
+			Expect(err).NotTo(HaveOccurred())
+
+			testhelpers.New("https://opendart.fss.or.kr").Get("/api/corpCode.xml").Reply(200).Body(zipDocument).Header("Content-Type", "application/zip")
+		})
+
+		It("streams the companies with their stock codes", func() {
+			var companies []dart.Company
+			Expect(client.EachCompany(func(company dart.Company) error {
+				companies = append(companies, company)
+				return nil
+			})).To(Succeed())
+
+			Expect(companies).To(HaveLen(2))
+			Expect(companies[0].StockCode).To(BeEmpty())
+			Expect(companies[1].CorpCode).To(Equal("00126380"))
+			Expect(companies[1].StockCode).To(Equal("005930"))
+			Expect(companies[1].ModifyDate).To(Equal("20250301"))
+		})
+
+		It("stops at the first error of the callback", func() {
+			stop := fmt.Errorf("stop")
+			calls := 0
+			err := client.EachCompany(func(company dart.Company) error {
+				calls++
+				return stop
+			})
+			Expect(err).To(MatchError(stop))
+			Expect(calls).To(Equal(1))
+		})
+	})
 })
@@ -0,0 +1,249 @@
+package dart
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+)
+
+// SECURITY: Prevent Zip Bomb / memory exhaustion by limiting the files of an archive and their decompressed size
+const (
+	maxDocumentFiles = 100
+	maxDocumentSize  = 100 * 1024 * 1024 // across all files
+)
+
+// maxErrorResponseSize bounds what is read of a response that is not an archive
+const maxErrorResponseSize = 1024 * 1024
+
+// DocumentFile is a file of the archive of a filing
+type DocumentFile struct {
+	Name string
+	Main bool  // the filing itself, the other files are its attachments (첨부서류)
+	Size int64 // decompressed size as declared by the archive
+	open func() (io.ReadCloser, error)
+}
+
+// Document is the archive of a filing kept in a temporary file, its files are decompressed when they are opened.
+// Close removes the temporary file.
+type Document struct {
+	Files     []*DocumentFile
+	file      *os.File
+	remaining int64 // decompressed bytes left to read across all files
+}
+
+// Main returns the main document of the filing
+func (d *Document) Main() *DocumentFile {
+	for _, f := range d.Files {
+		if f.Main {
+			return f
+		}
+	}
+	return nil
+}
+
+// Attachments returns the files attached to the filing
+func (d *Document) Attachments() []*DocumentFile {
+	var files []*DocumentFile
+	for _, f := range d.Files {
+		if !f.Main {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// Open returns a reader of the decompressed file
+func (f *DocumentFile) Open() (io.ReadCloser, error) {
+	return f.open()
+}
+
+func (d *Document) Close() error {
+	if d.file == nil {
+		return nil
+	}
+	err := d.file.Close()
+	if rmErr := os.Remove(d.file.Name()); err == nil {
+		err = rmErr
+	}
+	d.file = nil
+	return err
+}
+
+// documentReader counts what is read of a file against the decompressed budget of its document
+type documentReader struct {
+	io.ReadCloser
+	doc *Document
+}
+
+func (r *documentReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.doc.remaining -= int64(n)
+	if r.doc.remaining < 0 {
+		return n, fmt.Errorf("decompressed data exceeds maximum allowed size")
+	}
+	return n, err
+}
+
+// 공시서류원본파일
+// https://opendart.fss.or.kr/guide/detail.do?apiGrpCd=DS001&apiId=2019003
+//
+// DownloadDocument writes the archive of a filing to w as it is received
+func (c *DartClient) DownloadDocument(rceptNo string, w io.Writer) error {
+	u, _ := url.Parse(baseURL + "/document.xml")
+	q := u.Query()
+	q.Set("crtfc_key", c.key)  // API Key
+	q.Set("rcept_no", rceptNo) // 접수번호
+
+	u.RawQuery = q.Encode()
+
+	resp, err := c.client.Get(u.String())
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		buf, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorResponseSize))
+		return fmt.Errorf("DART error %d: %s", resp.StatusCode, string(buf))
+	}
+
+	// documents come zipped, errors as XML
+	if strings.Contains(resp.Header.Get("Content-Type"), "xml") {
+		buf, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorResponseSize))
+		if err != nil {
+			return err
+		}
+		if err := parseErrorResponse(buf); err != nil {
+			return err
+		}
+		if _, err := w.Write(buf); err != nil {
+			return err
+		}
+	}
+
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+// GetDocumentStream downloads the archive of a filing to a temporary file and lists its files
+// without decompressing them. The caller must Close the document.
+func (c *DartClient) GetDocumentStream(rceptNo string) (*Document, error) {
+	f, err := os.CreateTemp("", "dart-"+rceptNo+"-*.zip")
+	if err != nil {
+		return nil, err
+	}
+	doc := &Document{file: f, remaining: maxDocumentSize}
+
+	if err := c.DownloadDocument(rceptNo, f); err != nil {
+		doc.Close()
+		return nil, err
+	}
+	if err := doc.index(rceptNo); err != nil {
+		doc.Close()
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// index lists the files of the archive. The main document is named after the receipt number,
+// e.g. 20250314000123.xml next to 20250314000123_00760.xml, or is the first file otherwise.
+func (d *Document) index(rceptNo string) error {
+	info, err := d.file.Stat()
+	if err != nil {
+		return err
+	}
+
+	zr, err := zip.NewReader(d.file, info.Size())
+	if errors.Is(err, zip.ErrFormat) {
+		// a document DART sent as is
+		d.Files = []*DocumentFile{{
+			Name: rceptNo + ".xml",
+			Main: true,
+			Size: info.Size(),
+			open: func() (io.ReadCloser, error) {
+				return io.NopCloser(io.NewSectionReader(d.file, 0, info.Size())), nil
+			},
+		}}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(zr.File) > maxDocumentFiles {
+		return fmt.Errorf("too many files in archive")
+	}
+
+	for _, zf := range zr.File {
+		d.Files = append(d.Files, &DocumentFile{
+			Name: zf.Name,
+			Main: strings.TrimSuffix(path.Base(zf.Name), path.Ext(zf.Name)) == rceptNo,
+			Size: int64(zf.UncompressedSize64),
+			open: func() (io.ReadCloser, error) {
+				rc, err := zf.Open()
+				if err != nil {
+					return nil, err
+				}
+				return &documentReader{ReadCloser: rc, doc: d}, nil
+			},
+		})
+	}
+
+	if d.Main() == nil && len(d.Files) > 0 {
+		d.Files[0].Main = true
+	}
+
+	return nil
+}
+
+// WriteTo writes the decompressed files one after another in archive order
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	var written int64
+	for _, f := range d.Files {
+		rc, err := f.Open()
+		if err != nil {
+			return written, err
+		}
+		n, err := io.Copy(w, rc)
+		rc.Close()
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// Reader returns the decompressed files one after another in archive order, as they are read
+func (d *Document) Reader() io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := d.WriteTo(pw)
+		pw.CloseWithError(err)
+	}()
+	return pr
+}
+
+// GetDocument returns the files of a filing concatenated in archive order
+func (c *DartClient) GetDocument(rceptNo string) (string, error) {
+	doc, err := c.GetDocumentStream(rceptNo)
+	if err != nil {
+		return "", err
+	}
+	defer doc.Close()
+
+	buf := new(bytes.Buffer)
+	if _, err := doc.WriteTo(buf); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
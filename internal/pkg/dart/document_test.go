@@ -0,0 +1,129 @@
+package dart_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+
+	"kosis/internal/pkg/dart"
+	"kosis/internal/testhelpers"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GetDocumentStream", func() {
+	var client *dart.DartClient
+
+	BeforeEach(func() {
+		testhelpers.Activate()
+
+		client = dart.New("test-dart-api-key")
+		client.UseDefaultClient()
+	})
+
+	AfterEach(func() {
+		testhelpers.Deactivate()
+	})
+
+	archive := func(files ...testhelpers.MockZipFile) []byte {
+		b, err := testhelpers.CreateMockZipArchiveFiles(files...)
+		Expect(err).NotTo(HaveOccurred())
+		return b
+	}
+
+	readFile := func(f *dart.DocumentFile) string {
+		rc, err := f.Open()
+		Expect(err).NotTo(HaveOccurred())
+		defer rc.Close()
+		b, err := io.ReadAll(rc)
+		Expect(err).NotTo(HaveOccurred())
+		return string(b)
+	}
+
+	It("returns the main document and the attachments separately", func() {
+		testhelpers.New("https://opendart.fss.or.kr").
+			Get("/api/document.xml").
+			Reply(200).
+			Header("Content-Type", "application/zip").
+			Body(archive(
+				testhelpers.MockZipFile{Name: "20250314000123_00760.xml", Body: "<DOCUMENT>감사보고서</DOCUMENT>"},
+				testhelpers.MockZipFile{Name: "20250314000123.xml", Body: "<DOCUMENT>사업보고서</DOCUMENT>"},
+			))
+
+		doc, err := client.GetDocumentStream("20250314000123")
+		Expect(err).NotTo(HaveOccurred())
+		defer doc.Close()
+
+		Expect(doc.Files).To(HaveLen(2))
+		Expect(doc.Main().Name).To(Equal("20250314000123.xml"))
+		Expect(readFile(doc.Main())).To(Equal("<DOCUMENT>사업보고서</DOCUMENT>"))
+
+		attachments := doc.Attachments()
+		Expect(attachments).To(HaveLen(1))
+		Expect(attachments[0].Name).To(Equal("20250314000123_00760.xml"))
+		Expect(readFile(attachments[0])).To(Equal("<DOCUMENT>감사보고서</DOCUMENT>"))
+	})
+
+	It("takes the first file as the main document when none is named after the receipt number", func() {
+		testhelpers.New("https://opendart.fss.or.kr").
+			Get("/api/document.xml").
+			Reply(200).
+			Header("Content-Type", "application/zip").
+			Body(archive(testhelpers.MockZipFile{Name: "document.xml", Body: "<DOCUMENT/>"}))
+
+		doc, err := client.GetDocumentStream("20250314000123")
+		Expect(err).NotTo(HaveOccurred())
+		defer doc.Close()
+
+		Expect(doc.Main().Name).To(Equal("document.xml"))
+		Expect(doc.Attachments()).To(BeEmpty())
+	})
+
+	It("writes the archive to a writer as it is received", func() {
+		body := archive(testhelpers.MockZipFile{Name: "20250314000123.xml", Body: "<DOCUMENT/>"})
+		testhelpers.New("https://opendart.fss.or.kr").
+			Get("/api/document.xml").
+			Reply(200).
+			Header("Content-Type", "application/zip").
+			Body(body)
+
+		buf := new(bytes.Buffer)
+		Expect(client.DownloadDocument("20250314000123", buf)).To(Succeed())
+		Expect(buf.Bytes()).To(Equal(body))
+	})
+
+	It("removes the temporary file on close", func() {
+		before, err := os.ReadDir(os.TempDir())
+		Expect(err).NotTo(HaveOccurred())
+
+		testhelpers.New("https://opendart.fss.or.kr").
+			Get("/api/document.xml").
+			Reply(200).
+			Header("Content-Type", "application/zip").
+			Body(archive(testhelpers.MockZipFile{Name: "20250314000123.xml", Body: "<DOCUMENT/>"}))
+
+		doc, err := client.GetDocumentStream("20250314000123")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(doc.Close()).To(Succeed())
+
+		after, err := os.ReadDir(os.TempDir())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(after).To(HaveLen(len(before)))
+	})
+
+	It("concatenates the files for GetDocument", func() {
+		testhelpers.New("https://opendart.fss.or.kr").
+			Get("/api/document.xml").
+			Reply(200).
+			Header("Content-Type", "application/zip").
+			Body(archive(
+				testhelpers.MockZipFile{Name: "20250314000123.xml", Body: "<A/>"},
+				testhelpers.MockZipFile{Name: "20250314000123_00760.xml", Body: "<B/>"},
+			))
+
+		doc, err := client.GetDocument("20250314000123")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(doc).To(Equal("<A/><B/>"))
+	})
+})
@@ -0,0 +1,92 @@
+package dart
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+)
+
+// DART status codes
+// https://opendart.fss.or.kr/guide/detail.do?apiGrpCd=DS001&apiId=2019001
+const (
+	StatusOK                   = "000" // 정상
+	StatusUnregisteredKey      = "010" // 등록되지 않은 키입니다
+	StatusUnavailableKey       = "011" // 사용할 수 없는 키입니다
+	StatusForbiddenIP          = "012" // 접근할 수 없는 IP입니다
+	StatusNoData               = "013" // 조회된 데이타가 없습니다
+	StatusFileNotFound         = "014" // 파일이 존재하지 않습니다
+	StatusRequestLimitExceeded = "020" // 요청 제한을 초과하였습니다 (일 20,000건)
+	StatusTooManyCompanies     = "021" // 조회 가능한 회사 개수가 초과하였습니다 (최대 100건)
+	StatusInvalidField         = "100" // 필드의 부적절한 값입니다
+	StatusInvalidAccess        = "101" // 부적절한 접근입니다
+	StatusMaintenance          = "800" // 시스템 점검으로 인한 서비스가 중지 중입니다
+	StatusUndefined            = "900" // 정의되지 않은 오류가 발생하였습니다
+	StatusAccountExpired       = "901" // 사용자 계정의 개인정보 보유기간이 만료되어 사용할 수 없는 키입니다
+)
+
+// DartError is a response of DART whose status is not 000
+type DartError struct {
+	Status  string
+	Message string
+}
+
+// Sentinels of the DART statuses, match them with errors.Is
+var (
+	ErrUnregisteredKey      = &DartError{Status: StatusUnregisteredKey, Message: "unregistered key"}
+	ErrUnavailableKey       = &DartError{Status: StatusUnavailableKey, Message: "unavailable key"}
+	ErrForbiddenIP          = &DartError{Status: StatusForbiddenIP, Message: "IP not allowed"}
+	ErrNoData               = &DartError{Status: StatusNoData, Message: "no data"}
+	ErrDocumentNotFound     = &DartError{Status: StatusFileNotFound, Message: "document not found"}
+	ErrRequestLimitExceeded = &DartError{Status: StatusRequestLimitExceeded, Message: "request limit exceeded"}
+	ErrInvalidField         = &DartError{Status: StatusInvalidField, Message: "invalid field"}
+	ErrMaintenance          = &DartError{Status: StatusMaintenance, Message: "under maintenance"}
+	ErrUndefined            = &DartError{Status: StatusUndefined, Message: "undefined error"}
+	ErrAccountExpired       = &DartError{Status: StatusAccountExpired, Message: "account expired"}
+)
+
+func (e *DartError) Error() string {
+	return fmt.Sprintf("DART error %s: %s", e.Status, e.Message)
+}
+
+// Is matches errors of the same status, so a response matches its sentinel whatever its message
+func (e *DartError) Is(target error) bool {
+	t, ok := target.(*DartError)
+	return ok && t.Status == e.Status
+}
+
+// statusError returns the error of a response status, nil for 000
+func statusError(status, message string) error {
+	if status == StatusOK {
+		return nil
+	}
+	return &DartError{Status: status, Message: message}
+}
+
+// parseErrorResponse reads the status of a JSON or XML response, nil when body is not an error response of DART.
+// XML errors come as <result><status>014</status><message>...</message></result>.
+func parseErrorResponse(body []byte) *DartError {
+	var out struct {
+		Status  string `json:"status" xml:"status"`
+		Message string `json:"message" xml:"message"`
+	}
+
+	body = bytes.TrimSpace(body)
+	switch {
+	case bytes.HasPrefix(body, []byte("{")):
+		if json.Unmarshal(body, &out) != nil {
+			return nil
+		}
+	case bytes.HasPrefix(body, []byte("<")):
+		if xml.Unmarshal(body, &out) != nil {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	if out.Status == "" || out.Status == StatusOK {
+		return nil
+	}
+	return &DartError{Status: out.Status, Message: out.Message}
+}
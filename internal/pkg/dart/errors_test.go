@@ -0,0 +1,85 @@
+package dart_test
+
+import (
+	"errors"
+	"kosis/internal/pkg/dart"
+	"kosis/internal/testhelpers"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DartError", func() {
+	var client *dart.DartClient
+
+	BeforeEach(func() {
+		testhelpers.Activate()
+
+		client = dart.New("test-dart-api-key")
+		client.UseDefaultClient()
+	})
+
+	AfterEach(func() {
+		testhelpers.Deactivate()
+	})
+
+	It("matches its sentinel whatever the message", func() {
+		err := &dart.DartError{Status: dart.StatusUnregisteredKey, Message: "등록되지 않은 키입니다."}
+		Expect(errors.Is(err, dart.ErrUnregisteredKey)).To(BeTrue())
+		Expect(errors.Is(err, dart.ErrUnavailableKey)).To(BeFalse())
+	})
+
+	DescribeTable("parses JSON error responses",
+		func(status string, sentinel error) {
+			testhelpers.New("https://opendart.fss.or.kr").
+				Get("/api/list.json").
+				Reply(200).
+				BodyString(`{"status": "` + status + `", "message": "error"}`)
+
+			_, err := client.GetRecentRawReports()
+			Expect(err).To(MatchError(sentinel))
+
+			var dartErr *dart.DartError
+			Expect(errors.As(err, &dartErr)).To(BeTrue())
+			Expect(dartErr.Status).To(Equal(status))
+			Expect(dartErr.Message).To(Equal("error"))
+		},
+		Entry("unregistered key", dart.StatusUnregisteredKey, dart.ErrUnregisteredKey),
+		Entry("unavailable key", dart.StatusUnavailableKey, dart.ErrUnavailableKey),
+		Entry("IP not allowed", dart.StatusForbiddenIP, dart.ErrForbiddenIP),
+		Entry("no data", dart.StatusNoData, dart.ErrNoData),
+		Entry("request limit exceeded", dart.StatusRequestLimitExceeded, dart.ErrRequestLimitExceeded),
+		Entry("invalid field", dart.StatusInvalidField, dart.ErrInvalidField),
+		Entry("maintenance", dart.StatusMaintenance, dart.ErrMaintenance),
+		Entry("undefined", dart.StatusUndefined, dart.ErrUndefined),
+		Entry("account expired", dart.StatusAccountExpired, dart.ErrAccountExpired),
+	)
+
+	DescribeTable("parses XML error responses of documents",
+		func(contentType string, status string, sentinel error) {
+			testhelpers.New("https://opendart.fss.or.kr").
+				Get("/api/document.xml").
+				Reply(200).
+				Header("Content-Type", contentType).
+				BodyString(`<?xml version="1.0" encoding="UTF-8"?><result><status>` + status + `</status><message>error</message></result>`)
+
+			_, err := client.GetDocument("20250101000001")
+			Expect(err).To(MatchError(sentinel))
+		},
+		Entry("document not found", "application/xml;charset=UTF-8", dart.StatusFileNotFound, dart.ErrDocumentNotFound),
+		Entry("document not found without charset", "application/xml", dart.StatusFileNotFound, dart.ErrDocumentNotFound),
+		Entry("unregistered key", "text/xml;charset=UTF-8", dart.StatusUnregisteredKey, dart.ErrUnregisteredKey),
+		Entry("maintenance", "application/xml;charset=UTF-8", dart.StatusMaintenance, dart.ErrMaintenance),
+	)
+
+	It("parses XML error responses of corpCode.xml", func() {
+		testhelpers.New("https://opendart.fss.or.kr").
+			Get("/api/corpCode.xml").
+			Reply(200).
+			Header("Content-Type", "application/xml;charset=UTF-8").
+			BodyString(`<?xml version="1.0" encoding="UTF-8"?><result><status>011</status><message>사용할 수 없는 키입니다.</message></result>`)
+
+		err := client.EachCompany(func(dart.Company) error { return nil })
+		Expect(err).To(MatchError(dart.ErrUnavailableKey))
+	})
+})
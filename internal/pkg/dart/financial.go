@@ -0,0 +1,151 @@
+package dart
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+type FsDiv string
+
+const CONSOLIDATED = FsDiv("CFS") // 연결재무제표
+const SEPARATE = FsDiv("OFS")     // 재무제표
+
+// maxMultiCompanies is the number of corp codes fnlttMultiAcnt accepts at once
+const maxMultiCompanies = 100
+
+// AccountItem is a single account row of a periodic report's financial statements.
+// Amounts are kept as returned by DART; use the *Amount helpers to read them as numbers.
+type AccountItem struct {
+	RceptNo         string     `json:"rcept_no"`
+	BsnsYear        string     `json:"bsns_year"`
+	CorpCode        string     `json:"corp_code"`
+	StockCode       string     `json:"stock_code"`
+	ReprtCode       ReportType `json:"reprt_code"`
+	FsDiv           FsDiv      `json:"fs_div"`
+	FsNm            string     `json:"fs_nm"`
+	SjDiv           string     `json:"sj_div"` // BS, IS, CIS, CF, SCE
+	SjNm            string     `json:"sj_nm"`
+	AccountID       string     `json:"account_id"` // only in fnlttSinglAcntAll, e.g. ifrs-full_Assets
+	AccountNm       string     `json:"account_nm"`
+	AccountDetail   string     `json:"account_detail"`
+	ThstrmNm        string     `json:"thstrm_nm"`
+	ThstrmDt        string     `json:"thstrm_dt"`
+	ThstrmAmount    string     `json:"thstrm_amount"`
+	ThstrmAddAmount string     `json:"thstrm_add_amount"`
+	FrmtrmQNm       string     `json:"frmtrm_q_nm"`
+	FrmtrmQAmount   string     `json:"frmtrm_q_amount"`
+	FrmtrmNm        string     `json:"frmtrm_nm"`
+	FrmtrmDt        string     `json:"frmtrm_dt"`
+	FrmtrmAmount    string     `json:"frmtrm_amount"`
+	FrmtrmAddAmount string     `json:"frmtrm_add_amount"`
+	BfefrmtrmNm     string     `json:"bfefrmtrm_nm"`
+	BfefrmtrmDt     string     `json:"bfefrmtrm_dt"`
+	BfefrmtrmAmount string     `json:"bfefrmtrm_amount"`
+	Ord             string     `json:"ord"`
+	Currency        string     `json:"currency"`
+}
+
+type AccountResp struct {
+	Status  string        `json:"status"`
+	Message string        `json:"message"`
+	List    []AccountItem `json:"list"`
+}
+
+// CurrentAmount returns the amount of the current period (당기)
+func (a AccountItem) CurrentAmount() *int64 {
+	return toInt64Ptr(a.ThstrmAmount)
+}
+
+// CumulativeAmount returns the amount from the start of the fiscal year to the end of the current period (당기 누적)
+func (a AccountItem) CumulativeAmount() *int64 {
+	return toInt64Ptr(a.ThstrmAddAmount)
+}
+
+// PreviousAmount returns the amount of the previous period (전기)
+func (a AccountItem) PreviousAmount() *int64 {
+	return toInt64Ptr(a.FrmtrmAmount)
+}
+
+// BeforePreviousAmount returns the amount of the period before the previous one (전전기)
+func (a AccountItem) BeforePreviousAmount() *int64 {
+	return toInt64Ptr(a.BfefrmtrmAmount)
+}
+
+// 단일회사 주요계정
+// https://opendart.fss.or.kr/guide/detail.do?apiGrpCd=DS003&apiId=2019016
+func (c *DartClient) GetMajorAccounts(corpCode, bsnsYear string, reportCode ReportType) ([]AccountItem, error) {
+	q := url.Values{}
+	q.Set("corp_code", corpCode)            // 8자리 기업코드(예: 삼성전자 00126380)
+	q.Set("bsns_year", bsnsYear)            // 사업연도, 2015년 이후
+	q.Set("reprt_code", string(reportCode)) // 보고서 코드
+
+	return c.getAccounts("/fnlttSinglAcnt.json", q)
+}
+
+// 다중회사 주요계정
+// https://opendart.fss.or.kr/guide/detail.do?apiGrpCd=DS003&apiId=2019017
+func (c *DartClient) GetMultiMajorAccounts(corpCodes []string, bsnsYear string, reportCode ReportType) ([]AccountItem, error) {
+	if len(corpCodes) == 0 {
+		return nil, fmt.Errorf("at least one corp code is required")
+	}
+	if len(corpCodes) > maxMultiCompanies {
+		return nil, fmt.Errorf("too many corp codes: %d, max %d", len(corpCodes), maxMultiCompanies)
+	}
+
+	q := url.Values{}
+	q.Set("corp_code", strings.Join(corpCodes, ",")) // 쉼표로 구분, 최대 100건
+	q.Set("bsns_year", bsnsYear)
+	q.Set("reprt_code", string(reportCode))
+
+	return c.getAccounts("/fnlttMultiAcnt.json", q)
+}
+
+// 단일회사 전체 재무제표
+// https://opendart.fss.or.kr/guide/detail.do?apiGrpCd=DS003&apiId=2019020
+func (c *DartClient) GetFullFinancialStatements(corpCode, bsnsYear string, reportCode ReportType, fsDiv FsDiv) ([]AccountItem, error) {
+	q := url.Values{}
+	q.Set("corp_code", corpCode)
+	q.Set("bsns_year", bsnsYear)
+	q.Set("reprt_code", string(reportCode))
+	q.Set("fs_div", string(fsDiv)) // CFS: 연결재무제표, OFS: 재무제표
+
+	items, err := c.getAccounts("/fnlttSinglAcntAll.json", q)
+	if err != nil {
+		return nil, err
+	}
+
+	// fnlttSinglAcntAll does not echo fs_div and bsns_year back
+	for i := range items {
+		items[i].FsDiv = fsDiv
+		if items[i].BsnsYear == "" {
+			items[i].BsnsYear = bsnsYear
+		}
+	}
+
+	return items, nil
+}
+
+func (c *DartClient) getAccounts(path string, q url.Values) ([]AccountItem, error) {
+	u, _ := url.Parse(baseURL + path)
+	q.Set("crtfc_key", c.key) // API Key
+	u.RawQuery = q.Encode()
+
+	resp, err := c.client.Get(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var out AccountResp
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, err
+	}
+
+	if err := statusError(out.Status, out.Message); err != nil {
+		return nil, err
+	}
+
+	return out.List, nil
+}
@@ -0,0 +1,75 @@
+package dart_test
+
+import (
+	"kosis/internal/pkg/dart"
+	"kosis/internal/testhelpers"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// goldenFiling is what the golden files record of a disclosure of testdata
+type goldenFiling struct {
+	ReportName string          `json:"report_name"`
+	Parser     string          `json:"parser"`
+	Document   any             `json:"document,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	Amendment  *dart.Amendment `json:"amendment,omitempty"`
+}
+
+// The parses of the disclosures of testdata are kept in testdata/golden, run the specs with -update
+// after a parser change and review the diff of the golden files
+var _ = Describe("Golden corpus", func() {
+	// the report names the disclosures were filed under
+	reportNames := map[string]string{
+		"acquisition-decision.html":       "주요사항보고서(타법인주식및출자증권취득결정)",
+		"capital-increase-amendment.html": "[기재정정]주요사항보고서(유상증자결정)",
+		"capital-increase.html":           "주요사항보고서(유상증자결정)",
+		"convertible-bond.html":           "주요사항보고서(전환사채권발행결정)",
+		"disposal-decision.html":          "주요사항보고서(타법인주식및출자증권처분결정)",
+		"dividend-decision.html":          "현금ㆍ현물배당결정",
+		"exchangeable-bond.html":          "주요사항보고서(교환사채권발행결정)",
+		"insider-ownership.html":          "임원ㆍ주요주주특정증권등소유상황보고서",
+		"major-holding.html":              "주식등의대량보유상황보고서(일반)",
+		"supply-contract-amendment.html":  "[기재정정]단일판매ㆍ공급계약체결",
+		"supply-contract.html":            "단일판매ㆍ공급계약체결",
+		"treasury-acquisition.html":       "주요사항보고서(자기주식취득결정)",
+		"treasury-cancellation.html":      "자기주식소각결정",
+		"treasury-disposal.html":          "주요사항보고서(자기주식처분결정)",
+		"treasury-trust.html":             "주요사항보고서(자기주식취득신탁계약체결결정)",
+	}
+
+	files, err := filepath.Glob("testdata/*.html")
+	if err != nil {
+		panic(err)
+	}
+
+	for _, file := range files {
+		name := filepath.Base(file)
+		golden := filepath.Join("testdata", "golden", strings.TrimSuffix(name, ".html")+".json")
+
+		It("parses "+name+" as "+golden+" says", func() {
+			reportName, ok := reportNames[name]
+			Expect(ok).To(BeTrue(), "add the report name of %s to the corpus", name)
+
+			raw, err := os.ReadFile(file)
+			Expect(err).NotTo(HaveOccurred())
+
+			out := goldenFiling{ReportName: reportName}
+			out.Parser, _ = dart.ParserFor(reportName)
+			out.Document, err = dart.ParseFiling(reportName, string(raw), "20250101000001")
+			if err != nil {
+				out.Error = err.Error()
+			}
+			out.Amendment, err = dart.ParseAmendmentHTML(string(raw))
+			Expect(err).NotTo(HaveOccurred())
+
+			actual, expected, err := testhelpers.Golden(golden, out)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(actual)).To(Equal(string(expected)), "the parse of %s changed, run the specs with -update and review the diff of %s", name, golden)
+		})
+	}
+})
@@ -85,9 +85,11 @@ func toDatePtr(s string) *time.Time {
 		return nil
 	}
 
-	// 주로 YYYY-MM-DD
-	if t, err := time.Parse("2006-01-02", s); err == nil {
-		return &t
+	// 주로 YYYY-MM-DD, 소유상황보고서 등은 YYYY.MM.DD
+	for _, layout := range []string{"2006-01-02", "2006.01.02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return &t
+		}
 	}
 	return nil
 }
@@ -0,0 +1,126 @@
+package dart
+
+import (
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Amendment is the 정정신고 wrapper of an amended filing
+type Amendment struct {
+	Document     string            `json:"document"`      // 정정관련(대상) 공시서류
+	OriginalDate *time.Time        `json:"original_date"` // 정정관련 공시서류제출일, 최초제출일
+	Date         *time.Time        `json:"date"`
+	Reason       string            `json:"reason"`
+	Changes      []AmendmentChange `json:"changes"`
+}
+
+// AmendmentChange is a row of the 정정사항 table
+type AmendmentChange struct {
+	Item   string `json:"item"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// Corrected returns the 정정후 value of the first change whose item contains key, compared after norm
+func (a *Amendment) Corrected(key string) (AmendmentChange, bool) {
+	if a == nil {
+		return AmendmentChange{}, false
+	}
+	key = norm(key)
+	for _, c := range a.Changes {
+		if strings.Contains(norm(c.Item), key) {
+			return c, true
+		}
+	}
+	return AmendmentChange{}, false
+}
+
+// isAmendmentTable tells the 정정전/정정후 table apart from the body tables
+func isAmendmentTable(tbl *goquery.Selection) bool {
+	header := norm(tbl.Find("tr").First().Text())
+	return strings.Contains(header, "정정전") && strings.Contains(header, "정정후")
+}
+
+// IsCorrection tells whether the report name is a correction such as [기재정정]...
+func IsCorrection(reportName string) bool {
+	return strings.Contains(reReportNamePrefix.FindString(reportName), "정정")
+}
+
+// BaseReportName returns the normalized report name without prefixes such as [기재정정],
+// an amendment and the filing it corrects share it
+func BaseReportName(reportName string) string {
+	return norm(reReportNamePrefix.ReplaceAllString(reportName, ""))
+}
+
+// ParseAmendmentHTML reads the 정정신고 wrapper of a filing, nil when the filing is not an amendment
+func ParseAmendmentHTML(raw string) (*Amendment, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	return parseAmendment(doc), nil
+}
+
+// parseAmendment collects the 정정사항 of an amended filing, nil when the filing is not an amendment.
+// The form ids differ between filings, so the tables are found by their contents.
+func parseAmendment(doc *goquery.Document) *Amendment {
+	out := &Amendment{}
+	found := false
+	doc.Find("table").Each(func(_ int, tbl *goquery.Selection) {
+		if !isAmendmentTable(tbl) {
+			return
+		}
+		found = true
+
+		tbl.Find("tr").Each(func(i int, tr *goquery.Selection) {
+			if i == 0 {
+				return
+			}
+			tds := tr.Find("td")
+			if tds.Length() < 3 {
+				return
+			}
+
+			labels := []string{}
+			tds.Slice(0, tds.Length()-2).Each(func(_ int, td *goquery.Selection) {
+				if s := cleanVal(textOf(td)); s != "" {
+					labels = append(labels, s)
+				}
+			})
+			out.Changes = append(out.Changes, AmendmentChange{
+				Item:   strings.Join(labels, " "),
+				Before: cleanVal(textOf(tds.Eq(tds.Length() - 2))),
+				After:  cleanVal(textOf(tds.Last())),
+			})
+		})
+	})
+
+	doc.Find("tr").Each(func(_ int, tr *goquery.Selection) {
+		tds := tr.Find("td")
+		if tds.Length() < 2 {
+			return
+		}
+		label := norm(reBlockNumber.ReplaceAllString(textOf(tds.First()), ""))
+		val := cleanVal(textOf(tds.Last()))
+		switch {
+		case label == "정정일자":
+			out.Date = toDatePtr(val)
+		case label == "정정사유":
+			out.Reason = val
+		case strings.HasPrefix(label, "정정관련공시서류"), strings.HasPrefix(label, "정정대상공시서류"):
+			found = true
+			if strings.Contains(label, "제출일") {
+				out.OriginalDate = toDatePtr(val)
+			} else {
+				out.Document = val
+			}
+		}
+	})
+
+	if !found {
+		return nil
+	}
+	return out
+}
@@ -0,0 +1,49 @@
+package dart_test
+
+import (
+	"kosis/internal/pkg/dart"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParseAmendmentHTML", func() {
+	It("reads the filing an amendment corrects", func() {
+		raw, err := os.ReadFile("testdata/supply-contract-amendment.html")
+		Expect(err).NotTo(HaveOccurred())
+
+		amendment, err := dart.ParseAmendmentHTML(string(raw))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(amendment).NotTo(BeNil())
+		Expect(amendment.Document).To(Equal("단일판매ㆍ공급계약체결"))
+		Expect(amendment.OriginalDate.Format("2006-01-02")).To(Equal("2024-12-30"))
+		Expect(amendment.Date.Format("2006-01-02")).To(Equal("2025-06-02"))
+		Expect(amendment.Changes).To(HaveLen(3))
+	})
+
+	It("returns nil for a filing that is not an amendment", func() {
+		raw, err := os.ReadFile("testdata/supply-contract.html")
+		Expect(err).NotTo(HaveOccurred())
+
+		amendment, err := dart.ParseAmendmentHTML(string(raw))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(amendment).To(BeNil())
+	})
+})
+
+var _ = DescribeTable("IsCorrection",
+	func(reportName string, expected bool) {
+		Expect(dart.IsCorrection(reportName)).To(Equal(expected))
+	},
+	Entry("plain filing", "단일판매ㆍ공급계약체결", false),
+	Entry("correction", "[기재정정]단일판매ㆍ공급계약체결", true),
+	Entry("attached correction", "[첨부정정]주요사항보고서(유상증자결정)", true),
+	Entry("additional filing", "[첨부추가]주요사항보고서(유상증자결정)", false),
+)
+
+var _ = Describe("BaseReportName", func() {
+	It("is shared by a correction and its original", func() {
+		Expect(dart.BaseReportName("[기재정정]단일판매ㆍ공급계약체결")).To(Equal(dart.BaseReportName("단일판매ㆍ공급계약체결")))
+	})
+})
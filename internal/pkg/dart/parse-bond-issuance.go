@@ -0,0 +1,203 @@
+package dart
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Kinds of equity-linked bonds
+const (
+	BondKindConvertible  = "CB" // 전환사채
+	BondKindWithWarrants = "BW" // 신주인수권부사채
+	BondKindExchangeable = "EB" // 교환사채
+)
+
+type BondIssuanceDoc struct {
+	DocType            string         `json:"doc_type"` // "전환사채권 발행결정" 등
+	RceptNo            string         `json:"rcept_no"` // (상위 로직에서 주입)
+	BondKind           string         `json:"bond_kind"`
+	Series             string         `json:"series"`    // 회차
+	BondType           string         `json:"bond_type"` // 무기명식 이권부 무보증 사모 전환사채 등
+	FaceAmountKRW      *int64         `json:"face_amount_krw"`
+	CouponRate         *float64       `json:"coupon_rate"`       // 표면이자율(%)
+	YieldToMaturity    *float64       `json:"yield_to_maturity"` // 만기이자율(%)
+	MaturityDate       *time.Time     `json:"maturity_date"`
+	IssueMethod        string         `json:"issue_method"`   // 공모/사모
+	ExerciseRatio      *float64       `json:"exercise_ratio"` // 전환/행사/교환비율(%)
+	ExercisePrice      *int64         `json:"exercise_price"` // 전환/행사/교환가액(원/주)
+	RefixingFloor      *int64         `json:"refixing_floor"` // 시가하락에 따른 최저 조정가액(원)
+	ShareKind          string         `json:"share_kind"`     // 발행(교환)할 주식의 종류
+	Shares             *int64         `json:"shares"`
+	RatioToTotalShares *float64       `json:"ratio_to_total_shares"` // 주식총수 대비 비율(%)
+	ExerciseFrom       *time.Time     `json:"exercise_from"`
+	ExerciseTo         *time.Time     `json:"exercise_to"`
+	SubscriptionDate   *time.Time     `json:"subscription_date"`
+	PaymentDate        *time.Time     `json:"payment_date"`
+	BoardDate          *time.Time     `json:"board_date"`
+	PutOption          string         `json:"put_option"`  // 조기상환청구권
+	CallOption         string         `json:"call_option"` // 매도청구권
+	Investors          []BondInvestor `json:"investors"`
+
+	// filled by ApplyDilution
+	OutstandingShares *int64      `json:"outstanding_shares"`
+	SharesBasis       *ShareCount `json:"shares_basis,omitempty"` // where and as of when OutstandingShares was read
+	Dilution          *float64    `json:"dilution"`               // %
+}
+
+// BondInvestor is a row of 특정인에 대한 대상자별 사채발행내역
+type BondInvestor struct {
+	Name      string `json:"name"`
+	Relation  string `json:"relation"`
+	AmountKRW *int64 `json:"amount_krw"`
+}
+
+// ApplyDilution sets the share of the company the bond holders would own after converting
+// (exercising) in full, given the outstanding shares before the issuance. Exchangeable bonds are
+// exchanged for shares already issued, they dilute nothing.
+func (d *BondIssuanceDoc) ApplyDilution(shares ShareCount) {
+	if d.BondKind == BondKindExchangeable || d.Shares == nil || shares.Outstanding <= 0 {
+		return
+	}
+
+	outstanding := shares.Outstanding
+	dilution := math.Round(float64(*d.Shares)/float64(outstanding+*d.Shares)*10000) / 100
+	d.OutstandingShares = &outstanding
+	d.SharesBasis = &shares
+	d.Dilution = &dilution
+}
+
+// ParseBondIssuanceHTML parses "전환사채권 발행결정", "신주인수권부사채권 발행결정" and "교환사채권 발행결정".
+// The three forms share their layout except for the block on the right attached to the bond.
+func ParseBondIssuanceHTML(raw string, rceptNo string) (*BondIssuanceDoc, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	out := &BondIssuanceDoc{
+		RceptNo: rceptNo,
+	}
+
+	doc.Find("table").Each(func(_ int, tbl *goquery.Selection) {
+		// --- 특정인에 대한 대상자별 사채발행내역
+		if strings.Contains(norm(tbl.Find("tr").First().Text()), "대상자명") {
+			tbl.Find("tr").Each(func(i int, tr *goquery.Selection) {
+				tds := tr.Find("td")
+				if i == 0 || tds.Length() < 3 {
+					return
+				}
+				name := cleanVal(textOf(tds.Eq(0)))
+				if name == "" || name == "-" || norm(name) == "합계" {
+					return
+				}
+				out.Investors = append(out.Investors, BondInvestor{
+					Name:      name,
+					Relation:  cleanVal(textOf(tds.Eq(1))),
+					AmountKRW: toInt64Ptr(textOf(tds.Last())),
+				})
+			})
+			return
+		}
+
+		// --- 본문 메인 테이블
+		block := ""
+		tbl.Find("tr").Each(func(_ int, tr *goquery.Selection) {
+			tds := tr.Find("td")
+			if tds.Length() < 2 {
+				return
+			}
+
+			first := cleanVal(textOf(tds.First()))
+			if reBlockNumber.MatchString(first) {
+				block = norm(reBlockNumber.ReplaceAllString(first, ""))
+			}
+
+			// 1. 사채의 종류 | 회차 | 1 | 종류 | ...
+			if strings.HasPrefix(block, "사채의종류") && tds.Length() >= 5 {
+				out.Series = cleanVal(textOf(tds.Eq(2)))
+				out.BondType = cleanVal(textOf(tds.Eq(4)))
+				return
+			}
+
+			label := norm(textOf(tds.Eq(tds.Length() - 2)))
+			if reBlockNumber.MatchString(textOf(tds.Eq(tds.Length() - 2))) {
+				label = ""
+			}
+
+			assignBond(out, block, label, cleanVal(textOf(tds.Last())))
+		})
+	})
+
+	switch out.BondKind {
+	case BondKindConvertible:
+		out.DocType = "전환사채권 발행결정"
+	case BondKindWithWarrants:
+		out.DocType = "신주인수권부사채권 발행결정"
+	case BondKindExchangeable:
+		out.DocType = "교환사채권 발행결정"
+	}
+
+	return out, nil
+}
+
+// 블록+라벨별로 out에 주입
+func assignBond(out *BondIssuanceDoc, block, label, value string) {
+	switch {
+	case strings.HasPrefix(block, "사채의권면"):
+		if label == "" {
+			out.FaceAmountKRW = toInt64Ptr(value)
+		}
+	case strings.HasPrefix(block, "사채의이율"):
+		switch {
+		case strings.HasPrefix(label, "표면이자율"):
+			out.CouponRate = toFloat64Ptr(value)
+		case strings.HasPrefix(label, "만기이자율"):
+			out.YieldToMaturity = toFloat64Ptr(value)
+		}
+	case strings.HasPrefix(block, "사채만기일"):
+		out.MaturityDate = toDatePtr(value)
+	case strings.HasPrefix(block, "사채발행방법"):
+		out.IssueMethod = value
+	case strings.HasPrefix(block, "전환에관한사항"), strings.HasPrefix(block, "신주인수권에관한사항"), strings.HasPrefix(block, "교환에관한사항"):
+		switch {
+		case strings.HasPrefix(block, "전환"):
+			out.BondKind = BondKindConvertible
+		case strings.HasPrefix(block, "신주인수권"):
+			out.BondKind = BondKindWithWarrants
+		default:
+			out.BondKind = BondKindExchangeable
+		}
+
+		switch {
+		case strings.Contains(label, "비율") && !strings.Contains(label, "주식총수"):
+			out.ExerciseRatio = toFloat64Ptr(value)
+		case strings.Contains(label, "가액원/주"):
+			out.ExercisePrice = toInt64Ptr(value)
+		case strings.HasPrefix(label, "최저조정가액") && !strings.Contains(label, "근거"):
+			out.RefixingFloor = toInt64Ptr(value)
+		case label == "종류":
+			out.ShareKind = value
+		case label == "주식수":
+			out.Shares = toInt64Ptr(value)
+		case strings.HasPrefix(label, "주식총수대비"):
+			out.RatioToTotalShares = toFloat64Ptr(value)
+		case label == "시작일":
+			out.ExerciseFrom = toDatePtr(value)
+		case label == "종료일":
+			out.ExerciseTo = toDatePtr(value)
+		}
+	case strings.HasPrefix(block, "청약일"):
+		out.SubscriptionDate = toDatePtr(value)
+	case strings.HasPrefix(block, "납입일"):
+		out.PaymentDate = toDatePtr(value)
+	case strings.HasPrefix(block, "이사회결의일"):
+		out.BoardDate = toDatePtr(value)
+	case strings.HasPrefix(block, "조기상환청구권"):
+		out.PutOption = value
+	case strings.HasPrefix(block, "매도청구권"):
+		out.CallOption = value
+	}
+}
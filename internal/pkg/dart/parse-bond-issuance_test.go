@@ -0,0 +1,91 @@
+package dart_test
+
+import (
+	"kosis/internal/pkg/dart"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParseBondIssuanceHTML", func() {
+	mustParse := func(name string) *dart.BondIssuanceDoc {
+		raw, err := os.ReadFile("testdata/" + name)
+		Expect(err).NotTo(HaveOccurred())
+
+		doc, err := dart.ParseBondIssuanceHTML(string(raw), "20250620000321")
+		Expect(err).NotTo(HaveOccurred())
+		return doc
+	}
+
+	It("parses a convertible bond issuance", func() {
+		doc := mustParse("convertible-bond.html")
+
+		Expect(doc.DocType).To(Equal("전환사채권 발행결정"))
+		Expect(doc.BondKind).To(Equal(dart.BondKindConvertible))
+		Expect(doc.Series).To(Equal("3"))
+		Expect(doc.BondType).To(Equal("무기명식 이권부 무보증 사모 전환사채"))
+		Expect(*doc.FaceAmountKRW).To(Equal(int64(30000000000)))
+		Expect(*doc.CouponRate).To(Equal(0.0))
+		Expect(*doc.YieldToMaturity).To(Equal(2.5))
+		Expect(doc.MaturityDate.Format("2006-01-02")).To(Equal("2028-06-30"))
+		Expect(doc.IssueMethod).To(Equal("사모"))
+		Expect(*doc.ExerciseRatio).To(Equal(100.0))
+		Expect(*doc.ExercisePrice).To(Equal(int64(12500)))
+		Expect(*doc.RefixingFloor).To(Equal(int64(8750)))
+		Expect(doc.ShareKind).To(Equal("주식회사 케이엠 기명식 보통주"))
+		Expect(*doc.Shares).To(Equal(int64(2400000)))
+		Expect(*doc.RatioToTotalShares).To(Equal(5.12))
+		Expect(doc.ExerciseFrom.Format("2006-01-02")).To(Equal("2026-06-30"))
+		Expect(doc.ExerciseTo.Format("2006-01-02")).To(Equal("2028-05-30"))
+		Expect(doc.SubscriptionDate.Format("2006-01-02")).To(Equal("2025-06-27"))
+		Expect(doc.PaymentDate.Format("2006-01-02")).To(Equal("2025-06-30"))
+		Expect(doc.BoardDate.Format("2006-01-02")).To(Equal("2025-06-20"))
+		Expect(doc.PutOption).To(HavePrefix("사채권자는 발행일로부터 2년이 되는 날"))
+		Expect(doc.CallOption).To(HavePrefix("발행회사 또는 발행회사가 지정하는 자는"))
+
+		Expect(doc.Investors).To(HaveLen(2))
+		Expect(doc.Investors[0].Name).To(Equal("스마트 메자닌 제1호 신기술사업투자조합"))
+		Expect(*doc.Investors[0].AmountKRW).To(Equal(int64(20000000000)))
+		Expect(doc.Investors[1].Name).To(Equal("케이비증권 주식회사"))
+
+		Expect(doc.Dilution).To(BeNil())
+	})
+
+	It("parses an exchangeable bond issuance", func() {
+		doc := mustParse("exchangeable-bond.html")
+
+		Expect(doc.DocType).To(Equal("교환사채권 발행결정"))
+		Expect(doc.BondKind).To(Equal(dart.BondKindExchangeable))
+		Expect(*doc.FaceAmountKRW).To(Equal(int64(5000000000)))
+		Expect(*doc.ExercisePrice).To(Equal(int64(25000)))
+		Expect(doc.RefixingFloor).To(BeNil())
+		Expect(*doc.Shares).To(Equal(int64(200000)))
+		Expect(doc.ExerciseTo.Format("2006-01-02")).To(Equal("2030-02-15"))
+		Expect(doc.Investors).To(BeEmpty())
+	})
+
+	It("computes the dilution against outstanding shares", func() {
+		doc := mustParse("convertible-bond.html")
+
+		shares := dart.ShareCount{Outstanding: 44_475_000, AsOf: time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC), Source: dart.ShareSourceReport, ReceiptNumber: "20250515000100"}
+		doc.ApplyDilution(shares)
+		Expect(*doc.OutstandingShares).To(Equal(int64(44475000)))
+		Expect(*doc.SharesBasis).To(Equal(shares))
+		Expect(*doc.Dilution).To(Equal(5.12))
+
+		doc = &dart.BondIssuanceDoc{}
+		doc.ApplyDilution(shares)
+		Expect(doc.Dilution).To(BeNil())
+	})
+
+	It("leaves exchangeable bonds undiluted", func() {
+		doc := mustParse("exchangeable-bond.html")
+		Expect(doc.Shares).NotTo(BeNil())
+
+		doc.ApplyDilution(dart.ShareCount{Outstanding: 44_475_000})
+		Expect(doc.OutstandingShares).To(BeNil())
+		Expect(doc.Dilution).To(BeNil())
+	})
+})
@@ -0,0 +1,229 @@
+package dart
+
+import (
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+type CapitalIncreaseDoc struct {
+	DocType            string     `json:"doc_type"` // "유상증자결정"
+	RceptNo            string     `json:"rcept_no"` // (상위 로직에서 주입)
+	NewCommonShares    *int64     `json:"new_common_shares"`
+	NewOtherShares     *int64     `json:"new_other_shares"`
+	ParValue           *int64     `json:"par_value"`
+	PriorCommonShares  *int64     `json:"prior_common_shares"` // 증자전 발행주식총수
+	PriorOtherShares   *int64     `json:"prior_other_shares"`
+	Method             string     `json:"method"` // 주주배정증자, 제3자배정증자, 일반공모증자 등
+	CommonIssuePrice   *int64     `json:"common_issue_price"`
+	OtherIssuePrice    *int64     `json:"other_issue_price"`
+	PriceConfirmed     *bool      `json:"price_confirmed"` // 확정발행가 여부, 예정발행가면 false
+	DiscountRate       *float64   `json:"discount_rate"`   // 할인율 또는 할증율(%)
+	PricingMethod      string     `json:"pricing_method"`
+	RecordDate         *time.Time `json:"record_date"` // 신주배정기준일
+	PaymentDate        *time.Time `json:"payment_date"`
+	DividendStartDate  *time.Time `json:"dividend_start_date"`
+	ListingDate        *time.Time `json:"listing_date"`
+	RightsTransferable *bool      `json:"rights_transferable"` // 신주인수권양도여부
+	BoardDate          *time.Time `json:"board_date"`
+	Allottees          []Allottee `json:"allottees"`
+	Amendment          *Amendment `json:"amendment"` // 정정신고일 때만
+}
+
+// Allottee is a row of 제3자배정 대상자별 선정경위, 거래내역, 배정내역 등
+type Allottee struct {
+	Name     string `json:"name"`
+	Relation string `json:"relation"`
+	Reason   string `json:"reason"` // 선정경위
+	Shares   *int64 `json:"shares"`
+	LockUp   string `json:"lock_up"` // 비고란의 보호예수(전매제한) 등
+}
+
+// ParseCapitalIncreaseHTML parses "유상증자결정" and merges the 정정후 values of an amendment
+func ParseCapitalIncreaseHTML(raw string, rceptNo string) (*CapitalIncreaseDoc, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	out := &CapitalIncreaseDoc{
+		DocType:   "유상증자결정",
+		RceptNo:   rceptNo,
+		Amendment: parseAmendment(doc),
+	}
+
+	doc.Find("table").Each(func(_ int, tbl *goquery.Selection) {
+		if isAmendmentTable(tbl) {
+			return
+		}
+
+		// --- 제3자배정 대상자별 선정경위, 거래내역, 배정내역 등
+		if strings.Contains(norm(tbl.Find("tr").First().Text()), "제3자배정대상자") {
+			out.Allottees = append(out.Allottees, parseAllottees(tbl)...)
+			return
+		}
+
+		// --- 본문 메인 테이블
+		block, class := "", ""
+		tbl.Find("tr").Each(func(_ int, tr *goquery.Selection) {
+			tds := tr.Find("td")
+			if tds.Length() < 2 {
+				return
+			}
+
+			first := cleanVal(textOf(tds.First()))
+			if reBlockNumber.MatchString(first) {
+				block = norm(reBlockNumber.ReplaceAllString(first, ""))
+				class = ""
+			}
+			tds.Slice(0, tds.Length()-1).Each(func(_ int, td *goquery.Selection) {
+				if c := shareClassOf(textOf(td)); c != "" {
+					class = c
+				}
+			})
+
+			label := ""
+			if l := textOf(tds.Eq(tds.Length() - 2)); !reBlockNumber.MatchString(l) {
+				label = norm(l)
+			}
+
+			assignCapital(out, block, class, label, cleanVal(textOf(tds.Last())))
+		})
+	})
+
+	// 정정후 값 우선, 정정사항은 "6. 신주 발행가액 - 보통주식 - 확정발행가 (원)" 꼴
+	if out.Amendment != nil {
+		for _, c := range out.Amendment.Changes {
+			parts := strings.Split(c.Item, " - ")
+			block := norm(reBlockNumber.ReplaceAllString(parts[0], ""))
+			class, label := "", ""
+			for _, part := range parts[1:] {
+				if cls := shareClassOf(part); cls != "" {
+					class = cls
+				}
+			}
+			if len(parts) > 1 {
+				label = norm(parts[len(parts)-1])
+			}
+			assignCapital(out, block, class, label, c.After)
+		}
+	}
+
+	return out, nil
+}
+
+func parseAllottees(tbl *goquery.Selection) []Allottee {
+	cols := map[string]int{}
+	tbl.Find("tr").First().Find("td").Each(func(i int, td *goquery.Selection) {
+		h := norm(textOf(td))
+		switch {
+		case strings.Contains(h, "대상자"):
+			cols["name"] = i
+		case strings.Contains(h, "관계"):
+			cols["relation"] = i
+		case strings.Contains(h, "선정경위"):
+			cols["reason"] = i
+		case strings.Contains(h, "배정주식수"):
+			cols["shares"] = i
+		case strings.Contains(h, "비고"):
+			cols["note"] = i
+		}
+	})
+
+	cell := func(tds *goquery.Selection, col string) string {
+		i, ok := cols[col]
+		if !ok || i >= tds.Length() {
+			return ""
+		}
+		return cleanVal(textOf(tds.Eq(i)))
+	}
+
+	var out []Allottee
+	tbl.Find("tr").Each(func(i int, tr *goquery.Selection) {
+		tds := tr.Find("td")
+		if i == 0 || tds.Length() < 2 {
+			return
+		}
+		name := cell(tds, "name")
+		if name == "" || name == "-" || norm(name) == "합계" {
+			return
+		}
+		out = append(out, Allottee{
+			Name:     name,
+			Relation: cell(tds, "relation"),
+			Reason:   cell(tds, "reason"),
+			Shares:   toInt64Ptr(cell(tds, "shares")),
+			LockUp:   cell(tds, "note"),
+		})
+	})
+	return out
+}
+
+// 보통주식/기타주식(종류주식) 구분
+func shareClassOf(s string) string {
+	s = norm(s)
+	switch {
+	case strings.HasPrefix(s, "보통주식"):
+		return "common"
+	case strings.HasPrefix(s, "기타주식"), strings.HasPrefix(s, "종류주식"):
+		return "other"
+	}
+	return ""
+}
+
+// 블록+주식종류+라벨별로 out에 주입
+func assignCapital(out *CapitalIncreaseDoc, block, class, label, value string) {
+	pick := func(common, other **int64) {
+		switch class {
+		case "common":
+			*common = toInt64Ptr(value)
+		case "other":
+			*other = toInt64Ptr(value)
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(block, "신주의종류와수"):
+		pick(&out.NewCommonShares, &out.NewOtherShares)
+	case strings.HasPrefix(block, "1주당액면가액"):
+		out.ParValue = toInt64Ptr(value)
+	case strings.HasPrefix(block, "증자전발행주식총수"):
+		pick(&out.PriorCommonShares, &out.PriorOtherShares)
+	case strings.HasPrefix(block, "증자방식"):
+		out.Method = value
+	case strings.HasPrefix(block, "신주발행가액"):
+		switch {
+		case strings.HasPrefix(label, "확정발행가"):
+			if toInt64Ptr(value) == nil {
+				return
+			}
+			pick(&out.CommonIssuePrice, &out.OtherIssuePrice)
+			confirmed := true
+			out.PriceConfirmed = &confirmed
+		case strings.HasPrefix(label, "예정발행가"):
+			if toInt64Ptr(value) == nil || out.PriceConfirmed != nil && *out.PriceConfirmed {
+				return
+			}
+			pick(&out.CommonIssuePrice, &out.OtherIssuePrice)
+			confirmed := false
+			out.PriceConfirmed = &confirmed
+		}
+	case strings.HasPrefix(block, "할인율또는할증율"):
+		out.DiscountRate = toFloat64Ptr(value)
+	case strings.HasPrefix(block, "발행가산정방법"), strings.HasPrefix(block, "발행가액산정방법"):
+		out.PricingMethod = value
+	case strings.HasPrefix(block, "신주배정기준일"):
+		out.RecordDate = toDatePtr(value)
+	case strings.HasPrefix(block, "납입일"):
+		out.PaymentDate = toDatePtr(value)
+	case strings.HasPrefix(block, "신주의배당기산일"):
+		out.DividendStartDate = toDatePtr(value)
+	case strings.HasPrefix(block, "신주의상장예정일"):
+		out.ListingDate = toDatePtr(value)
+	case strings.HasPrefix(block, "신주인수권양도여부"):
+		out.RightsTransferable = boolFromKorean(value)
+	case strings.HasPrefix(block, "이사회결의일"):
+		out.BoardDate = toDatePtr(value)
+	}
+}
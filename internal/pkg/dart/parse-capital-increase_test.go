@@ -0,0 +1,66 @@
+package dart_test
+
+import (
+	"kosis/internal/pkg/dart"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParseCapitalIncreaseHTML", func() {
+	mustParse := func(name string) *dart.CapitalIncreaseDoc {
+		raw, err := os.ReadFile("testdata/" + name)
+		Expect(err).NotTo(HaveOccurred())
+
+		doc, err := dart.ParseCapitalIncreaseHTML(string(raw), "20250701000555")
+		Expect(err).NotTo(HaveOccurred())
+		return doc
+	}
+
+	It("parses a third-party allotment", func() {
+		doc := mustParse("capital-increase.html")
+
+		Expect(doc.RceptNo).To(Equal("20250701000555"))
+		Expect(*doc.NewCommonShares).To(Equal(int64(4000000)))
+		Expect(doc.NewOtherShares).To(BeNil())
+		Expect(*doc.ParValue).To(Equal(int64(500)))
+		Expect(*doc.PriorCommonShares).To(Equal(int64(36000000)))
+		Expect(doc.Method).To(Equal("제3자배정증자"))
+		Expect(*doc.CommonIssuePrice).To(Equal(int64(5000)))
+		Expect(doc.OtherIssuePrice).To(BeNil())
+		Expect(*doc.PriceConfirmed).To(BeTrue())
+		Expect(*doc.DiscountRate).To(Equal(10.0))
+		Expect(doc.PricingMethod).To(ContainSubstring("10% 할인율"))
+		Expect(doc.RecordDate).To(BeNil())
+		Expect(doc.PaymentDate.Format("2006-01-02")).To(Equal("2025-07-15"))
+		Expect(doc.DividendStartDate.Format("2006-01-02")).To(Equal("2025-01-01"))
+		Expect(doc.ListingDate.Format("2006-01-02")).To(Equal("2025-08-01"))
+		Expect(*doc.RightsTransferable).To(BeFalse())
+		Expect(doc.BoardDate.Format("2006-01-02")).To(Equal("2025-07-01"))
+		Expect(doc.Amendment).To(BeNil())
+
+		Expect(doc.Allottees).To(HaveLen(2))
+		Expect(doc.Allottees[0].Name).To(Equal("주식회사 에이치홀딩스"))
+		Expect(doc.Allottees[0].Relation).To(Equal("최대주주"))
+		Expect(doc.Allottees[0].Reason).To(Equal("회사의 경영안정 및 책임경영"))
+		Expect(*doc.Allottees[0].Shares).To(Equal(int64(3000000)))
+		Expect(doc.Allottees[0].LockUp).To(Equal("1년간 전량 보호예수"))
+		Expect(doc.Allottees[1].Relation).To(Equal("대표이사"))
+	})
+
+	It("merges the corrected values of an amendment", func() {
+		doc := mustParse("capital-increase-amendment.html")
+
+		Expect(*doc.CommonIssuePrice).To(Equal(int64(4850)))
+		Expect(*doc.DiscountRate).To(Equal(12.7))
+		Expect(doc.PaymentDate.Format("2006-01-02")).To(Equal("2025-07-22"))
+		Expect(doc.ListingDate.Format("2006-01-02")).To(Equal("2025-08-08"))
+		Expect(*doc.NewCommonShares).To(Equal(int64(4000000)))
+
+		Expect(doc.Amendment).NotTo(BeNil())
+		Expect(doc.Amendment.Reason).To(Equal("발행가액 확정 및 납입일 변경"))
+		Expect(doc.Amendment.Date.Format("2006-01-02")).To(Equal("2025-07-10"))
+		Expect(doc.Amendment.Changes).To(HaveLen(4))
+	})
+})
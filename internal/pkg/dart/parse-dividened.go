@@ -7,34 +7,36 @@ import (
 )
 
 type DividendDecision struct {
-	배당구분       string
-	배당종류       string
-	현물자산상세내역   string
-	보통주_1주당배당금 string
-	종류주_1주당배당금 string
-	차등배당여부     string
-	보통주_시가배당율  string
-	종류주_시가배당율  string
-	배당금총액      string
-	배당기준일      string
-	지급예정일자     string
-	주주총회개최여부   string
-	주주총회예정일자   string
-	이사회결의일     string
-	사외이사참석     string
-	사외이사불참     string
-	감사참석여부     string
-	기타사항       string
+	DocType                 string `json:"doc_type"`                  // "현금ㆍ현물배당결정"
+	RceptNo                 string `json:"rcept_no"`                  // (상위 로직에서 주입)
+	DividendCategory        string `json:"dividend_category"`         // 배당구분: 결산배당, 중간배당, 분기배당
+	DividendKind            string `json:"dividend_kind"`             // 배당종류: 현금배당, 현물배당
+	InKindAssetDetail       string `json:"in_kind_asset_detail"`      // 현물자산의 상세내역
+	CommonDividendPerShare  string `json:"common_dividend_per_share"` // 1주당 배당금(원) 보통주식
+	ClassDividendPerShare   string `json:"class_dividend_per_share"`  // 1주당 배당금(원) 종류주식
+	DifferentialDividend    string `json:"differential_dividend"`     // 차등배당 여부
+	CommonDividendYield     string `json:"common_dividend_yield"`     // 시가배당율(%) 보통주식
+	ClassDividendYield      string `json:"class_dividend_yield"`      // 시가배당율(%) 종류주식
+	TotalDividend           string `json:"total_dividend"`            // 배당금총액(원)
+	RecordDate              string `json:"record_date"`               // 배당기준일
+	PaymentDate             string `json:"payment_date"`              // 배당금지급 예정일자
+	ShareholdersMeeting     string `json:"shareholders_meeting"`      // 주주총회 개최여부
+	ShareholdersMeetingDate string `json:"shareholders_meeting_date"` // 주주총회 예정일자
+	BoardDate               string `json:"board_date"`                // 이사회결의일(결정일)
+	OutsideDirsPresent      string `json:"outside_dirs_present"`      // 사외이사 참석(명)
+	OutsideDirsAbsent       string `json:"outside_dirs_absent"`       // 사외이사 불참(명)
+	AuditorPresent          string `json:"auditor_present"`           // 감사(감사위원) 참석여부
+	Notes                   string `json:"notes"`                     // 기타 투자판단과 관련한 중요사항
 	// and for the “종류주식에 대한 배당 관련 사항” subsection, maybe a slice of another struct
-	StockTypeDetails []StockTypeDetail
+	StockTypeDetails []StockTypeDetail `json:"stock_type_details"`
 }
 
 type StockTypeDetail struct {
-	종류주식명   string
-	구분      string
-	배당금_1주당 string
-	시가배당율   string
-	배당금총액   string
+	StockName        string `json:"stock_name"`         // 종류주식명
+	Category         string `json:"category"`           // 구분
+	DividendPerShare string `json:"dividend_per_share"` // 배당금(1주당)
+	DividendYield    string `json:"dividend_yield"`     // 시가배당율
+	TotalDividend    string `json:"total_dividend"`     // 배당금총액
 }
 
 func ParseDividendHTML(htmlStr string) (*DividendDecision, error) {
@@ -43,7 +45,7 @@ func ParseDividendHTML(htmlStr string) (*DividendDecision, error) {
 		return nil, err
 	}
 
-	var d DividendDecision
+	d := DividendDecision{DocType: "현금ㆍ현물배당결정"}
 
 	// find the main table (first one)
 	tblSel := doc.Find("table#XFormD1_Form0_Table0")
@@ -66,37 +68,37 @@ func ParseDividendHTML(htmlStr string) (*DividendDecision, error) {
 
 		switch {
 		case strings.Contains(label, "배당구분"):
-			d.배당구분 = val
+			d.DividendCategory = val
 		case strings.Contains(label, "배당종류"):
-			d.배당종류 = val
+			d.DividendKind = val
 		case strings.Contains(label, "현물자산의 상세내역"):
-			d.현물자산상세내역 = val
+			d.InKindAssetDetail = val
 		case strings.Contains(label, "1주당 배당금"):
 			// careful: this row has subrows. If it’s this label, you may skip and handle subrows
 		case strings.Contains(label, "차등배당 여부"):
-			d.차등배당여부 = val
+			d.DifferentialDividend = val
 		case strings.Contains(label, "배당금총액"):
-			d.배당금총액 = val
+			d.TotalDividend = val
 		case strings.Contains(label, "배당기준일"):
-			d.배당기준일 = val
+			d.RecordDate = val
 		case strings.Contains(label, "배당금지급 예정일자"):
-			d.지급예정일자 = val
+			d.PaymentDate = val
 		case strings.Contains(label, "주주총회 개최여부"):
-			d.주주총회개최여부 = val
+			d.ShareholdersMeeting = val
 		case strings.Contains(label, "주주총회 예정일자"):
-			d.주주총회예정일자 = val
+			d.ShareholdersMeetingDate = val
 		case strings.Contains(label, "이사회결의일"):
-			d.이사회결의일 = val
+			d.BoardDate = val
 		case strings.Contains(label, "사외이사 참석여부"):
 			// here the row is “- 사외이사 참석여부 / 참석(명) / 불참(명)”
 			// the label cell may span two rows; need to check sub-cells
 			// one approach: treat this as two separate rows:
 			// first row: tds[1] → 참석, next row: tds[1] → 불참
-			d.사외이사참석 = val
+			d.OutsideDirsPresent = val
 		case strings.Contains(label, "감사 참석여부"):
-			d.감사참석여부 = val
+			d.AuditorPresent = val
 		case strings.Contains(label, "기타 투자판단과 관련한 중요사항"):
-			d.기타사항 = val
+			d.Notes = val
 		}
 	})
 
@@ -110,49 +112,45 @@ func ParseDividendHTML(htmlStr string) (*DividendDecision, error) {
 			tds := tr.Find("td")
 			if tds.Length() >= 5 {
 				detail := StockTypeDetail{
-					종류주식명:   strings.TrimSpace(tds.Eq(0).Text()),
-					구분:      strings.TrimSpace(tds.Eq(1).Text()),
-					배당금_1주당: strings.TrimSpace(tds.Eq(2).Text()),
-					시가배당율:   strings.TrimSpace(tds.Eq(3).Text()),
-					배당금총액:   strings.TrimSpace(tds.Eq(4).Text()),
+					StockName:        strings.TrimSpace(tds.Eq(0).Text()),
+					Category:         strings.TrimSpace(tds.Eq(1).Text()),
+					DividendPerShare: strings.TrimSpace(tds.Eq(2).Text()),
+					DividendYield:    strings.TrimSpace(tds.Eq(3).Text()),
+					TotalDividend:    strings.TrimSpace(tds.Eq(4).Text()),
 				}
 				d.StockTypeDetails = append(d.StockTypeDetails, detail)
 			}
 		})
 	})
 
-	// Now, for the “1주당 배당금 / 시가배당율” subrows, we need to locate those two rows
-	// The rows with rowspan=2 likely include the “보통주식 / 종류주식” labels.
-	// So a second pass:
+	// “1주당 배당금 / 시가배당율” span two rows: the first has the label, 보통주식 and its value,
+	// the second (rowspan continuation) only 종류주식 and its value
+	group := ""
 	tblSel.Find("tr").Each(func(i int, tr *goquery.Selection) {
 		tds := tr.Find("td")
-		if tds.Length() >= 3 {
+		switch {
+		case tds.Length() >= 3:
 			label := strings.TrimSpace(tds.Eq(0).Text())
-			// If label contains “1주당 배당금” or “시가배당율”
-			if strings.Contains(label, "1주당 배당금") {
-				// next <td> is 보통주 text, third <td> is the value
-				// tds[1] is "보통주식", tds[2] is the value
-				d.보통주_1주당배당금 = strings.TrimSpace(tds.Eq(2).Text())
-			}
-			if strings.Contains(label, "시가배당율") {
-				d.보통주_시가배당율 = strings.TrimSpace(tds.Eq(2).Text())
-			}
-		}
-	})
-	// Another pass for the “종류주식” subrows (second row of those two)
-	tblSel.Find("tr").Each(func(i int, tr *goquery.Selection) {
-		tds := tr.Find("td")
-		if tds.Length() >= 3 {
-			// If the first cell is empty (row continuation), but second cell is 종류주식
-			second := strings.TrimSpace(tds.Eq(1).Text())
-			if second == "종류주식" {
-				d.종류주_1주당배당금 = strings.TrimSpace(tds.Eq(2).Text())
+			switch {
+			case strings.Contains(label, "1주당 배당금"):
+				group = "per_share"
+				d.CommonDividendPerShare = strings.TrimSpace(tds.Eq(2).Text())
+			case strings.Contains(label, "시가배당율"):
+				group = "yield"
+				d.CommonDividendYield = strings.TrimSpace(tds.Eq(2).Text())
+			default:
+				group = ""
 			}
-			// also 시가배당율 second-row
-			if strings.Contains(second, "종류주식") {
-				// maybe next rows contain 시가배당율
-				// you can also detect by context
+		case tds.Length() == 2 && strings.TrimSpace(tds.Eq(0).Text()) == "종류주식":
+			switch group {
+			case "per_share":
+				d.ClassDividendPerShare = strings.TrimSpace(tds.Eq(1).Text())
+			case "yield":
+				d.ClassDividendYield = strings.TrimSpace(tds.Eq(1).Text())
 			}
+			group = ""
+		default:
+			group = ""
 		}
 	})
 
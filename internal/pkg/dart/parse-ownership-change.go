@@ -0,0 +1,193 @@
+package dart
+
+import (
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+type OwnershipChangeDoc struct {
+	DocType  string         `json:"doc_type"` // "임원ㆍ주요주주특정증권등소유상황보고서", "주식등의대량보유상황보고서"
+	RceptNo  string         `json:"rcept_no"` // (상위 로직에서 주입)
+	CorpName string         `json:"corp_name"`
+	Reporter string         `json:"reporter"` // 보고자(대표보고자)
+	Relation string         `json:"relation"` // 보고자와 발행회사의 관계
+	Changes  []HolderChange `json:"changes"`
+}
+
+// HolderChange is a row of 세부변동내역, one change of one holder
+type HolderChange struct {
+	Name         string     `json:"name"`
+	Relation     string     `json:"relation"`
+	Date         *time.Time `json:"date"`
+	Reason       string     `json:"reason"` // 보고사유, 취득/처분 방법
+	ShareKind    string     `json:"share_kind"`
+	SharesBefore *int64     `json:"shares_before"`
+	SharesChange *int64     `json:"shares_change"` // 처분은 음수
+	SharesAfter  *int64     `json:"shares_after"`
+	Price        *int64     `json:"price"` // 취득/처분 단가(원)
+	Note         string     `json:"note"`
+}
+
+// ParseOwnershipChangeHTML parses "임원ㆍ주요주주특정증권등소유상황보고서" and "주식등의대량보유상황보고서".
+// The insider report lists the changes of the reporter only, so holder name and relation fall back to the reporter.
+func ParseOwnershipChangeHTML(raw string, rceptNo string) (*OwnershipChangeDoc, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	out := &OwnershipChangeDoc{
+		RceptNo: rceptNo,
+	}
+
+	title := norm(doc.Find("title").Text())
+	switch {
+	case strings.Contains(title, "대량보유"):
+		out.DocType = "주식등의대량보유상황보고서"
+	default:
+		out.DocType = "임원ㆍ주요주주특정증권등소유상황보고서"
+	}
+
+	doc.Find("table").Each(func(_ int, tbl *goquery.Selection) {
+		// --- 세부변동내역
+		header := norm(tbl.Find("tr").First().Text())
+		if strings.Contains(header, "변동일") && strings.Contains(header, "증감") {
+			out.Changes = append(out.Changes, parseHolderChanges(tbl)...)
+			return
+		}
+
+		// --- 발행회사, 보고자에 관한 사항
+		tbl.Find("tr").Each(func(_ int, tr *goquery.Selection) {
+			tds := tr.Find("th, td")
+			if tds.Length() < 2 {
+				return
+			}
+			label := norm(reBlockNumber.ReplaceAllString(textOf(tds.Eq(tds.Length()-2)), ""))
+			val := cleanVal(textOf(tds.Last()))
+			switch {
+			case label == "회사명" && out.CorpName == "":
+				out.CorpName = val
+			case strings.HasPrefix(label, "성명") && out.Reporter == "":
+				out.Reporter = val
+			case strings.HasSuffix(label, "회사와의관계") && out.Relation == "":
+				out.Relation = val
+			case label == "직위명" && val != "-":
+				// 임원은 회사와의 관계가 임원(등기여부), 직위명, 주요주주로 나뉜다
+				out.Relation = val
+			case label == "주요주주" && val != "-" && out.Relation == "":
+				out.Relation = val
+			}
+		})
+	})
+
+	for i := range out.Changes {
+		if out.Changes[i].Name == "" {
+			out.Changes[i].Name = out.Reporter
+		}
+		if out.Changes[i].Relation == "" {
+			out.Changes[i].Relation = out.Relation
+		}
+	}
+
+	return out, nil
+}
+
+func parseHolderChanges(tbl *goquery.Selection) []HolderChange {
+	cols := map[string]int{}
+	header := tbl.Find("tr").First().Find("th, td")
+	header.Each(func(i int, td *goquery.Selection) {
+		h := norm(textOf(td))
+		switch {
+		case strings.HasPrefix(h, "성명"):
+			cols["name"] = i
+		case strings.Contains(h, "관계"):
+			cols["relation"] = i
+		case strings.HasPrefix(h, "변동일"):
+			cols["date"] = i
+		case strings.HasPrefix(h, "보고사유"), strings.Contains(h, "취득/처분방법"):
+			cols["reason"] = i
+		case strings.Contains(h, "종류"):
+			cols["kind"] = i
+		case strings.HasPrefix(h, "변동전"):
+			cols["before"] = i
+		case strings.HasPrefix(h, "증감"):
+			cols["change"] = i
+		case strings.HasPrefix(h, "변동후"):
+			cols["after"] = i
+		case strings.Contains(h, "단가"):
+			cols["price"] = i
+		case strings.HasPrefix(h, "비고"):
+			cols["note"] = i
+		}
+	})
+
+	var out []HolderChange
+	prev := make([]string, header.Length())
+	tbl.Find("tr").Each(func(i int, tr *goquery.Selection) {
+		tds := tr.Find("td")
+		width := 0
+		tds.Each(func(_ int, td *goquery.Selection) {
+			width += colspanOf(td)
+		})
+		if i == 0 || width == 0 || width > len(prev) {
+			return
+		}
+
+		// 같은 보고자의 여러 변동은 앞쪽 칸(성명, 관계 등)이 rowspan으로 묶인다
+		row := make([]string, len(prev))
+		pos := len(prev) - width
+		copy(row, prev[:pos])
+		tds.Each(func(_ int, td *goquery.Selection) {
+			row[pos] = cleanVal(textOf(td))
+			pos += colspanOf(td)
+		})
+		prev = row
+
+		cell := func(col string) string {
+			if i, ok := cols[col]; ok {
+				return row[i]
+			}
+			return ""
+		}
+
+		// 합계 행 등은 변동일이 없다
+		date := toDatePtr(strings.TrimRight(cell("date"), "*"))
+		if date == nil {
+			return
+		}
+		out = append(out, HolderChange{
+			Name:         cell("name"),
+			Relation:     cell("relation"),
+			Date:         date,
+			Reason:       cell("reason"),
+			ShareKind:    cell("kind"),
+			SharesBefore: toSignedInt64Ptr(cell("before")),
+			SharesChange: toSignedInt64Ptr(cell("change")),
+			SharesAfter:  toSignedInt64Ptr(cell("after")),
+			Price:        toInt64Ptr(cell("price")),
+			Note:         cell("note"),
+		})
+	})
+	return out
+}
+
+func colspanOf(td *goquery.Selection) int {
+	if n := toIntOrNil(td.AttrOr("colspan", "1")); n != nil && *n > 0 {
+		return *n
+	}
+	return 1
+}
+
+// 증감은 "-1,000" 또는 "△1,000"으로 적힌다
+func toSignedInt64Ptr(s string) *int64 {
+	s = strings.TrimSpace(s)
+	neg := strings.HasPrefix(s, "△") || strings.HasPrefix(s, "-") && len(s) > 1
+	s = strings.TrimLeft(s, "△-+")
+	v := toInt64Ptr(s)
+	if v != nil && neg {
+		*v = -*v
+	}
+	return v
+}
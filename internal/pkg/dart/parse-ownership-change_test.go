@@ -0,0 +1,66 @@
+package dart_test
+
+import (
+	"kosis/internal/pkg/dart"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParseOwnershipChangeHTML", func() {
+	mustParse := func(name string) *dart.OwnershipChangeDoc {
+		raw, err := os.ReadFile("testdata/" + name)
+		Expect(err).NotTo(HaveOccurred())
+
+		doc, err := dart.ParseOwnershipChangeHTML(string(raw), "20250616000111")
+		Expect(err).NotTo(HaveOccurred())
+		return doc
+	}
+
+	It("parses an insider ownership report", func() {
+		doc := mustParse("insider-ownership.html")
+
+		Expect(doc.DocType).To(Equal("임원ㆍ주요주주특정증권등소유상황보고서"))
+		Expect(doc.CorpName).To(Equal("주식회사 케이엠"))
+		Expect(doc.Reporter).To(Equal("홍길동"))
+		Expect(doc.Relation).To(Equal("대표이사"))
+
+		Expect(doc.Changes).To(HaveLen(3))
+		first := doc.Changes[0]
+		Expect(first.Name).To(Equal("홍길동"))
+		Expect(first.Relation).To(Equal("대표이사"))
+		Expect(first.Date.Format("2006-01-02")).To(Equal("2025-06-10"))
+		Expect(first.Reason).To(Equal("장내매수(+)"))
+		Expect(first.ShareKind).To(Equal("보통주"))
+		Expect(*first.SharesBefore).To(Equal(int64(120000)))
+		Expect(*first.SharesChange).To(Equal(int64(10000)))
+		Expect(*first.SharesAfter).To(Equal(int64(130000)))
+		Expect(*first.Price).To(Equal(int64(15200)))
+
+		Expect(*doc.Changes[2].SharesChange).To(Equal(int64(-2000)))
+	})
+
+	It("parses a major holding report with rowspan holders", func() {
+		doc := mustParse("major-holding.html")
+
+		Expect(doc.DocType).To(Equal("주식등의대량보유상황보고서"))
+		Expect(doc.Reporter).To(Equal("주식회사 에이치홀딩스"))
+		Expect(doc.Relation).To(Equal("최대주주"))
+
+		Expect(doc.Changes).To(HaveLen(3))
+		Expect(doc.Changes[0].Relation).To(Equal("본인"))
+		Expect(doc.Changes[0].Reason).To(Equal("유상신주취득(+)"))
+
+		sale := doc.Changes[1]
+		Expect(sale.Name).To(Equal("주식회사 에이치홀딩스"))
+		Expect(sale.Date.Format("2006-01-02")).To(Equal("2025-07-30"))
+		Expect(*sale.SharesChange).To(Equal(int64(-500000)))
+		Expect(*sale.Price).To(Equal(int64(6100)))
+		Expect(sale.Note).To(Equal("시간외 대량매매"))
+
+		Expect(doc.Changes[2].Name).To(Equal("김철수"))
+		Expect(doc.Changes[2].Relation).To(Equal("특수관계인"))
+		Expect(*doc.Changes[2].SharesBefore).To(Equal(int64(0)))
+	})
+})
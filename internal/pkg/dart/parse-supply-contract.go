@@ -0,0 +1,207 @@
+package dart
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+type SupplyContractDoc struct {
+	DocType              string           `json:"doc_type"` // "단일판매ㆍ공급계약체결"
+	RceptNo              string           `json:"rcept_no"` // (상위 로직에서 주입)
+	ContractKind         string           `json:"contract_kind"`
+	ContractName         string           `json:"contract_name"`
+	AmountKRW            *int64           `json:"amount_krw"`
+	RecentSalesKRW       *int64           `json:"recent_sales_krw"`
+	RatioToSales         *float64         `json:"ratio_to_sales"` // %
+	IsLargeCorp          *bool            `json:"is_large_corp"`
+	Counterparty         string           `json:"counterparty"`
+	CounterpartyRelation string           `json:"counterparty_relation"`
+	CounterpartySalesKRW *int64           `json:"counterparty_sales_krw"`
+	Region               string           `json:"region"`
+	TermFrom             *time.Time       `json:"term_from"`
+	TermTo               *time.Time       `json:"term_to"`
+	Conditions           string           `json:"conditions"`
+	ContractDate         *time.Time       `json:"contract_date"`
+	Notes                string           `json:"notes"`
+	Amendment            *SupplyAmendment `json:"amendment"` // 정정신고일 때만
+}
+
+// SupplyAmendment holds the 정정전/정정후 values of an amended supply contract
+type SupplyAmendment struct {
+	Amendment
+	PrevAmountKRW    *int64     `json:"prev_amount_krw"`
+	NewAmountKRW     *int64     `json:"new_amount_krw"`
+	PrevRatioToSales *float64   `json:"prev_ratio_to_sales"`
+	NewRatioToSales  *float64   `json:"new_ratio_to_sales"`
+	PrevTermTo       *time.Time `json:"prev_term_to"`
+	NewTermTo        *time.Time `json:"new_term_to"`
+}
+
+// "2. 계약내역" 같은 블록 번호
+var reBlockNumber = regexp.MustCompile(`^\s*\d+\.\s*`)
+
+// ParseSupplyContractHTML parses "단일판매ㆍ공급계약체결" and merges the 정정후 values of an amendment.
+// The form ids differ between filings, so the tables are found by their contents.
+func ParseSupplyContractHTML(raw string, rceptNo string) (*SupplyContractDoc, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	out := &SupplyContractDoc{
+		DocType: "단일판매ㆍ공급계약체결",
+		RceptNo: rceptNo,
+	}
+
+	// --- Amendment (정정) 수집
+	amendment := parseAmendment(doc)
+	if amendment != nil {
+		out.Amendment = &SupplyAmendment{Amendment: *amendment}
+		if c, ok := amendment.Corrected("계약금액"); ok {
+			out.Amendment.PrevAmountKRW = toInt64Ptr(c.Before)
+			out.Amendment.NewAmountKRW = toInt64Ptr(c.After)
+		}
+		if c, ok := amendment.Corrected("매출액대비"); ok {
+			out.Amendment.PrevRatioToSales = toFloat64Ptr(c.Before)
+			out.Amendment.NewRatioToSales = toFloat64Ptr(c.After)
+		}
+		if c, ok := amendment.Corrected("종료일"); ok {
+			out.Amendment.PrevTermTo = toDatePtr(c.Before)
+			out.Amendment.NewTermTo = toDatePtr(c.After)
+		}
+	}
+
+	// --- 본문 메인 테이블: 계약금액이 있는 표
+	var base *goquery.Selection
+	doc.Find("table").EachWithBreak(func(_ int, tbl *goquery.Selection) bool {
+		text := norm(tbl.Text())
+		if strings.Contains(text, "계약금액") && strings.Contains(text, "계약상대") && !isAmendmentTable(tbl) {
+			base = tbl
+			return false
+		}
+		return true
+	})
+	if base == nil {
+		return out, nil
+	}
+
+	block := ""
+	pendingNotes := false
+	base.Find("tr").Each(func(_ int, tr *goquery.Selection) {
+		tds := tr.Find("td")
+		if tds.Length() == 0 {
+			return
+		}
+
+		first := cleanVal(textOf(tds.First()))
+		val := cleanVal(textOf(tds.Last()))
+
+		if pendingNotes {
+			out.Notes = val
+			pendingNotes = false
+			return
+		}
+
+		label := ""
+		if reBlockNumber.MatchString(first) {
+			block = norm(reBlockNumber.ReplaceAllString(first, ""))
+		} else if tds.Length() < 3 {
+			label = norm(strings.TrimLeft(first, "- "))
+		}
+		if tds.Length() >= 3 {
+			if sub := norm(textOf(tds.Eq(tds.Length() - 2))); sub != "" {
+				label = sub
+			} else if !reBlockNumber.MatchString(first) {
+				label = norm(strings.TrimLeft(first, "- "))
+			}
+		}
+		if tds.Length() == 1 {
+			val = ""
+		}
+
+		if strings.HasPrefix(block, "기타투자판단과관련한중요사항") && label == "" && val == "" {
+			pendingNotes = true
+			return
+		}
+
+		assignSupply(out, block, label, val)
+	})
+
+	// 정정후 값 우선
+	if c, ok := amendment.Corrected("계약금액"); ok {
+		out.AmountKRW = toInt64Ptr(c.After)
+	}
+	if c, ok := amendment.Corrected("매출액대비"); ok {
+		out.RatioToSales = toFloat64Ptr(c.After)
+	}
+	if c, ok := amendment.Corrected("최근매출액"); ok {
+		out.RecentSalesKRW = toInt64Ptr(c.After)
+	}
+	if c, ok := amendment.Corrected("시작일"); ok {
+		out.TermFrom = toDatePtr(c.After)
+	}
+	if c, ok := amendment.Corrected("종료일"); ok {
+		out.TermTo = toDatePtr(c.After)
+	}
+
+	return out, nil
+}
+
+// 블록+라벨별로 out에 주입
+func assignSupply(out *SupplyContractDoc, block, label, value string) {
+	switch {
+	case strings.HasPrefix(block, "판매공급계약구분"), strings.HasPrefix(block, "판매공급계약내용"):
+		switch label {
+		case "":
+			if strings.HasPrefix(block, "판매공급계약구분") {
+				out.ContractKind = value
+			} else {
+				out.ContractName = value
+			}
+		case "체결계약명":
+			out.ContractName = value
+		}
+	case strings.HasPrefix(block, "계약내역"):
+		switch {
+		case strings.HasPrefix(label, "계약금액"):
+			out.AmountKRW = toInt64Ptr(value)
+		case strings.HasPrefix(label, "최근매출액"):
+			out.RecentSalesKRW = toInt64Ptr(value)
+		case strings.HasPrefix(label, "매출액대비"):
+			out.RatioToSales = toFloat64Ptr(value)
+		case label == "대규모법인여부":
+			out.IsLargeCorp = boolFromKorean(value)
+		}
+	case strings.HasPrefix(block, "계약상대"):
+		switch {
+		case label == "":
+			out.Counterparty = value
+		case strings.HasPrefix(label, "회사와의관계"):
+			out.CounterpartyRelation = value
+		case strings.HasPrefix(label, "최근매출액"):
+			out.CounterpartySalesKRW = toInt64Ptr(value)
+		}
+	case strings.HasPrefix(block, "판매공급지역"):
+		out.Region = value
+	case strings.HasPrefix(block, "계약기간"):
+		switch label {
+		case "시작일":
+			out.TermFrom = toDatePtr(value)
+		case "종료일":
+			out.TermTo = toDatePtr(value)
+		}
+	case strings.HasPrefix(block, "주요계약조건"):
+		if label == "" {
+			out.Conditions = value
+		}
+	case strings.HasPrefix(block, "계약수주일자"):
+		out.ContractDate = toDatePtr(value)
+	case strings.HasPrefix(block, "기타투자판단과관련한중요사항"):
+		if label == "" {
+			out.Notes = value
+		}
+	}
+}
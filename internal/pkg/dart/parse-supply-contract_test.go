@@ -0,0 +1,65 @@
+package dart_test
+
+import (
+	"kosis/internal/pkg/dart"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParseSupplyContractHTML", func() {
+	mustParse := func(name string) *dart.SupplyContractDoc {
+		raw, err := os.ReadFile("testdata/" + name)
+		Expect(err).NotTo(HaveOccurred())
+
+		doc, err := dart.ParseSupplyContractHTML(string(raw), "20241230000789")
+		Expect(err).NotTo(HaveOccurred())
+		return doc
+	}
+
+	It("parses a supply contract", func() {
+		doc := mustParse("supply-contract.html")
+
+		Expect(doc.RceptNo).To(Equal("20241230000789"))
+		Expect(doc.ContractKind).To(Equal("상품공급"))
+		Expect(doc.ContractName).To(Equal("2차전지 양극재 공급계약"))
+		Expect(*doc.AmountKRW).To(Equal(int64(1234567890000)))
+		Expect(*doc.RecentSalesKRW).To(Equal(int64(51864800000000)))
+		Expect(*doc.RatioToSales).To(Equal(2.38))
+		Expect(*doc.IsLargeCorp).To(BeTrue())
+		Expect(doc.Counterparty).To(Equal("General Motors Holdings LLC"))
+		Expect(doc.CounterpartySalesKRW).To(BeNil())
+		Expect(doc.Region).To(Equal("미국"))
+		Expect(doc.TermFrom.Format("2006-01-02")).To(Equal("2025-01-01"))
+		Expect(doc.TermTo.Format("2006-01-02")).To(Equal("2030-12-31"))
+		Expect(doc.ContractDate.Format("2006-01-02")).To(Equal("2024-12-30"))
+		Expect(doc.Notes).To(Equal("상기 계약금액은 예상 판매수량과 단가를 기준으로 산정한 금액입니다."))
+		Expect(doc.Amendment).To(BeNil())
+	})
+
+	It("merges the corrected values of an amendment", func() {
+		doc := mustParse("supply-contract-amendment.html")
+
+		Expect(*doc.AmountKRW).To(Equal(int64(1500000000000)))
+		Expect(*doc.RatioToSales).To(Equal(2.89))
+		Expect(doc.TermTo.Format("2006-01-02")).To(Equal("2032-12-31"))
+		Expect(doc.Counterparty).To(Equal("General Motors Holdings LLC"))
+
+		Expect(doc.Amendment).NotTo(BeNil())
+		Expect(doc.Amendment.Date.Format("2006-01-02")).To(Equal("2025-06-02"))
+		Expect(doc.Amendment.Reason).To(Equal("계약기간 연장 및 계약금액 변경"))
+		Expect(*doc.Amendment.PrevAmountKRW).To(Equal(int64(1234567890000)))
+		Expect(*doc.Amendment.NewAmountKRW).To(Equal(int64(1500000000000)))
+		Expect(*doc.Amendment.PrevRatioToSales).To(Equal(2.38))
+		Expect(*doc.Amendment.NewRatioToSales).To(Equal(2.89))
+		Expect(doc.Amendment.PrevTermTo.Format("2006-01-02")).To(Equal("2030-12-31"))
+		Expect(doc.Amendment.NewTermTo.Format("2006-01-02")).To(Equal("2032-12-31"))
+		Expect(doc.Amendment.Changes).To(HaveLen(3))
+		Expect(doc.Amendment.Changes[0]).To(Equal(dart.AmendmentChange{
+			Item:   "2. 계약내역 - 계약금액(원)",
+			Before: "1,234,567,890,000",
+			After:  "1,500,000,000,000",
+		}))
+	})
+})
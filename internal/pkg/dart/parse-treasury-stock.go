@@ -0,0 +1,217 @@
+package dart
+
+import (
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Treasury stock events
+const (
+	TreasuryStockAcquisition  = "acquisition"    // 자기주식취득결정
+	TreasuryStockDisposal     = "disposal"       // 자기주식처분결정
+	TreasuryStockCancellation = "cancellation"   // 자기주식소각결정
+	TreasuryStockTrust        = "trust_contract" // 자기주식취득신탁계약체결결정
+)
+
+type TreasuryStockDoc struct {
+	DocType          string     `json:"doc_type"` // "자기주식취득결정" 등
+	RceptNo          string     `json:"rcept_no"` // (상위 로직에서 주입)
+	Event            string     `json:"event"`
+	CommonShares     *int64     `json:"common_shares"` // 취득(처분, 소각)예정 주식수, 신탁계약은 없음
+	OtherShares      *int64     `json:"other_shares"`
+	AmountKRW        *int64     `json:"amount_krw"`      // 취득(처분, 소각)예정금액, 신탁 계약금액
+	PricePerShare    *int64     `json:"price_per_share"` // 처분 대상 주식가격
+	ParValue         *int64     `json:"par_value"`       // 소각 1주당 가액
+	PeriodFrom       *time.Time `json:"period_from"`     // 취득예상기간, 처분예정기간, 계약기간
+	PeriodTo         *time.Time `json:"period_to"`
+	Purpose          string     `json:"purpose"`
+	Method           string     `json:"method"`
+	Broker           string     `json:"broker"` // 위탁투자중개업자, 계약체결기관
+	CancellationDate *time.Time `json:"cancellation_date"`
+	BoardDate        *time.Time `json:"board_date"` // 취득(처분)결정일, 이사회결의일
+	Amendment        *Amendment `json:"amendment"`  // 정정신고일 때만
+}
+
+// ReferencePrice returns the price per share the decision is based on, derived from the amount when the form has no price
+func (d *TreasuryStockDoc) ReferencePrice() *int64 {
+	if d.PricePerShare != nil {
+		return d.PricePerShare
+	}
+	if d.AmountKRW == nil {
+		return nil
+	}
+
+	var shares int64
+	for _, s := range []*int64{d.CommonShares, d.OtherShares} {
+		if s != nil {
+			shares += *s
+		}
+	}
+	if shares == 0 {
+		return nil
+	}
+
+	price := *d.AmountKRW / shares
+	return &price
+}
+
+// ParseTreasuryStockHTML parses "자기주식취득결정", "자기주식처분결정", "자기주식소각결정" and "자기주식취득신탁계약체결결정".
+// The event is told from the blocks of the form and the 정정후 values of an amendment are merged.
+func ParseTreasuryStockHTML(raw string, rceptNo string) (*TreasuryStockDoc, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	out := &TreasuryStockDoc{
+		RceptNo:   rceptNo,
+		Amendment: parseAmendment(doc),
+	}
+
+	// 금액은 주식 종류별로 모아 합산한다, 정정후 값은 같은 종류를 덮어쓴다
+	amounts := map[string]int64{}
+
+	doc.Find("table").Each(func(_ int, tbl *goquery.Selection) {
+		if isAmendmentTable(tbl) {
+			return
+		}
+
+		block, class := "", ""
+		tbl.Find("tr").Each(func(_ int, tr *goquery.Selection) {
+			tds := tr.Find("td")
+			if tds.Length() < 2 {
+				return
+			}
+
+			first := cleanVal(textOf(tds.First()))
+			if reBlockNumber.MatchString(first) {
+				block = norm(reBlockNumber.ReplaceAllString(first, ""))
+				class = ""
+			}
+			tds.Slice(0, tds.Length()-1).Each(func(_ int, td *goquery.Selection) {
+				if c := shareClassOf(textOf(td)); c != "" {
+					class = c
+				}
+			})
+
+			label := ""
+			if l := textOf(tds.Eq(tds.Length() - 2)); !reBlockNumber.MatchString(l) {
+				label = norm(l)
+			}
+
+			assignTreasury(out, amounts, block, class, label, cleanVal(textOf(tds.Last())))
+		})
+	})
+
+	// 정정후 값 우선, 정정사항은 "3. 취득예상기간 - 종료일" 꼴
+	if out.Amendment != nil {
+		for _, c := range out.Amendment.Changes {
+			parts := strings.Split(c.Item, " - ")
+			block := norm(reBlockNumber.ReplaceAllString(parts[0], ""))
+			class, label := "", ""
+			for _, part := range parts[1:] {
+				if cls := shareClassOf(part); cls != "" {
+					class = cls
+				}
+			}
+			if len(parts) > 1 {
+				label = norm(parts[len(parts)-1])
+			}
+			assignTreasury(out, amounts, block, class, label, c.After)
+		}
+	}
+
+	if len(amounts) > 0 {
+		var sum int64
+		for _, v := range amounts {
+			sum += v
+		}
+		out.AmountKRW = &sum
+	}
+
+	switch out.Event {
+	case TreasuryStockAcquisition:
+		out.DocType = "자기주식취득결정"
+	case TreasuryStockDisposal:
+		out.DocType = "자기주식처분결정"
+	case TreasuryStockCancellation:
+		out.DocType = "자기주식소각결정"
+	case TreasuryStockTrust:
+		out.DocType = "자기주식취득신탁계약체결결정"
+	}
+
+	return out, nil
+}
+
+// 블록+주식종류+라벨별로 out에 주입
+func assignTreasury(out *TreasuryStockDoc, amounts map[string]int64, block, class, label, value string) {
+	pick := func(common, other **int64) {
+		switch class {
+		case "common":
+			*common = toInt64Ptr(value)
+		case "other":
+			*other = toInt64Ptr(value)
+		}
+	}
+	amount := func() {
+		if v := toInt64Ptr(value); v != nil {
+			amounts[class] = *v
+		}
+	}
+	period := func() {
+		switch label {
+		case "시작일":
+			out.PeriodFrom = toDatePtr(value)
+		case "종료일":
+			out.PeriodTo = toDatePtr(value)
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(block, "취득예정주식"):
+		out.Event = TreasuryStockAcquisition
+		pick(&out.CommonShares, &out.OtherShares)
+	case strings.HasPrefix(block, "처분예정주식"):
+		out.Event = TreasuryStockDisposal
+		pick(&out.CommonShares, &out.OtherShares)
+	case strings.HasPrefix(block, "소각할주식의종류와수"):
+		out.Event = TreasuryStockCancellation
+		pick(&out.CommonShares, &out.OtherShares)
+	case strings.HasPrefix(block, "계약금액"):
+		out.Event = TreasuryStockTrust
+		amount()
+	case strings.HasPrefix(block, "취득예정금액"), strings.HasPrefix(block, "처분예정금액"), strings.HasPrefix(block, "소각예정금액"):
+		amount()
+	case strings.HasPrefix(block, "처분대상주식가격"):
+		if class == "common" || out.PricePerShare == nil {
+			if v := toInt64Ptr(value); v != nil {
+				out.PricePerShare = v
+			}
+		}
+	case strings.HasPrefix(block, "1주당가액"):
+		out.ParValue = toInt64Ptr(value)
+	case strings.HasPrefix(block, "취득예상기간"), strings.HasPrefix(block, "처분예정기간"), strings.HasPrefix(block, "계약기간"):
+		period()
+	case strings.HasPrefix(block, "취득목적"), strings.HasPrefix(block, "처분목적"), strings.HasPrefix(block, "계약목적"):
+		out.Purpose = value
+	case strings.HasPrefix(block, "취득방법"), strings.HasPrefix(block, "소각할주식의취득방법"):
+		out.Method = value
+	case strings.HasPrefix(block, "처분방법"):
+		// 시장을 통한 매도, 시간외대량매매, 장외처분, 기타 중 수량이 있는 방법
+		method := strings.TrimSuffix(label, "주")
+		if v := toInt64Ptr(value); v != nil && *v > 0 && !strings.Contains(out.Method, method) {
+			if out.Method != "" {
+				out.Method += ", "
+			}
+			out.Method += method
+		}
+	case strings.HasPrefix(block, "위탁투자중개업자"), strings.HasPrefix(block, "계약체결기관"):
+		out.Broker = value
+	case strings.HasPrefix(block, "소각예정일"):
+		out.CancellationDate = toDatePtr(value)
+	case strings.HasPrefix(block, "취득결정일"), strings.HasPrefix(block, "처분결정일"), strings.HasPrefix(block, "이사회결의일"):
+		out.BoardDate = toDatePtr(value)
+	}
+}
@@ -0,0 +1,74 @@
+package dart_test
+
+import (
+	"kosis/internal/pkg/dart"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParseTreasuryStockHTML", func() {
+	mustParse := func(name string) *dart.TreasuryStockDoc {
+		raw, err := os.ReadFile("testdata/" + name)
+		Expect(err).NotTo(HaveOccurred())
+
+		doc, err := dart.ParseTreasuryStockHTML(string(raw), "20250318000222")
+		Expect(err).NotTo(HaveOccurred())
+		return doc
+	}
+
+	It("parses an acquisition", func() {
+		doc := mustParse("treasury-acquisition.html")
+
+		Expect(doc.DocType).To(Equal("자기주식취득결정"))
+		Expect(doc.Event).To(Equal(dart.TreasuryStockAcquisition))
+		Expect(*doc.CommonShares).To(Equal(int64(1000000)))
+		Expect(doc.OtherShares).To(BeNil())
+		Expect(*doc.AmountKRW).To(Equal(int64(70000000000)))
+		Expect(doc.PeriodFrom.Format("2006-01-02")).To(Equal("2025-03-19"))
+		Expect(doc.PeriodTo.Format("2006-01-02")).To(Equal("2025-06-18"))
+		Expect(doc.Purpose).To(Equal("주주가치 제고"))
+		Expect(doc.Method).To(Equal("유가증권시장을 통한 장내 직접 취득"))
+		Expect(doc.Broker).To(Equal("미래에셋증권㈜"))
+		Expect(doc.BoardDate.Format("2006-01-02")).To(Equal("2025-03-18"))
+		Expect(*doc.ReferencePrice()).To(Equal(int64(70000)))
+	})
+
+	It("parses a disposal", func() {
+		doc := mustParse("treasury-disposal.html")
+
+		Expect(doc.Event).To(Equal(dart.TreasuryStockDisposal))
+		Expect(*doc.CommonShares).To(Equal(int64(300000)))
+		Expect(*doc.PricePerShare).To(Equal(int64(68500)))
+		Expect(*doc.AmountKRW).To(Equal(int64(20550000000)))
+		Expect(doc.Purpose).To(Equal("임직원 성과보상"))
+		Expect(doc.Method).To(Equal("장외처분"))
+		Expect(doc.BoardDate.Format("2006-01-02")).To(Equal("2025-03-28"))
+	})
+
+	It("parses a cancellation", func() {
+		doc := mustParse("treasury-cancellation.html")
+
+		Expect(doc.Event).To(Equal(dart.TreasuryStockCancellation))
+		Expect(*doc.CommonShares).To(Equal(int64(1000000)))
+		Expect(*doc.AmountKRW).To(Equal(int64(71200000000)))
+		Expect(*doc.ParValue).To(Equal(int64(500)))
+		Expect(*doc.ReferencePrice()).To(Equal(int64(71200)))
+		Expect(doc.Method).To(Equal("기취득 자기주식"))
+		Expect(doc.CancellationDate.Format("2006-01-02")).To(Equal("2025-07-10"))
+		Expect(doc.BoardDate.Format("2006-01-02")).To(Equal("2025-07-03"))
+	})
+
+	It("parses a trust contract", func() {
+		doc := mustParse("treasury-trust.html")
+
+		Expect(doc.DocType).To(Equal("자기주식취득신탁계약체결결정"))
+		Expect(doc.Event).To(Equal(dart.TreasuryStockTrust))
+		Expect(*doc.AmountKRW).To(Equal(int64(10000000000)))
+		Expect(doc.CommonShares).To(BeNil())
+		Expect(doc.ReferencePrice()).To(BeNil())
+		Expect(doc.PeriodTo.Format("2006-01-02")).To(Equal("2025-11-01"))
+		Expect(doc.Broker).To(Equal("한국투자증권"))
+	})
+})
@@ -0,0 +1,175 @@
+package dart
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// defined error that no parser handles the report name
+var ErrNoParser = errors.New("no parser for report")
+
+// defined error that a parser matched but missed fields the analysis cannot do without
+var ErrIncompleteParse = errors.New("parsed document is incomplete")
+
+// reportParser runs a dedicated HTML parser and tells whether its output is usable
+type reportParser struct {
+	name     string
+	parse    func(raw, rceptNo string) (any, error)
+	complete func(v any) bool
+}
+
+// reportRoute sends the reports whose normalized report_nm contains name to a parser
+type reportRoute struct {
+	name   string
+	parser reportParser
+}
+
+// reportParsers is matched on the normalized report_nm, see norm. Names such as
+// 주요사항보고서(타법인주식및출자증권취득결정) or ...(자율공시) match on containment, so the first
+// route that matches wins and a name containing another one, e.g. 자기주식취득신탁계약체결결정
+// and 자기주식취득결정, must come before it.
+var reportParsers = []reportRoute{
+	{"타법인주식및출자증권취득결정", reportParser{
+		name: "acquisition",
+		parse: func(raw, rceptNo string) (any, error) {
+			return ParseAcquisitionHTML(raw, rceptNo)
+		},
+		complete: func(v any) bool {
+			d := v.(*AcquisitionDoc)
+			return d.CorpName != "" && (d.Acquire.Shares != nil || d.Acquire.AmountKRW != nil)
+		},
+	}},
+	{"타법인주식및출자증권처분결정", reportParser{
+		name: "disposal",
+		parse: func(raw, rceptNo string) (any, error) {
+			return ParseDisposalHTML(raw, rceptNo)
+		},
+		complete: func(v any) bool {
+			d := v.(*DisposalDoc)
+			return d.CorpName != "" && (d.Disposal.Shares != nil || d.Disposal.AmountKRW != nil)
+		},
+	}},
+	{"단일판매공급계약체결", reportParser{
+		name: "supply_contract",
+		parse: func(raw, rceptNo string) (any, error) {
+			return ParseSupplyContractHTML(raw, rceptNo)
+		},
+		complete: func(v any) bool {
+			d := v.(*SupplyContractDoc)
+			return d.AmountKRW != nil && d.Counterparty != ""
+		},
+	}},
+	{"전환사채권발행결정", bondIssuanceParser},
+	{"신주인수권부사채권발행결정", bondIssuanceParser},
+	{"교환사채권발행결정", bondIssuanceParser},
+	{"유상증자결정", reportParser{
+		name: "capital_increase",
+		parse: func(raw, rceptNo string) (any, error) {
+			return ParseCapitalIncreaseHTML(raw, rceptNo)
+		},
+		complete: func(v any) bool {
+			d := v.(*CapitalIncreaseDoc)
+			return d.Method != "" && (d.NewCommonShares != nil || d.NewOtherShares != nil)
+		},
+	}},
+	{"임원주요주주특정증권등소유상황보고서", ownershipChangeParser},
+	{"주식등의대량보유상황보고서", ownershipChangeParser},
+	{"자기주식취득신탁계약체결결정", treasuryStockParser},
+	{"자기주식취득결정", treasuryStockParser},
+	{"자기주식처분결정", treasuryStockParser},
+	{"자기주식소각결정", treasuryStockParser},
+	{"현금현물배당결정", reportParser{
+		name: "dividend",
+		parse: func(raw, rceptNo string) (any, error) {
+			d, err := ParseDividendHTML(raw)
+			if err != nil {
+				return nil, err
+			}
+			d.RceptNo = rceptNo
+			return d, nil
+		},
+		complete: func(v any) bool {
+			d := v.(*DividendDecision)
+			return d.DividendKind != "" && d.CommonDividendPerShare != "" && d.RecordDate != ""
+		},
+	}},
+}
+
+// the three equity-linked bond decisions share their form
+var bondIssuanceParser = reportParser{
+	name: "bond_issuance",
+	parse: func(raw, rceptNo string) (any, error) {
+		return ParseBondIssuanceHTML(raw, rceptNo)
+	},
+	complete: func(v any) bool {
+		d := v.(*BondIssuanceDoc)
+		return d.BondKind != "" && d.FaceAmountKRW != nil && d.ExercisePrice != nil
+	},
+}
+
+// insider and major holding reports share the 세부변동내역 table
+var ownershipChangeParser = reportParser{
+	name: "ownership_change",
+	parse: func(raw, rceptNo string) (any, error) {
+		return ParseOwnershipChangeHTML(raw, rceptNo)
+	},
+	complete: func(v any) bool {
+		return len(v.(*OwnershipChangeDoc).Changes) > 0
+	},
+}
+
+// treasury stock acquisitions, disposals, cancellations and trust contracts
+var treasuryStockParser = reportParser{
+	name: "treasury_stock",
+	parse: func(raw, rceptNo string) (any, error) {
+		return ParseTreasuryStockHTML(raw, rceptNo)
+	},
+	complete: func(v any) bool {
+		d := v.(*TreasuryStockDoc)
+		return d.Event != "" && (d.AmountKRW != nil || d.CommonShares != nil || d.OtherShares != nil)
+	},
+}
+
+// report names carry prefixes such as [기재정정], which may repeat the decision name
+var reReportNamePrefix = regexp.MustCompile(`^\s*\[[^\]]*\]`)
+
+// ParserFor returns the name of the parser that handles the report name, if any
+func ParserFor(reportName string) (string, bool) {
+	p, ok := parserFor(reportName)
+	if !ok {
+		return "", false
+	}
+	return p.name, true
+}
+
+// ParseFiling runs the dedicated parser for the report name. It returns ErrNoParser when no
+// parser handles the report and ErrIncompleteParse when the parser misses critical fields,
+// in both cases the caller is expected to fall back to a generic analysis.
+func ParseFiling(reportName, raw, rceptNo string) (any, error) {
+	p, ok := parserFor(reportName)
+	if !ok {
+		return nil, ErrNoParser
+	}
+
+	v, err := p.parse(raw, rceptNo)
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.complete(v) {
+		return nil, ErrIncompleteParse
+	}
+
+	return v, nil
+}
+
+func parserFor(reportName string) (reportParser, bool) {
+	name := BaseReportName(reportName)
+	for _, route := range reportParsers {
+		if strings.Contains(name, route.name) {
+			return route.parser, true
+		}
+	}
+	return reportParser{}, false
+}
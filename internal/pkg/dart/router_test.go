@@ -0,0 +1,82 @@
+package dart_test
+
+import (
+	"kosis/internal/pkg/dart"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParseFiling", func() {
+	mustReadFile := func(name string) string {
+		b, err := os.ReadFile("testdata/" + name)
+		Expect(err).NotTo(HaveOccurred())
+		return string(b)
+	}
+
+	It("routes report names to their parser", func() {
+		for name, parser := range map[string]string{
+			"타법인주식및출자증권취득결정":          "acquisition",
+			"[기재정정]타법인주식및출자증권취득결정":    "acquisition",
+			"주요사항보고서(타법인주식및출자증권처분결정)": "disposal",
+			"현금ㆍ현물배당결정":               "dividend",
+			"현금·현물배당결정(자회사의 주요경영사항)":  "dividend",
+			"주요사항보고서(자기주식취득신탁계약체결결정)": "treasury_stock",
+			"주요사항보고서(교환사채권발행결정)":      "bond_issuance",
+		} {
+			got, ok := dart.ParserFor(name)
+			Expect(ok).To(BeTrue(), name)
+			Expect(got).To(Equal(parser), name)
+		}
+
+		_, ok := dart.ParserFor("분기보고서 (2025.09)")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("parses a dividend decision", func() {
+		v, err := dart.ParseFiling("현금ㆍ현물배당결정", mustReadFile("dividend-decision.html"), "20250731000123")
+		Expect(err).NotTo(HaveOccurred())
+
+		d, ok := v.(*dart.DividendDecision)
+		Expect(ok).To(BeTrue())
+		Expect(d.RceptNo).To(Equal("20250731000123"))
+		Expect(d.DividendCategory).To(Equal("분기배당"))
+		Expect(d.DividendKind).To(Equal("현금배당"))
+		Expect(d.CommonDividendPerShare).To(Equal("370"))
+		Expect(d.ClassDividendPerShare).To(Equal("370"))
+		Expect(d.CommonDividendYield).To(Equal("0.5"))
+		Expect(d.ClassDividendYield).To(Equal("0.6"))
+		Expect(d.TotalDividend).To(Equal("2,452,976,462,800"))
+		Expect(d.RecordDate).To(Equal("2025-06-30"))
+		Expect(d.PaymentDate).To(Equal("2025-08-20"))
+	})
+
+	It("parses an acquisition decision", func() {
+		v, err := dart.ParseFiling("타법인주식및출자증권취득결정", mustReadFile("acquisition-decision.html"), "20250901000456")
+		Expect(err).NotTo(HaveOccurred())
+
+		d, ok := v.(*dart.AcquisitionDoc)
+		Expect(ok).To(BeTrue())
+		Expect(d.CorpName).To(Equal("에이비씨 주식회사"))
+		Expect(*d.Acquire.Shares).To(Equal(int64(510000)))
+		Expect(*d.Acquire.AmountKRW).To(Equal(int64(25500000000)))
+		Expect(*d.Post.Ratio).To(Equal(51.0))
+	})
+
+	It("returns ErrIncompleteParse when critical fields are missing", func() {
+		_, err := dart.ParseFiling("현금ㆍ현물배당결정", "<DOCUMENT><BODY><P>배당</P></BODY></DOCUMENT>", "20250731000123")
+		Expect(err).To(MatchError(dart.ErrIncompleteParse))
+
+		_, err = dart.ParseFiling("타법인주식및출자증권처분결정", "<DOCUMENT></DOCUMENT>", "20250731000123")
+		Expect(err).To(MatchError(dart.ErrIncompleteParse))
+	})
+
+	It("returns ErrNoParser for other reports", func() {
+		_, err := dart.ParseFiling("최대주주등소유주식변동신고서", "<DOCUMENT></DOCUMENT>", "20250731000123")
+		Expect(err).To(MatchError(dart.ErrNoParser))
+
+		_, err = dart.ParseFiling("주요사항보고서(유무상증자결정)", "<DOCUMENT></DOCUMENT>", "20250731000123")
+		Expect(err).To(MatchError(dart.ErrNoParser))
+	})
+})
@@ -0,0 +1,161 @@
+package dart
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// DefaultDailyLimit is the number of calls OpenDART allows a key per day
+const DefaultDailyLimit = 20000
+
+// ErrQuotaExceeded is returned instead of calling DART once the daily quota of the key is used up
+var ErrQuotaExceeded = errors.New("daily DART quota exceeded")
+
+// kst is the time zone the daily quota of DART resets in
+var kst = time.FixedZone("KST", 9*60*60)
+
+// QuotaStore persists the calls made with a key per day, so every worker shares the quota
+type QuotaStore interface {
+	// Use counts a call on day (YYYY-MM-DD in KST) and returns ErrQuotaExceeded without
+	// counting it when limit calls were already made or the day was exhausted
+	Use(ctx context.Context, day string, limit int) error
+	// Exhaust marks day as used up, DART answered 020
+	Exhaust(ctx context.Context, day string) error
+}
+
+// Transport is the http.RoundTripper shared by every call of a DartClient. It keeps
+// the calls under the rate limit, retries server errors and timeouts with exponential
+// backoff and counts every call against the daily quota of the key.
+type Transport struct {
+	Base       http.RoundTripper
+	Limiter    *rate.Limiter // nil means no limit
+	Quota      QuotaStore    // nil means no quota accounting
+	DailyLimit int
+	MaxRetries int
+	Backoff    time.Duration // delay before the first retry, doubled on every retry
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	day := QuotaDay(time.Now())
+
+	for attempt := 0; ; attempt++ {
+		if t.Quota != nil {
+			if err := t.Quota.Use(ctx, day, t.DailyLimit); err != nil {
+				return nil, err
+			}
+		}
+		if t.Limiter != nil {
+			if err := t.Limiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := t.base().RoundTrip(req)
+		if attempt < t.MaxRetries && isRetryable(resp, err) && (req.Body == nil || req.GetBody != nil) {
+			if resp != nil {
+				resp.Body.Close()
+			}
+			if req.GetBody != nil {
+				if req.Body, err = req.GetBody(); err != nil {
+					return nil, err
+				}
+			}
+
+			delay := t.Backoff << attempt
+			log.Printf("retrying DART call %s in %s (attempt %d): %v", req.URL.Path, delay, attempt+1, retryReason(resp, err))
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(delay):
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if t.Quota != nil {
+			if err := t.checkExhausted(ctx, day, resp); err != nil {
+				resp.Body.Close()
+				return nil, err
+			}
+		}
+		return resp, nil
+	}
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+// errorSniffSize is how much of a response checkExhausted reads, DART error responses are a few dozen bytes
+const errorSniffSize = 1024
+
+// checkExhausted reads the status of an error response and exhausts the day when it is 020.
+// Documents and corpCode.xml come zipped and are never error responses. Only the head of a response
+// is read, the rest is left to stream, so an archive sent under another content type is not buffered.
+func (t *Transport) checkExhausted(ctx context.Context, day string, resp *http.Response) error {
+	contentType := resp.Header.Get("Content-Type")
+	if strings.Contains(contentType, "zip") || strings.Contains(contentType, "octet-stream") {
+		return nil
+	}
+
+	head, err := io.ReadAll(io.LimitReader(resp.Body, errorSniffSize))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(head), resp.Body), resp.Body}
+	if err != nil {
+		return err
+	}
+
+	// a zip archive starts with its local file header
+	if bytes.HasPrefix(head, []byte("PK")) {
+		return nil
+	}
+
+	if e := parseErrorResponse(head); e != nil && e.Status == StatusRequestLimitExceeded {
+		log.Printf("DART request limit exceeded on %s", day)
+		return t.Quota.Exhaust(ctx, day)
+	}
+	return nil
+}
+
+// QuotaDay returns the day of t the DART quota is counted on
+func QuotaDay(t time.Time) string {
+	return t.In(kst).Format("2006-01-02")
+}
+
+// NextQuotaReset returns when the DART quota resets after t, at midnight in KST
+func NextQuotaReset(t time.Time) time.Time {
+	d := t.In(kst)
+	return time.Date(d.Year(), d.Month(), d.Day()+1, 0, 0, 0, 0, kst)
+}
+
+func isRetryable(resp *http.Response, err error) bool {
+	if err != nil {
+		var netErr net.Error
+		return errors.As(err, &netErr) && netErr.Timeout() || errors.Is(err, io.ErrUnexpectedEOF)
+	}
+	return resp.StatusCode >= http.StatusInternalServerError
+}
+
+func retryReason(resp *http.Response, err error) any {
+	if err != nil {
+		return err
+	}
+	return resp.Status
+}
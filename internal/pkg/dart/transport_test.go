@@ -0,0 +1,142 @@
+package dart_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"kosis/internal/pkg/dart"
+	"kosis/internal/testhelpers"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// memoryQuota is a QuotaStore kept in memory
+type memoryQuota struct {
+	mu        sync.Mutex
+	calls     map[string]int
+	exhausted map[string]bool
+}
+
+func (q *memoryQuota) Use(_ context.Context, day string, limit int) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.exhausted[day] || q.calls[day] >= limit {
+		return dart.ErrQuotaExceeded
+	}
+	q.calls[day]++
+	return nil
+}
+
+func (q *memoryQuota) Exhaust(_ context.Context, day string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.exhausted[day] = true
+	return nil
+}
+
+var _ = Describe("Transport", func() {
+	var quota *memoryQuota
+	var client *http.Client
+
+	BeforeEach(func() {
+		quota = &memoryQuota{calls: map[string]int{}, exhausted: map[string]bool{}}
+		client = &http.Client{
+			Transport: &dart.Transport{
+				Base:       testhelpers.DefaultTransport,
+				Quota:      quota,
+				DailyLimit: 10,
+				MaxRetries: 2,
+				Backoff:    time.Millisecond,
+			},
+		}
+	})
+
+	AfterEach(func() {
+		testhelpers.DefaultTransport.Reset()
+	})
+
+	It("retries server errors with backoff", func() {
+		testhelpers.New("https://opendart.fss.or.kr").Get("/api/list.json").Reply(503).BodyString("busy")
+		testhelpers.New("https://opendart.fss.or.kr").Get("/api/list.json").Reply(200).BodyString(`{"status":"000"}`)
+
+		resp, err := client.Get("https://opendart.fss.or.kr/api/list.json")
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		body, err := io.ReadAll(resp.Body)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(body)).To(Equal(`{"status":"000"}`))
+		Expect(testhelpers.IsDone()).To(BeTrue())
+		Expect(quota.calls[dart.QuotaDay(time.Now())]).To(Equal(2))
+	})
+
+	It("returns the last server error once the retries are used up", func() {
+		for range 3 {
+			testhelpers.New("https://opendart.fss.or.kr").Get("/api/list.json").Reply(500).BodyString("down")
+		}
+
+		resp, err := client.Get("https://opendart.fss.or.kr/api/list.json")
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+
+		Expect(resp.StatusCode).To(Equal(http.StatusInternalServerError))
+		Expect(testhelpers.IsDone()).To(BeTrue())
+	})
+
+	It("does not call DART once the daily quota is used up", func() {
+		quota.calls[dart.QuotaDay(time.Now())] = 10
+
+		_, err := client.Get("https://opendart.fss.or.kr/api/list.json")
+		Expect(err).To(MatchError(dart.ErrQuotaExceeded))
+	})
+
+	It("exhausts the day when DART reports the request limit", func() {
+		testhelpers.New("https://opendart.fss.or.kr").Get("/api/list.json").Reply(200).BodyString(`{"status":"020","message":"요청 제한을 초과하였습니다."}`)
+
+		resp, err := client.Get("https://opendart.fss.or.kr/api/list.json")
+		Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+		Expect(quota.exhausted[dart.QuotaDay(time.Now())]).To(BeTrue())
+
+		_, err = client.Get("https://opendart.fss.or.kr/api/list.json")
+		Expect(err).To(MatchError(dart.ErrQuotaExceeded))
+	})
+
+	It("leaves an archive sent under another content type to stream", func() {
+		archive := "PK\x03\x04" + strings.Repeat("x", 4096)
+		testhelpers.New("https://opendart.fss.or.kr").Get("/api/document.xml").Reply(200).BodyString(archive).Header("Content-Type", "application/x-msdownload")
+
+		resp, err := client.Get("https://opendart.fss.or.kr/api/document.xml")
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(body)).To(Equal(archive))
+		Expect(quota.exhausted[dart.QuotaDay(time.Now())]).To(BeFalse())
+	})
+
+	It("returns a long response whole after reading its head", func() {
+		list := `{"status":"000","list":[` + strings.Repeat(`{"rcept_no":"20250101000001"},`, 100) + `{}]}`
+		testhelpers.New("https://opendart.fss.or.kr").Get("/api/list.json").Reply(200).BodyString(list).Header("Content-Type", "application/json")
+
+		resp, err := client.Get("https://opendart.fss.or.kr/api/list.json")
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(body)).To(Equal(list))
+	})
+
+	It("counts the quota in KST", func() {
+		Expect(dart.QuotaDay(time.Date(2025, 6, 30, 16, 0, 0, 0, time.UTC))).To(Equal("2025-07-01"))
+		Expect(dart.NextQuotaReset(time.Date(2025, 6, 30, 14, 0, 0, 0, time.UTC)).UTC()).To(Equal(time.Date(2025, 6, 30, 15, 0, 0, 0, time.UTC)))
+	})
+})
@@ -0,0 +1,345 @@
+package xbrl
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Statements an account belongs to
+const (
+	StatementBalanceSheet    = "balance_sheet"
+	StatementIncomeStatement = "income_statement"
+)
+
+// Canonical accounts, named like the keys of the analysis
+const (
+	AccountTotalAssets             = "total_assets"
+	AccountCurrentAssets           = "current_assets"
+	AccountNonCurrentAssets        = "non_current_assets"
+	AccountTotalLiabilities        = "total_liabilities"
+	AccountCurrentLiabilities      = "current_liabilities"
+	AccountNonCurrentLiabilities   = "non_current_liabilities"
+	AccountTotalEquity             = "total_equity"
+	AccountEquityOwners            = "equity_attributable_to_owners"
+	AccountNonControllingInterests = "non_controlling_interests"
+	AccountCapital                 = "capital"
+	AccountRetainedEarnings        = "retained_earnings"
+	AccountSales                   = "sales"
+	AccountOperatingIncome         = "operating_income"
+	AccountIncomeBeforeTax         = "income_before_tax"
+	AccountNetIncome               = "net_income"
+	AccountOwnersNetIncome         = "owners_net_income"
+)
+
+// accountTaxonomy lists the labels DART filings write each canonical account with, as normalizeLabel leaves them,
+// and the concepts of the XBRL instances that report it
+var accountTaxonomy = []struct {
+	account   string
+	statement string
+	labels    []string
+	concepts  []string
+}{
+	{AccountTotalAssets, StatementBalanceSheet, []string{"자산총계", "총자산", "자산합계"}, []string{"ifrs-full:Assets"}},
+	{AccountCurrentAssets, StatementBalanceSheet, []string{"유동자산"}, []string{"ifrs-full:CurrentAssets"}},
+	{AccountNonCurrentAssets, StatementBalanceSheet, []string{"비유동자산"}, []string{"ifrs-full:NoncurrentAssets"}},
+	{AccountTotalLiabilities, StatementBalanceSheet, []string{"부채총계", "총부채", "부채합계"}, []string{"ifrs-full:Liabilities"}},
+	{AccountCurrentLiabilities, StatementBalanceSheet, []string{"유동부채"}, []string{"ifrs-full:CurrentLiabilities"}},
+	{AccountNonCurrentLiabilities, StatementBalanceSheet, []string{"비유동부채"}, []string{"ifrs-full:NoncurrentLiabilities"}},
+	{AccountTotalEquity, StatementBalanceSheet, []string{"자본총계", "총자본", "자본합계"}, []string{"ifrs-full:Equity"}},
+	{AccountEquityOwners, StatementBalanceSheet, []string{"지배기업소유주지분자본", "지배기업의소유주에게귀속되는자본"}, []string{"ifrs-full:EquityAttributableToOwnersOfParent"}},
+	{AccountCapital, StatementBalanceSheet, []string{"자본금"}, []string{"ifrs-full:IssuedCapital"}},
+	{AccountRetainedEarnings, StatementBalanceSheet, []string{"이익잉여금"}, []string{"ifrs-full:RetainedEarnings"}},
+	{AccountSales, StatementIncomeStatement, []string{"매출액", "수익(매출액)", "매출", "영업수익", "매출및지분법손익"}, []string{"ifrs-full:Revenue"}},
+	{AccountOperatingIncome, StatementIncomeStatement, []string{"영업이익", "영업손익"}, []string{"dart:OperatingIncomeLoss"}},
+	{AccountIncomeBeforeTax, StatementIncomeStatement, []string{"법인세차감전순이익", "법인세비용차감전순이익", "법인세비용차감전계속영업이익", "법인세비용차감전순손익"}, []string{"ifrs-full:ProfitLossBeforeTax"}},
+	{AccountNetIncome, StatementIncomeStatement, []string{"당기순이익", "연결당기순이익", "분기순이익", "반기순이익", "당기순손익", "분기순손익", "반기순손익"}, []string{"ifrs-full:ProfitLoss"}},
+	{AccountOwnersNetIncome, StatementIncomeStatement, []string{"지배기업소유주지분순이익", "지배기업의소유주에게귀속되는당기순이익", "지배기업소유주지분당기순이익"}, []string{"ifrs-full:ProfitLossAttributableToOwnersOfParent"}},
+}
+
+// the rows splitting net income or equity between the owners of the parent and the other shareholders.
+// What they split is the account above them.
+var (
+	ownersLabels         = []string{"지배기업소유주지분", "지배기업의소유주지분", "지배기업의소유주", "지배기업소유주"}
+	nonControllingLabels = []string{"비지배지분"}
+)
+
+var (
+	accountLabels   = map[string]int{} // normalized label to index in accountTaxonomy
+	accountConcepts = map[string]int{} // concept to index in accountTaxonomy
+)
+
+func init() {
+	for i, a := range accountTaxonomy {
+		for _, label := range a.labels {
+			accountLabels[normalizeLabel(label)] = i
+		}
+		for _, concept := range a.concepts {
+			accountConcepts[concept] = i
+		}
+	}
+}
+
+var (
+	// numbering of a row label, e.g. Ⅰ. 유동자산, 1. 현금, (1) 매출, 가. 상품, ① 제품
+	reLabelNumbering = regexp.MustCompile(`^(?:[ⅠⅡⅢⅣⅤⅥⅦⅧⅨⅩ]+\.?|\d+\.|[가-하]\.|\(\d+\)|\([가-하]\)|[①-⑳]|[·ㆍ\-]+)`)
+	// remarks of a row label, e.g. 당기순이익(손실), 이익잉여금(결손금), 자산총계(주3)
+	reLabelRemark = regexp.MustCompile(`\((?:손실|순손실|결손금|손익|이익|주\d*(?:,\d+)*)\)|\*\d*$`)
+
+	// a date of a column or caption, e.g. 2025.09.30, 2025-09-30 or 2025년 9월 30일
+	rePeriodDate = regexp.MustCompile(`(\d{4})\s*[.\-/년]\s*(\d{1,2})\s*[.\-/월]\s*(\d{1,2})`)
+	// the ordinal of a fiscal year, e.g. 제 56 기
+	reFiscalOrdinal = regexp.MustCompile(`제\s*(\d+)\s*기`)
+)
+
+// AccountValue is the amount of a canonical account in a period column of a financial statement table
+type AccountValue struct {
+	Account     string  `json:"account"`                // e.g. total_assets
+	Statement   string  `json:"statement"`              // StatementBalanceSheet or StatementIncomeStatement
+	Label       string  `json:"label"`                  // as written, e.g. 자산 총계
+	Scope       string  `json:"scope"`                  // ScopeConsolidated or ScopeSeparate
+	Column      string  `json:"column"`                 // e.g. 제 56 기 3분기 누적
+	PeriodEnd   string  `json:"period_end"`             // YYYY-MM-DD
+	ThreeMonths bool    `json:"three_months,omitempty"` // the quarter alone in an interim income statement, other columns are cumulative
+	Summary     bool    `json:"summary,omitempty"`      // from 요약재무정보, rounded to its unit
+	Value       float64 `json:"value"`                  // in KRW
+}
+
+// CanonicalAccount returns the canonical account and statement of a row label or an XBRL concept,
+// e.g. total_assets for "Ⅲ. 자산 총계" or ifrs-full:Assets
+func CanonicalAccount(label string) (account, statement string, ok bool) {
+	i, ok := accountConcepts[label]
+	if !ok {
+		i, ok = accountLabels[normalizeLabel(label)]
+	}
+	if !ok {
+		return "", "", false
+	}
+	return accountTaxonomy[i].account, accountTaxonomy[i].statement, true
+}
+
+// normalizeLabel drops the spaces, numbering, brackets and remarks of a row label, e.g. [유동자산]
+func normalizeLabel(label string) string {
+	label = strings.Join(strings.Fields(label), "")
+	label = reLabelNumbering.ReplaceAllString(label, "")
+	if strings.HasPrefix(label, "[") && strings.HasSuffix(label, "]") {
+		label = label[1 : len(label)-1]
+	}
+	return reLabelRemark.ReplaceAllString(label, "")
+}
+
+// RecognizeAccounts reads the canonical accounts of the 요약재무정보, 연결재무제표 and 재무제표 sections
+// of a periodic report, one value per account and period column. Notes (주석) are left out, as are
+// tables whose columns are not periods, e.g. 자본변동표.
+func (r *UsefulReport) RecognizeAccounts() []AccountValue {
+	var out []AccountValue
+	for i := range r.Sections {
+		out = append(out, recognizeSection(&r.Sections[i], "", false, false)...)
+	}
+	return out
+}
+
+// recognizeSection reads the statements of a section. in tells whether a parent section is about
+// financial statements, scope and summary are what its title said.
+func recognizeSection(s *Section, scope string, summary, in bool) []AccountValue {
+	title := strings.Join(strings.Fields(s.Title), "")
+	if strings.Contains(title, "주석") {
+		return nil
+	}
+	switch {
+	case strings.Contains(title, "재무정보"):
+		in, summary = true, true
+		scope = scopeOf(title, scope)
+	case strings.Contains(title, "재무제표"):
+		in, summary = true, false
+		scope = ScopeSeparate
+		if strings.Contains(title, "연결") {
+			scope = ScopeConsolidated
+		}
+	}
+
+	var out []AccountValue
+	if in {
+		var context []string // what was written since the last statement table, e.g. its title and periods
+		blockScope := scope
+		for _, b := range s.Blocks {
+			if b.Paragraph != "" {
+				context = append(context, b.Paragraph)
+				if summary {
+					blockScope = scopeOf(strings.Join(strings.Fields(b.Paragraph), ""), blockScope)
+				}
+				continue
+			}
+
+			values := recognizeTable(b.Table, context, blockScope, summary)
+			if len(values) == 0 {
+				// a table of periods, or a title written as a table
+				context = append(context, tableText(b.Table))
+				continue
+			}
+			out = append(out, values...)
+			context = nil
+		}
+	}
+
+	for i := range s.Sections {
+		out = append(out, recognizeSection(&s.Sections[i], scope, summary, in)...)
+	}
+	return out
+}
+
+// scopeOf reads the scope of a title such as 요약연결재무정보 or 요약별도재무정보, keeping scope otherwise
+func scopeOf(title, scope string) string {
+	switch {
+	case strings.Contains(title, "별도"):
+		return ScopeSeparate
+	case strings.Contains(title, "연결"):
+		return ScopeConsolidated
+	}
+	return scope
+}
+
+// recognizeTable reads the accounts of a statement table, context being the text written before it
+func recognizeTable(t *Table, context []string, scope string, summary bool) []AccountValue {
+	if t == nil || scope == "" || len(t.Rows) == 0 {
+		return nil
+	}
+	text := strings.Join(context, "\n")
+	if strings.Contains(strings.Join(strings.Fields(text), ""), "자본변동표") {
+		return nil
+	}
+
+	ends := ordinalPeriodEnds(text + "\n" + tableText(&Table{Rows: t.Header()}))
+	columns := t.ColumnNames()
+	periods := make([]string, len(columns))
+	for col, name := range columns {
+		if col > 0 {
+			periods[col] = columnPeriodEnd(name, ends)
+		}
+	}
+
+	var out []AccountValue
+	var parent, parentStatement string
+	for row, cells := range t.Body() {
+		label := cells[0].Text
+		account, statement, ok := CanonicalAccount(label)
+		switch {
+		case ok:
+			parent, parentStatement = account, statement
+		default:
+			account, statement, ok = splitAccount(label, parent, parentStatement)
+			// the split of 총포괄손익 follows net income, it is not the split of net income
+			if !ok && parentStatement == StatementIncomeStatement && rowHasNumber(cells) {
+				parent, parentStatement = "", ""
+			}
+		}
+		if !ok {
+			continue
+		}
+
+		for col, end := range periods {
+			if end == "" {
+				continue
+			}
+			value, ok := t.Value(row, col)
+			if !ok {
+				continue
+			}
+			out = append(out, AccountValue{
+				Account:     account,
+				Statement:   statement,
+				Label:       label,
+				Scope:       scope,
+				Column:      columns[col],
+				PeriodEnd:   end,
+				ThreeMonths: strings.Contains(strings.Join(strings.Fields(columns[col]), ""), "3개월"),
+				Summary:     summary,
+				Value:       value,
+			})
+		}
+	}
+	return out
+}
+
+// splitAccount reads a row splitting an account between the owners of the parent and the other
+// shareholders: net income when it follows 당기순이익, equity anywhere in a balance sheet, as
+// 지배기업 소유주지분 comes before 자본총계 in statements and after it in 요약재무정보
+func splitAccount(label, parent, parentStatement string) (string, string, bool) {
+	normalized := normalizeLabel(label)
+	owners := matchesLabel(normalized, ownersLabels)
+	nonControlling := matchesLabel(normalized, nonControllingLabels)
+
+	switch {
+	case parent == AccountNetIncome && owners:
+		return AccountOwnersNetIncome, StatementIncomeStatement, true
+	case parentStatement == StatementBalanceSheet && owners:
+		return AccountEquityOwners, StatementBalanceSheet, true
+	case parentStatement == StatementBalanceSheet && nonControlling:
+		return AccountNonControllingInterests, StatementBalanceSheet, true
+	}
+	return "", "", false
+}
+
+func matchesLabel(label string, labels []string) bool {
+	for _, l := range labels {
+		if label == l {
+			return true
+		}
+	}
+	return false
+}
+
+// columnPeriodEnd returns the end of the period of a column, from a date in its name or from the
+// fiscal year ordinal in its name, e.g. 제 56 기 3분기말
+func columnPeriodEnd(name string, ends map[string]string) string {
+	if dates := rePeriodDate.FindAllStringSubmatch(name, -1); len(dates) > 0 {
+		return isoDate(dates[len(dates)-1])
+	}
+	if m := reFiscalOrdinal.FindStringSubmatch(name); m != nil {
+		return ends[m[1]]
+	}
+	return ""
+}
+
+// ordinalPeriodEnds reads the period ends DART writes above statements, e.g. "제 56 기 3분기말 2025.09.30 현재"
+// or "제 56 기 3분기 2025.01.01 부터 2025.09.30 까지": the last date after an ordinal is the end of its period
+func ordinalPeriodEnds(text string) map[string]string {
+	ends := map[string]string{}
+	ordinals := reFiscalOrdinal.FindAllStringSubmatchIndex(text, -1)
+	for i, m := range ordinals {
+		next := len(text)
+		if i+1 < len(ordinals) {
+			next = ordinals[i+1][0]
+		}
+		ordinal := text[m[2]:m[3]]
+		if _, ok := ends[ordinal]; ok {
+			continue
+		}
+		if dates := rePeriodDate.FindAllStringSubmatch(text[m[1]:next], -1); len(dates) > 0 {
+			ends[ordinal] = isoDate(dates[len(dates)-1])
+		}
+	}
+	return ends
+}
+
+func isoDate(m []string) string {
+	month, _ := strconv.Atoi(m[2])
+	day, _ := strconv.Atoi(m[3])
+	return fmt.Sprintf("%s-%02d-%02d", m[1], month, day)
+}
+
+// tableText returns the text of the cells of a table, a row per line
+func tableText(t *Table) string {
+	var lines []string
+	for _, row := range t.Rows {
+		var cells []string
+		for _, cell := range row {
+			if !cell.Spanned && cell.Text != "" {
+				cells = append(cells, cell.Text)
+			}
+		}
+		lines = append(lines, strings.Join(cells, " "))
+	}
+	return strings.Join(lines, "\n")
+}
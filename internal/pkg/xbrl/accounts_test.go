@@ -0,0 +1,201 @@
+package xbrl_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"kosis/internal/pkg/xbrl"
+)
+
+var _ = Describe("RecognizeAccounts", func() {
+	const quarterlyReport = `
+<DOCUMENT>
+  <DOCUMENT-NAME ACODE="11013">분기보고서</DOCUMENT-NAME>
+  <BODY>
+    <SECTION-1>
+      <TITLE ATOC="Y">III. 재무에 관한 사항</TITLE>
+      <SECTION-2>
+        <TITLE ATOC="Y">1. 요약재무정보</TITLE>
+        <P>가. 요약연결재무정보</P>
+        <P>(단위 : 백만원)</P>
+        <TABLE>
+          <THEAD>
+            <TR><TH>구 분</TH><TH>제56기 3분기</TH><TH>제55기</TH></TR>
+            <TR><TH></TH><TH>(2025.09.30)</TH><TH>(2024.12.31)</TH></TR>
+          </THEAD>
+          <TBODY>
+            <TR><TD>[유동자산]</TD><TE>210,000</TE><TE>190,000</TE></TR>
+            <TR><TD>자산총계</TD><TE>530,000</TE><TE>514,532</TE></TR>
+            <TR><TD>부채총계</TD><TE>110,000</TE><TE>112,340</TE></TR>
+            <TR><TD>자본총계</TD><TE>420,000</TE><TE>402,192</TE></TR>
+            <TR><TD>· 지배기업 소유주지분</TD><TE>410,000</TE><TE>391,687</TE></TR>
+            <TR><TD>· 비지배지분</TD><TE>10,000</TE><TE>10,505</TE></TR>
+          </TBODY>
+        </TABLE>
+        <P>나. 요약별도재무정보</P>
+        <P>(단위 : 백만원)</P>
+        <TABLE>
+          <THEAD><TR><TH>구 분</TH><TH>제56기 3분기 (2025.09.30)</TH></TR></THEAD>
+          <TBODY><TR><TD>자산총계</TD><TE>300,000</TE></TR></TBODY>
+        </TABLE>
+      </SECTION-2>
+      <SECTION-2>
+        <TITLE ATOC="Y">2. 연결재무제표</TITLE>
+        <P>연결 재무상태표</P>
+        <TABLE><TR><TD>제 56 기 3분기말 2025.09.30 현재</TD></TR><TR><TD>제 55 기말 2024.12.31 현재</TD></TR></TABLE>
+        <TABLE>
+          <TR><TD>(단위 : 원)</TD></TR>
+        </TABLE>
+        <TABLE>
+          <THEAD><TR><TH></TH><TH>주석</TH><TH>제 56 기 3분기말</TH><TH>제 55 기말</TH></TR></THEAD>
+          <TBODY>
+            <TR><TD>Ⅰ. 유동자산</TD><TD>4</TD><TE>200,000,000,000</TE><TE>190,000,000,000</TE></TR>
+            <TR><TD>자산총계</TD><TD></TD><TE>530,000,000,000</TE><TE>514,532,000,000</TE></TR>
+            <TR><TD>부채총계</TD><TD></TD><TE>110,000,000,000</TE><TE>112,340,000,000</TE></TR>
+            <TR><TD>자본</TD><TD></TD><TD></TD><TD></TD></TR>
+            <TR><TD>지배기업 소유주지분</TD><TD></TD><TE>410,000,000,000</TE><TE>391,687,000,000</TE></TR>
+            <TR><TD>자본금</TD><TD>20</TD><TE>897,514,000</TE><TE>897,514,000</TE></TR>
+            <TR><TD>비지배지분</TD><TD></TD><TE>10,000,000,000</TE><TE>10,505,000,000</TE></TR>
+            <TR><TD>자본총계</TD><TD></TD><TE>420,000,000,000</TE><TE>402,192,000,000</TE></TR>
+          </TBODY>
+        </TABLE>
+        <P>연결 손익계산서</P>
+        <TABLE><TR><TD>제 56 기 3분기 2025.01.01 부터 2025.09.30 까지</TD></TR><TR><TD>제 55 기 3분기 2024.01.01 부터 2024.09.30 까지</TD></TR></TABLE>
+        <TABLE>
+          <THEAD>
+            <TR><TH ROWSPAN="2"></TH><TH COLSPAN="2">제 56 기 3분기</TH><TH COLSPAN="2">제 55 기 3분기</TH></TR>
+            <TR><TH>3개월</TH><TH>누적</TH><TH>3개월</TH><TH>누적</TH></TR>
+          </THEAD>
+          <TBODY>
+            <TR><TD>수익(매출액)</TD><TE>86,061,747,000</TE><TE>240,000,000,000</TE><TE>79,098,716,000</TE><TE>225,000,000,000</TE></TR>
+            <TR><TD>영업이익(손실)</TD><TE>(1,000,000)</TE><TE>30,000,000,000</TE><TE>9,183,413,000</TE><TE>26,000,000,000</TE></TR>
+            <TR><TD>분기순이익(손실)</TD><TE>12,225,656,000</TE><TE>25,000,000,000</TE><TE>10,100,000,000</TE><TE>24,000,000,000</TE></TR>
+            <TR><TD>분기순이익(손실)의 귀속</TD><TD></TD><TD></TD><TD></TD><TD></TD></TR>
+            <TR><TD>지배기업 소유주지분</TD><TE>12,000,000,000</TE><TE>24,500,000,000</TE><TE>9,900,000,000</TE><TE>23,500,000,000</TE></TR>
+            <TR><TD>비지배지분</TD><TE>225,656,000</TE><TE>500,000,000</TE><TE>200,000,000</TE><TE>500,000,000</TE></TR>
+            <TR><TD>총포괄손익</TD><TE>13,000,000,000</TE><TE>27,000,000,000</TE><TE>11,000,000,000</TE><TE>25,000,000,000</TE></TR>
+            <TR><TD>지배기업 소유주지분</TD><TE>1</TE><TE>2</TE><TE>3</TE><TE>4</TE></TR>
+          </TBODY>
+        </TABLE>
+        <P>연결 자본변동표</P>
+        <TABLE>
+          <THEAD><TR><TH></TH><TH>자본금</TH><TH>자본총계</TH></TR></THEAD>
+          <TBODY><TR><TD>분기순이익</TD><TE>0</TE><TE>25,000,000,000</TE></TR></TBODY>
+        </TABLE>
+      </SECTION-2>
+      <SECTION-2>
+        <TITLE ATOC="Y">3. 연결재무제표 주석</TITLE>
+        <TABLE>
+          <THEAD><TR><TH>구분</TH><TH>2025.09.30</TH></TR></THEAD>
+          <TBODY><TR><TD>유동자산</TD><TE>1</TE></TR></TBODY>
+        </TABLE>
+      </SECTION-2>
+      <SECTION-2>
+        <TITLE ATOC="Y">4. 재무제표</TITLE>
+        <P>재무상태표</P>
+        <TABLE><TR><TD>제 56 기 3분기말 2025.09.30 현재</TD></TR></TABLE>
+        <TABLE>
+          <THEAD><TR><TH></TH><TH>제 56 기 3분기말</TH></TR></THEAD>
+          <TBODY><TR><TD>자산총계</TD><TE>300,000,000,000</TE></TR></TBODY>
+        </TABLE>
+      </SECTION-2>
+    </SECTION-1>
+  </BODY>
+</DOCUMENT>
+`
+
+	var accounts []xbrl.AccountValue
+
+	BeforeEach(func() {
+		report := mustParseReport(quarterlyReport)
+		accounts = report.RecognizeAccounts()
+	})
+
+	find := func(account, scope, end string, summary, threeMonths bool) []xbrl.AccountValue {
+		var out []xbrl.AccountValue
+		for _, a := range accounts {
+			if a.Account == account && a.Scope == scope && a.PeriodEnd == end && a.Summary == summary && a.ThreeMonths == threeMonths {
+				out = append(out, a)
+			}
+		}
+		return out
+	}
+
+	It("reads 요약재무정보 with the scope of its headings and the unit of its caption", func() {
+		assets := find(xbrl.AccountTotalAssets, xbrl.ScopeConsolidated, "2025-09-30", true, false)
+		Expect(assets).To(HaveLen(1))
+		Expect(assets[0].Value).To(Equal(530000e6))
+		Expect(assets[0].Statement).To(Equal(xbrl.StatementBalanceSheet))
+		Expect(assets[0].Column).To(Equal("제56기 3분기 (2025.09.30)"))
+
+		Expect(find(xbrl.AccountCurrentAssets, xbrl.ScopeConsolidated, "2025-09-30", true, false)[0].Value).To(Equal(210000e6))
+
+		owners := find(xbrl.AccountEquityOwners, xbrl.ScopeConsolidated, "2024-12-31", true, false)
+		Expect(owners).To(HaveLen(1))
+		Expect(owners[0].Value).To(Equal(391687e6))
+		Expect(find(xbrl.AccountNonControllingInterests, xbrl.ScopeConsolidated, "2024-12-31", true, false)[0].Value).To(Equal(10505e6))
+
+		separate := find(xbrl.AccountTotalAssets, xbrl.ScopeSeparate, "2025-09-30", true, false)
+		Expect(separate).To(HaveLen(1))
+		Expect(separate[0].Value).To(Equal(300000e6))
+	})
+
+	It("reads statements with the period ends written above them", func() {
+		current := find(xbrl.AccountCurrentAssets, xbrl.ScopeConsolidated, "2025-09-30", false, false)
+		Expect(current).To(HaveLen(1))
+		Expect(current[0].Label).To(Equal("Ⅰ. 유동자산"))
+		Expect(current[0].Value).To(Equal(200e9))
+
+		Expect(find(xbrl.AccountCapital, xbrl.ScopeConsolidated, "2024-12-31", false, false)[0].Value).To(Equal(897514000.0))
+		Expect(find(xbrl.AccountEquityOwners, xbrl.ScopeConsolidated, "2025-09-30", false, false)[0].Value).To(Equal(410e9))
+		Expect(find(xbrl.AccountNonControllingInterests, xbrl.ScopeConsolidated, "2025-09-30", false, false)[0].Value).To(Equal(10e9))
+
+		Expect(find(xbrl.AccountTotalAssets, xbrl.ScopeSeparate, "2025-09-30", false, false)[0].Value).To(Equal(300e9))
+	})
+
+	It("tells cumulative and quarter columns of income statements apart", func() {
+		sales := find(xbrl.AccountSales, xbrl.ScopeConsolidated, "2025-09-30", false, false)
+		Expect(sales).To(HaveLen(1))
+		Expect(sales[0].Value).To(Equal(240e9))
+		Expect(sales[0].Statement).To(Equal(xbrl.StatementIncomeStatement))
+		Expect(sales[0].Column).To(Equal("제 56 기 3분기 누적"))
+
+		quarter := find(xbrl.AccountOperatingIncome, xbrl.ScopeConsolidated, "2025-09-30", false, true)
+		Expect(quarter).To(HaveLen(1))
+		Expect(quarter[0].Value).To(Equal(-1e6))
+
+		Expect(find(xbrl.AccountNetIncome, xbrl.ScopeConsolidated, "2024-09-30", false, false)[0].Value).To(Equal(24e9))
+	})
+
+	It("splits net income but not comprehensive income between owners", func() {
+		owners := find(xbrl.AccountOwnersNetIncome, xbrl.ScopeConsolidated, "2025-09-30", false, false)
+		Expect(owners).To(HaveLen(1))
+		Expect(owners[0].Value).To(Equal(24.5e9))
+	})
+
+	It("leaves out notes and statements of changes in equity", func() {
+		for _, a := range accounts {
+			Expect(a.Column).NotTo(Equal("자본총계"))
+			Expect(a.Column).NotTo(Equal("2025.09.30"))
+		}
+	})
+
+	DescribeTable("CanonicalAccount",
+		func(label, account, statement string) {
+			a, s, ok := xbrl.CanonicalAccount(label)
+			Expect(ok).To(Equal(account != ""))
+			Expect(a).To(Equal(account))
+			Expect(s).To(Equal(statement))
+		},
+		Entry("numbered", "Ⅲ. 자산 총계", xbrl.AccountTotalAssets, xbrl.StatementBalanceSheet),
+		Entry("loss remark", "당기순이익(손실)", xbrl.AccountNetIncome, xbrl.StatementIncomeStatement),
+		Entry("deficit remark", "이익잉여금(결손금)", xbrl.AccountRetainedEarnings, xbrl.StatementBalanceSheet),
+		Entry("footnote", "매출액(주3)", xbrl.AccountSales, xbrl.StatementIncomeStatement),
+		Entry("synonym", "법인세비용차감전순이익", xbrl.AccountIncomeBeforeTax, xbrl.StatementIncomeStatement),
+		Entry("summary bracket", "[부채총계]", xbrl.AccountTotalLiabilities, xbrl.StatementBalanceSheet),
+		Entry("concept", "ifrs-full:Revenue", xbrl.AccountSales, xbrl.StatementIncomeStatement),
+		Entry("dart concept", "dart:OperatingIncomeLoss", xbrl.AccountOperatingIncome, xbrl.StatementIncomeStatement),
+		Entry("unknown", "현금및현금성자산", "", ""),
+		Entry("unknown concept", "ifrs-full:CashAndCashEquivalents", "", ""),
+	)
+})
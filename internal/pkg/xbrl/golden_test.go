@@ -0,0 +1,66 @@
+package xbrl_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"kosis/internal/pkg/xbrl"
+	"kosis/internal/testhelpers"
+)
+
+// goldenReport is what the golden files record of a document of the corpus
+type goldenReport struct {
+	Strategy string              `json:"strategy"`
+	Report   *xbrl.UsefulReport  `json:"report"`
+	Accounts []xbrl.AccountValue `json:"accounts,omitempty"`
+}
+
+// The corpus is the anonymized DART documents of testdata, periodic and audit reports, a correction and an
+// XBRL instance, and the disclosures of the dart package testdata. Their parses are kept in testdata/golden,
+// run the specs with -update after a parser change and review the diff of the golden files.
+var _ = Describe("Golden corpus", func() {
+	type document struct{ file, golden string }
+	var corpus []document
+	for _, dir := range []struct{ glob, golden string }{
+		{"testdata/*.*", "testdata/golden"},
+		{"../dart/testdata/*.html", "testdata/golden/dart"},
+	} {
+		files, err := filepath.Glob(dir.glob)
+		if err != nil {
+			panic(err)
+		}
+		for _, file := range files {
+			name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+			corpus = append(corpus, document{file, filepath.Join(dir.golden, name+".json")})
+		}
+	}
+
+	parsers, err := xbrl.NewParsers("")
+	if err != nil {
+		panic(err)
+	}
+
+	for _, doc := range corpus {
+		file, golden := doc.file, doc.golden
+		It("parses "+file+" as "+golden+" says", func() {
+			raw, err := os.ReadFile(file)
+			Expect(err).NotTo(HaveOccurred())
+
+			strategy := parsers.Strategy("", file, raw)
+			report, err := parsers.Parse("", file, raw)
+			Expect(err).NotTo(HaveOccurred())
+
+			actual, expected, err := testhelpers.Golden(golden, goldenReport{
+				Strategy: strategy,
+				Report:   report,
+				Accounts: report.RecognizeAccounts(),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(actual)).To(Equal(string(expected)), "the parse of %s changed, run the specs with -update and review the diff of %s", file, golden)
+		})
+	}
+})
@@ -0,0 +1,268 @@
+package xbrl
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Scope of a fact, from the ConsolidatedAndSeparateFinancialStatementsAxis of its context
+const (
+	ScopeConsolidated = "consolidated"
+	ScopeSeparate     = "separate"
+)
+
+// consolidationAxis is the dimension DART tells consolidated and separate statements apart with.
+// A context without it is consolidated, ConsolidatedMember being the default of the axis.
+const consolidationAxis = "ConsolidatedAndSeparateFinancialStatementsAxis"
+
+// instanceSniffSize is how much of a file IsInstance looks at
+const instanceSniffSize = 64 * 1024
+
+// an xbrl root element, or the header of an inline XBRL document
+var reInstanceRoot = regexp.MustCompile(`<([\w-]+:)?xbrl[\s>]|<ix:header[\s>]`)
+
+// IsInstance reports whether a file of a filing is an XBRL instance, plain or inline, from its name
+// and the beginning of its contents
+func IsInstance(name string, data []byte) bool {
+	if strings.EqualFold(path.Ext(name), ".xbrl") {
+		return true
+	}
+	return reInstanceRoot.Match(data[:min(len(data), instanceSniffSize)])
+}
+
+// ParseXBRLInstance reads the facts of an XBRL instance, e.g. the ifrs-full and dart facts of the
+// financial statements of a filing. Inline XBRL facts (ix:nonFraction, ix:nonNumeric) are read too.
+// Each fact is resolved against its context and unit: periods, consolidated or separate scope,
+// other dimensions and currency.
+func ParseXBRLInstance(r io.Reader) (*UsefulReport, error) {
+	dec := xml.NewDecoder(r)
+	// inline XBRL is XHTML, which may use HTML entities
+	dec.Strict = false
+	dec.AutoClose = xml.HTMLAutoClose
+	dec.Entity = xml.HTMLEntity
+
+	prefixes := map[string]string{} // namespace to prefix
+	contexts := map[string]Context{}
+	units := map[string]Unit{}
+	var facts []FactValue
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading xbrl token: %w", err)
+		}
+		se, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		for _, a := range se.Attr {
+			switch {
+			case a.Name.Space == "xmlns":
+				prefixes[a.Value] = a.Name.Local
+			case a.Name.Space == "" && a.Name.Local == "xmlns":
+				prefixes[a.Value] = ""
+			}
+		}
+
+		switch {
+		case se.Name.Local == "context":
+			var c Context
+			if err := dec.DecodeElement(&c, &se); err != nil {
+				return nil, fmt.Errorf("unmarshal context: %w", err)
+			}
+			contexts[c.ID] = c
+		case se.Name.Local == "unit":
+			var u Unit
+			if err := dec.DecodeElement(&u, &se); err != nil {
+				return nil, fmt.Errorf("unmarshal unit: %w", err)
+			}
+			units[u.ID] = u
+		case se.Name.Local == "nonFraction" || se.Name.Local == "nonNumeric":
+			f, err := inlineFact(dec, se)
+			if err != nil {
+				return nil, err
+			}
+			facts = append(facts, f)
+		case attrValue(se, "contextRef") != "":
+			f, err := instanceFact(dec, se, prefixes)
+			if err != nil {
+				return nil, err
+			}
+			facts = append(facts, f)
+		}
+	}
+
+	// contexts and units may come after the facts referring to them
+	for i := range facts {
+		resolveFact(&facts[i], contexts, units)
+	}
+
+	return &UsefulReport{Facts: facts}, nil
+}
+
+// instanceFact reads a fact element of an xbrl instance, e.g. <ifrs-full:Revenue contextRef="CFY2024dFY" ...>
+func instanceFact(dec *xml.Decoder, se xml.StartElement, prefixes map[string]string) (FactValue, error) {
+	var raw Fact
+	if err := dec.DecodeElement(&raw, &se); err != nil {
+		return FactValue{}, fmt.Errorf("unmarshal fact %s: %w", se.Name.Local, err)
+	}
+
+	f := FactValue{
+		Concept:    qualifiedName(se.Name, prefixes),
+		Value:      strings.TrimSpace(raw.Value),
+		ContextRef: raw.ContextRef,
+		UnitRef:    raw.UnitRef,
+		Decimals:   raw.Decimals,
+	}
+	if f.UnitRef != "" && !isNil(se) {
+		if n, err := strconv.ParseFloat(f.Value, 64); err == nil {
+			f.Number = &n
+		}
+	}
+	return f, nil
+}
+
+// inlineFact reads an ix:nonFraction or ix:nonNumeric element. The number of a nonFraction is
+// written for display, e.g. "1,234" with scale="6" and sign="-" is -1,234,000,000.
+func inlineFact(dec *xml.Decoder, se xml.StartElement) (FactValue, error) {
+	text, err := elementText(dec)
+	if err != nil {
+		return FactValue{}, fmt.Errorf("reading fact %s: %w", attrValue(se, "name"), err)
+	}
+
+	f := FactValue{
+		Concept:    attrValue(se, "name"),
+		Value:      text,
+		ContextRef: attrValue(se, "contextRef"),
+		UnitRef:    attrValue(se, "unitRef"),
+		Decimals:   attrValue(se, "decimals"),
+	}
+	if se.Name.Local != "nonFraction" || isNil(se) {
+		return f, nil
+	}
+
+	if strings.Contains(attrValue(se, "format"), "comma-decimal") || strings.Contains(attrValue(se, "format"), "numcommadecimal") {
+		// 1.234,5
+		text = strings.NewReplacer(".", "", ",", ".").Replace(text)
+	}
+	n, ok := ParseNumber(text)
+	if !ok {
+		return f, nil
+	}
+	if scale, err := strconv.Atoi(attrValue(se, "scale")); err == nil {
+		n *= math.Pow10(scale)
+	}
+	if attrValue(se, "sign") == "-" {
+		n = -n
+	}
+	f.Number = &n
+	f.Value = strconv.FormatFloat(n, 'f', -1, 64)
+	return f, nil
+}
+
+// resolveFact fills the period, scope, dimensions and unit of a fact from its context and unit
+func resolveFact(f *FactValue, contexts map[string]Context, units map[string]Unit) {
+	if c, ok := contexts[f.ContextRef]; ok {
+		f.Instant = strings.TrimSpace(c.Period.Instant)
+		f.StartDate = strings.TrimSpace(c.Period.StartDate)
+		f.EndDate = strings.TrimSpace(c.Period.EndDate)
+
+		f.Scope = ScopeConsolidated
+		for _, members := range [][]ExplicitMember{c.Entity.Segment.ExplicitMembers, c.Scenario.ExplicitMembers} {
+			for _, m := range members {
+				dimension, member := strings.TrimSpace(m.Dimension), strings.TrimSpace(m.Value)
+				if localName(dimension) == consolidationAxis {
+					if localName(member) == "SeparateMember" {
+						f.Scope = ScopeSeparate
+					}
+					continue
+				}
+				if f.Dimensions == nil {
+					f.Dimensions = map[string]string{}
+				}
+				f.Dimensions[dimension] = member
+			}
+		}
+	}
+
+	if u, ok := units[f.UnitRef]; ok {
+		measure := strings.TrimSpace(u.Measure)
+		if u.Divide != nil {
+			measure = strings.TrimSpace(u.Divide.Numerator)
+			f.Unit = localName(measure) + "/" + localName(strings.TrimSpace(u.Divide.Denominator))
+		} else {
+			f.Unit = localName(measure)
+		}
+		if strings.HasPrefix(measure, "iso4217:") {
+			f.Currency = localName(measure)
+		}
+	}
+}
+
+// elementText returns the text of the element just started, with the text of its children
+func elementText(dec *xml.Decoder) (string, error) {
+	var b strings.Builder
+	for depth := 1; depth > 0; {
+		tok, err := dec.Token()
+		if err != nil {
+			return "", err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			depth--
+		case xml.CharData:
+			b.Write(t)
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " "), nil
+}
+
+func attrValue(se xml.StartElement, local string) string {
+	for _, a := range se.Attr {
+		if a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// isNil reports whether a fact is xsi:nil, it has no value
+func isNil(se xml.StartElement) bool {
+	return attrValue(se, "nil") == "true"
+}
+
+// qualifiedName writes an element name with the prefix of its namespace, e.g. ifrs-full:Revenue
+func qualifiedName(name xml.Name, prefixes map[string]string) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	prefix, ok := prefixes[name.Space]
+	if !ok {
+		// an undeclared prefix is left as is by the decoder
+		prefix = name.Space
+	}
+	if prefix == "" {
+		return name.Local
+	}
+	return prefix + ":" + name.Local
+}
+
+// localName drops the prefix of a QName, e.g. SeparateMember for ifrs-full:SeparateMember
+func localName(qname string) string {
+	if i := strings.LastIndex(qname, ":"); i >= 0 {
+		return qname[i+1:]
+	}
+	return qname
+}
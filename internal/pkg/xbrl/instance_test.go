@@ -0,0 +1,133 @@
+package xbrl_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"kosis/internal/pkg/xbrl"
+)
+
+var _ = Describe("ParseXBRLInstance", func() {
+	const instance = `<?xml version="1.0" encoding="UTF-8"?>
+<xbrli:xbrl xmlns:xbrli="http://www.xbrl.org/2003/instance"
+            xmlns:xbrldi="http://xbrl.org/2006/xbrldi"
+            xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"
+            xmlns:iso4217="http://www.xbrl.org/2003/iso4217"
+            xmlns:ifrs-full="https://xbrl.ifrs.org/taxonomy/2023-03-23/ifrs-full"
+            xmlns:dart="http://dart.fss.or.kr/xbrl/dte/2023-06-30/dart">
+  <ifrs-full:Revenue contextRef="CFY2024dFY" unitRef="KRW" decimals="-6">300870903000000</ifrs-full:Revenue>
+  <ifrs-full:Revenue contextRef="SFY2024dFY" unitRef="KRW" decimals="-6">209052241000000</ifrs-full:Revenue>
+  <ifrs-full:BasicEarningsLossPerShare contextRef="CFY2024dFY" unitRef="KRWPerShare" decimals="0">4950</ifrs-full:BasicEarningsLossPerShare>
+  <ifrs-full:Equity contextRef="CFY2024eFY_Retained" unitRef="KRW" decimals="-6">-1500000</ifrs-full:Equity>
+  <dart:DocumentType contextRef="CFY2024dFY">사업보고서</dart:DocumentType>
+  <ifrs-full:Goodwill contextRef="CFY2024eFY_Retained" unitRef="KRW" xsi:nil="true"/>
+
+  <xbrli:context id="CFY2024dFY">
+    <xbrli:entity><xbrli:identifier scheme="http://dart.fss.or.kr">00126380</xbrli:identifier></xbrli:entity>
+    <xbrli:period><xbrli:startDate>2024-01-01</xbrli:startDate><xbrli:endDate>2024-12-31</xbrli:endDate></xbrli:period>
+  </xbrli:context>
+  <xbrli:context id="SFY2024dFY">
+    <xbrli:entity><xbrli:identifier scheme="http://dart.fss.or.kr">00126380</xbrli:identifier></xbrli:entity>
+    <xbrli:period><xbrli:startDate>2024-01-01</xbrli:startDate><xbrli:endDate>2024-12-31</xbrli:endDate></xbrli:period>
+    <xbrli:scenario>
+      <xbrldi:explicitMember dimension="ifrs-full:ConsolidatedAndSeparateFinancialStatementsAxis">ifrs-full:SeparateMember</xbrldi:explicitMember>
+    </xbrli:scenario>
+  </xbrli:context>
+  <xbrli:context id="CFY2024eFY_Retained">
+    <xbrli:entity><xbrli:identifier scheme="http://dart.fss.or.kr">00126380</xbrli:identifier></xbrli:entity>
+    <xbrli:period><xbrli:instant>2024-12-31</xbrli:instant></xbrli:period>
+    <xbrli:scenario>
+      <xbrldi:explicitMember dimension="ifrs-full:ConsolidatedAndSeparateFinancialStatementsAxis">ifrs-full:ConsolidatedMember</xbrldi:explicitMember>
+      <xbrldi:explicitMember dimension="ifrs-full:ComponentsOfEquityAxis">ifrs-full:RetainedEarningsMember</xbrldi:explicitMember>
+    </xbrli:scenario>
+  </xbrli:context>
+  <xbrli:unit id="KRW"><xbrli:measure>iso4217:KRW</xbrli:measure></xbrli:unit>
+  <xbrli:unit id="KRWPerShare">
+    <xbrli:divide>
+      <xbrli:unitNumerator><xbrli:measure>iso4217:KRW</xbrli:measure></xbrli:unitNumerator>
+      <xbrli:unitDenominator><xbrli:measure>xbrli:shares</xbrli:measure></xbrli:unitDenominator>
+    </xbrli:divide>
+  </xbrli:unit>
+</xbrli:xbrl>
+`
+
+	It("resolves periods, scopes and currencies of the facts", func() {
+		report, err := xbrl.ParseXBRLInstance(strings.NewReader(instance))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Facts).To(HaveLen(6))
+
+		consolidated := report.Facts[0]
+		Expect(consolidated.Concept).To(Equal("ifrs-full:Revenue"))
+		Expect(consolidated.Value).To(Equal("300870903000000"))
+		Expect(*consolidated.Number).To(Equal(300870903000000.0))
+		Expect(consolidated.Decimals).To(Equal("-6"))
+		Expect(consolidated.Unit).To(Equal("KRW"))
+		Expect(consolidated.Currency).To(Equal("KRW"))
+		Expect(consolidated.StartDate).To(Equal("2024-01-01"))
+		Expect(consolidated.EndDate).To(Equal("2024-12-31"))
+		Expect(consolidated.Instant).To(BeEmpty())
+		Expect(consolidated.Scope).To(Equal(xbrl.ScopeConsolidated))
+		Expect(consolidated.Dimensions).To(BeEmpty())
+
+		separate := report.Facts[1]
+		Expect(separate.Scope).To(Equal(xbrl.ScopeSeparate))
+		Expect(*separate.Number).To(Equal(209052241000000.0))
+
+		eps := report.Facts[2]
+		Expect(eps.Unit).To(Equal("KRW/shares"))
+		Expect(eps.Currency).To(Equal("KRW"))
+
+		equity := report.Facts[3]
+		Expect(*equity.Number).To(Equal(-1500000.0))
+		Expect(equity.Instant).To(Equal("2024-12-31"))
+		Expect(equity.Scope).To(Equal(xbrl.ScopeConsolidated))
+		Expect(equity.Dimensions).To(Equal(map[string]string{"ifrs-full:ComponentsOfEquityAxis": "ifrs-full:RetainedEarningsMember"}))
+
+		documentType := report.Facts[4]
+		Expect(documentType.Concept).To(Equal("dart:DocumentType"))
+		Expect(documentType.Value).To(Equal("사업보고서"))
+		Expect(documentType.Number).To(BeNil())
+		Expect(documentType.Currency).To(BeEmpty())
+
+		Expect(report.Facts[5].Concept).To(Equal("ifrs-full:Goodwill"))
+		Expect(report.Facts[5].Number).To(BeNil())
+	})
+
+	It("reads inline XBRL facts as displayed, with their scale and sign", func() {
+		inline := `<html xmlns="http://www.w3.org/1999/xhtml" xmlns:ix="http://www.xbrl.org/2013/inlineXBRL" xmlns:xbrli="http://www.xbrl.org/2003/instance" xmlns:xbrldi="http://xbrl.org/2006/xbrldi">
+<body>
+  <div style="display:none"><ix:header><ix:resources>
+    <xbrli:context id="c1"><xbrli:entity><xbrli:identifier scheme="http://dart.fss.or.kr">00126380</xbrli:identifier></xbrli:entity><xbrli:period><xbrli:instant>2024-12-31</xbrli:instant></xbrli:period></xbrli:context>
+    <xbrli:unit id="u1"><xbrli:measure>iso4217:KRW</xbrli:measure></xbrli:unit>
+  </ix:resources></ix:header></div>
+  <p>자산총계&nbsp;<ix:nonFraction name="ifrs-full:Assets" contextRef="c1" unitRef="u1" decimals="-6" scale="6" format="ixt:num-dot-decimal">514,531,948</ix:nonFraction></p>
+  <p>당기순손실 <ix:nonFraction name="ifrs-full:ProfitLoss" contextRef="c1" unitRef="u1" scale="6" sign="-">1,234</ix:nonFraction></p>
+  <p><ix:nonNumeric name="dart:EntityRegisteredName" contextRef="c1">삼성<b>전자</b></ix:nonNumeric></p>
+</body>
+</html>`
+		Expect(xbrl.IsInstance("20250311001085.xhtml", []byte(inline))).To(BeTrue())
+
+		report, err := xbrl.ParseXBRLInstance(strings.NewReader(inline))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Facts).To(HaveLen(3))
+
+		Expect(report.Facts[0].Concept).To(Equal("ifrs-full:Assets"))
+		Expect(*report.Facts[0].Number).To(Equal(514531948e6))
+		Expect(report.Facts[0].Value).To(Equal("514531948000000"))
+		Expect(report.Facts[0].Currency).To(Equal("KRW"))
+		Expect(report.Facts[0].Instant).To(Equal("2024-12-31"))
+
+		Expect(*report.Facts[1].Number).To(Equal(-1234e6))
+
+		Expect(report.Facts[2].Value).To(Equal("삼성전자"))
+		Expect(report.Facts[2].Number).To(BeNil())
+	})
+
+	It("tells instances apart from DART documents", func() {
+		Expect(xbrl.IsInstance("20250311001085.xbrl", nil)).To(BeTrue())
+		Expect(xbrl.IsInstance("20250311001085.xml", []byte(instance))).To(BeTrue())
+		Expect(xbrl.IsInstance("20250311001085.xml", []byte(`<DOCUMENT><DOCUMENT-NAME>사업보고서</DOCUMENT-NAME></DOCUMENT>`))).To(BeFalse())
+	})
+})
@@ -1,7 +1,9 @@
 package xbrl
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
@@ -9,13 +11,12 @@ import (
 )
 
 func ParseXBRL(raw []byte) (*UsefulReport, error) {
-	rawString := string(raw)
-	rawString = strings.ReplaceAll(rawString, "<TU", "<TD")
-	rawString = strings.ReplaceAll(rawString, "</TU>", "</TD>")
-	rawString = strings.ReplaceAll(rawString, "<TE", "<TD")
-	rawString = strings.ReplaceAll(rawString, "</TE>", "</TD>")
+	return ParseXBRLReader(bytes.NewReader(raw))
+}
 
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(rawString))
+// ParseXBRLReader parses a document as it is read, without holding a copy of its raw text
+func ParseXBRLReader(r io.Reader) (*UsefulReport, error) {
+	doc, err := goquery.NewDocumentFromReader(&cellTagReader{r: r})
 	if err != nil {
 		return nil, err
 	}
@@ -33,30 +34,8 @@ func ParseXBRL(raw []byte) (*UsefulReport, error) {
 
 	var tables [][][]string
 	doc.Find("TABLE").Each(func(i int, table *goquery.Selection) {
-		rows := [][]string{}
-		table.Find("TR").Each(func(i int, row *goquery.Selection) {
-			cells := []string{}
-
-			row.Children().Each(func(i int, s *goquery.Selection) {
-				tag := goquery.NodeName(s)
-				if strings.EqualFold(tag, "td") || strings.EqualFold(tag, "th") || strings.EqualFold(tag, "tu") || strings.EqualFold(tag, "te") {
-					childText := ""
-					if s.Text() != "" {
-						childText += s.Text()
-					}
-
-					for _, node := range s.Nodes {
-						if node.Type == html.TextNode {
-							childText += node.Data
-						}
-					}
-					cells = append(cells, childText)
-				}
-			})
-
-			rows = append(rows, cells)
-		})
-		tables = append(tables, rows)
+		tables = append(tables, tableRows(table))
+		report.TypedTables = append(report.TypedTables, ParseTable(table))
 	})
 
 	report.Tables = tables
@@ -67,9 +46,41 @@ func ParseXBRL(raw []byte) (*UsefulReport, error) {
 		}
 	})
 
+	for _, root := range doc.Nodes {
+		report.Sections = append(report.Sections, parseSections(root)...)
+	}
+
 	return report, nil
 }
 
+// tableRows returns the rows of cells of a TABLE
+func tableRows(table *goquery.Selection) [][]string {
+	rows := [][]string{}
+	table.Find("TR").Each(func(i int, row *goquery.Selection) {
+		cells := []string{}
+
+		row.Children().Each(func(i int, s *goquery.Selection) {
+			tag := goquery.NodeName(s)
+			if strings.EqualFold(tag, "td") || strings.EqualFold(tag, "th") || strings.EqualFold(tag, "tu") || strings.EqualFold(tag, "te") {
+				childText := ""
+				if s.Text() != "" {
+					childText += s.Text()
+				}
+
+				for _, node := range s.Nodes {
+					if node.Type == html.TextNode {
+						childText += node.Data
+					}
+				}
+				cells = append(cells, childText)
+			}
+		})
+
+		rows = append(rows, cells)
+	})
+	return rows
+}
+
 func ReportToMarkdown(report *UsefulReport) string {
 	var builder strings.Builder
 	// --- Convert Metadata ---
@@ -79,48 +90,60 @@ func ReportToMarkdown(report *UsefulReport) string {
 	builder.WriteString("---\n\n") // Horizontal rule
 
 	// --- Convert Tables ---
-	for i, table := range report.Tables {
-		if len(table) == 0 {
-			continue // Skip empty tables
+	// tables parsed in this process know their header rows and spans
+	if len(report.TypedTables) > 0 {
+		for i, table := range report.TypedTables {
+			if len(table.Rows) == 0 {
+				continue
+			}
+			builder.WriteString(fmt.Sprintf("## Table %d\n\n", i+1))
+			builder.WriteString(table.Markdown())
+			builder.WriteString("\n---\n\n")
 		}
+	} else {
+		for i, table := range report.Tables {
+			if len(table) == 0 {
+				continue // Skip empty tables
+			}
 
-		builder.WriteString(fmt.Sprintf("## Table %d\n\n", i+1))
-
-		// Assume the first row is the header
-		headers := table[0]
-		numCols := len(headers)
+			builder.WriteString(fmt.Sprintf("## Table %d\n\n", i+1))
 
-		// Write Header
-		builder.WriteString("|")
-		for _, header := range headers {
-			// Clean up cell content for Markdown
-			cell := strings.TrimSpace(strings.ReplaceAll(header, "\n", " "))
-			builder.WriteString(fmt.Sprintf(" %s |", cell))
-		}
-		builder.WriteString("\n")
+			// Assume the first row is the header
+			headers := table[0]
+			numCols := len(headers)
 
-		// Write Separator
-		builder.WriteString("|")
-		for j := 0; j < numCols; j++ {
-			builder.WriteString(" --- |")
-		}
-		builder.WriteString("\n")
+			// Write Header
+			builder.WriteString("|")
+			for _, header := range headers {
+				// Clean up cell content for Markdown
+				cell := strings.TrimSpace(strings.ReplaceAll(header, "\n", " "))
+				builder.WriteString(fmt.Sprintf(" %s |", cell))
+			}
+			builder.WriteString("\n")
 
-		// Write Data Rows
-		for _, row := range table[1:] {
+			// Write Separator
 			builder.WriteString("|")
 			for j := 0; j < numCols; j++ {
-				var cell string
-				if j < len(row) {
-					cell = row[j]
-				}
-				// Clean up cell content
-				cell = strings.TrimSpace(strings.ReplaceAll(cell, "\n", " "))
-				builder.WriteString(fmt.Sprintf(" %s |", cell))
+				builder.WriteString(" --- |")
 			}
 			builder.WriteString("\n")
+
+			// Write Data Rows
+			for _, row := range table[1:] {
+				builder.WriteString("|")
+				for j := 0; j < numCols; j++ {
+					var cell string
+					if j < len(row) {
+						cell = row[j]
+					}
+					// Clean up cell content
+					cell = strings.TrimSpace(strings.ReplaceAll(cell, "\n", " "))
+					builder.WriteString(fmt.Sprintf(" %s |", cell))
+				}
+				builder.WriteString("\n")
+			}
+			builder.WriteString("\n---\n\n") // Separator between tables
 		}
-		builder.WriteString("\n---\n\n") // Separator between tables
 	}
 
 	// --- Convert Key Paragraphs ---
@@ -134,3 +157,61 @@ func ReportToMarkdown(report *UsefulReport) string {
 
 	return builder.String()
 }
+
+// cellTags are the cells DART writes as TU and TE, renamed to TD so they parse as table cells
+var cellTags = [][2][]byte{
+	{[]byte("<TU"), []byte("<TD")},
+	{[]byte("</TU>"), []byte("</TD>")},
+	{[]byte("<TE"), []byte("<TD")},
+	{[]byte("</TE>"), []byte("</TD>")},
+}
+
+// cellTagHold is what a chunk keeps back, a tag may start in its last bytes
+const cellTagHold = len("</TU>") - 1
+
+// cellTagReader renames the cell tags of a document while it is read
+type cellTagReader struct {
+	r     io.Reader
+	buf   []byte
+	ready int // buf[:ready] is renamed and can be returned
+	err   error
+}
+
+func (c *cellTagReader) Read(p []byte) (int, error) {
+	for c.ready == 0 {
+		if c.err != nil {
+			return 0, c.err
+		}
+		if c.buf == nil {
+			c.buf = make([]byte, 0, 32*1024)
+		}
+
+		n, err := c.r.Read(c.buf[len(c.buf):cap(c.buf)])
+		c.buf = c.buf[:len(c.buf)+n]
+		c.err = err
+		renameCellTags(c.buf)
+
+		c.ready = len(c.buf)
+		if err == nil {
+			c.ready = max(0, len(c.buf)-cellTagHold)
+		}
+	}
+
+	n := copy(p, c.buf[:c.ready])
+	c.buf = c.buf[:copy(c.buf, c.buf[n:])]
+	c.ready -= n
+	return n, nil
+}
+
+func renameCellTags(b []byte) {
+	for _, tag := range cellTags {
+		for i := 0; ; {
+			j := bytes.Index(b[i:], tag[0])
+			if j < 0 {
+				break
+			}
+			copy(b[i+j:], tag[1])
+			i += j + len(tag[1])
+		}
+	}
+}
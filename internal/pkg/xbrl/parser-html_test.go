@@ -1,6 +1,9 @@
 package xbrl_test
 
 import (
+	"strings"
+	"testing/iotest"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
@@ -70,6 +73,12 @@ var _ = Describe("ParseHTML", func() {
 			{"Total Assets", "2000"},
 		}))
 	})
+
+	It("parses a reader split inside the cell tags", func() {
+		streamed, err := xbrl.ParseXBRLReader(iotest.OneByteReader(strings.NewReader(sampleHTML)))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*streamed).To(Equal(report))
+	})
 })
 
 func mustParseReport(rawHTML string) xbrl.UsefulReport {
@@ -0,0 +1,111 @@
+package xbrl
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Parser reads a document of a filing into a UsefulReport
+type Parser interface {
+	Parse(r io.Reader) (*UsefulReport, error)
+}
+
+// ParserFunc adapts a function to Parser
+type ParserFunc func(r io.Reader) (*UsefulReport, error)
+
+func (f ParserFunc) Parse(r io.Reader) (*UsefulReport, error) {
+	return f(r)
+}
+
+// Parsing strategies
+const (
+	StrategyHTML     = "html"     // goquery, the default: metadata, tables, paragraphs, sections and typed tables
+	StrategyInstance = "instance" // XBRL instances, plain or inline: facts
+)
+
+var strategies = map[string]Parser{
+	StrategyHTML:     ParserFunc(ParseXBRLReader),
+	StrategyInstance: ParserFunc(ParseXBRLInstance),
+}
+
+// ParserOf returns the parser of a strategy
+func ParserOf(strategy string) (Parser, bool) {
+	p, ok := strategies[strategy]
+	return p, ok
+}
+
+// Strategies returns the names of the strategies
+func Strategies() []string {
+	names := make([]string, 0, len(strategies))
+	for name := range strategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Parsers chooses the strategy of a document by the name of its report
+type Parsers struct {
+	rules []parserRule
+}
+
+type parserRule struct {
+	reportName string // contained in the report name, e.g. 감사보고서
+	strategy   string
+}
+
+// NewParsers reads rules such as "재무제표=instance,감사보고서=html". The first rule whose name the report name
+// contains wins, documents no rule matches use StrategyHTML.
+func NewParsers(rules string) (*Parsers, error) {
+	ps := &Parsers{}
+	for _, rule := range strings.Split(rules, ",") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+
+		name, strategy, ok := strings.Cut(rule, "=")
+		name, strategy = strings.TrimSpace(name), strings.TrimSpace(strategy)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid parser rule %q, expected <report name>=<strategy>", rule)
+		}
+		if _, ok := strategies[strategy]; !ok {
+			return nil, fmt.Errorf("unknown parser strategy %q, expected one of %s", strategy, strings.Join(Strategies(), ", "))
+		}
+
+		ps.rules = append(ps.rules, parserRule{reportName: name, strategy: strategy})
+	}
+	return ps, nil
+}
+
+// Strategy returns the strategy of a file of a filing. XBRL instances are parsed as such whatever the rules say.
+func (ps *Parsers) Strategy(reportName, filename string, data []byte) string {
+	if IsInstance(filename, data) {
+		return StrategyInstance
+	}
+	for _, rule := range ps.rules {
+		if strings.Contains(reportName, rule.reportName) {
+			return rule.strategy
+		}
+	}
+	return StrategyHTML
+}
+
+// Parse parses a file of a filing with the strategy chosen for it
+func (ps *Parsers) Parse(reportName, filename string, data []byte) (*UsefulReport, error) {
+	return strategies[ps.Strategy(reportName, filename, data)].Parse(bytes.NewReader(data))
+}
+
+// ParseReader parses a file of a filing as it is read. Only its head is buffered to choose the strategy.
+func (ps *Parsers) ParseReader(reportName, filename string, r io.Reader) (*UsefulReport, error) {
+	br := bufio.NewReaderSize(r, instanceSniffSize)
+	head, err := br.Peek(instanceSniffSize)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	return strategies[ps.Strategy(reportName, filename, head)].Parse(br)
+}
@@ -0,0 +1,165 @@
+package xbrl_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"kosis/internal/pkg/xbrl"
+)
+
+var _ = Describe("Parsers", func() {
+	const dsd = `<?xml version="1.0" encoding="utf-8"?>
+<DOCUMENT>
+  <DOCUMENT-NAME ACODE="11011">사업보고서</DOCUMENT-NAME>
+  <COMPANY-NAME AREGCIK="00126380">삼성전자</COMPANY-NAME>
+  <BODY>
+    <SECTION-1>
+      <TITLE ATOC="Y">III. 재무에 관한 사항</TITLE>
+      <P>요약재무정보 &amp; 연결재무제표</P>
+      <TABLE>
+        <TR><TH>과목</TH><TH>제 56 기</TH></TR>
+        <TR><TD>자산총계</TD><TE>514,531,948</TE></TR>
+        <TR><TU>부채총계</TU><TE>112,339,878</TE></TR>
+      </TABLE>
+      <P>단위 : 백만원</P>
+    </SECTION-1>
+  </BODY>
+</DOCUMENT>`
+
+	It("chooses the strategy by report name, XBRL instances by their contents", func() {
+		ps, err := xbrl.NewParsers(" 감사보고서=instance, 보고서=html ,")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(ps.Strategy("[기재정정]감사보고서", "20250311001085.xml", []byte(dsd))).To(Equal(xbrl.StrategyInstance))
+		Expect(ps.Strategy("사업보고서 (2024.12)", "20250311001085.xml", []byte(dsd))).To(Equal(xbrl.StrategyHTML))
+		Expect(ps.Strategy("주요사항보고서", "20250311001085.xml", []byte(dsd))).To(Equal(xbrl.StrategyHTML))
+		Expect(ps.Strategy("감사보고서", "20250311001085.xbrl", nil)).To(Equal(xbrl.StrategyInstance))
+
+		defaults, err := xbrl.NewParsers("")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(defaults.Strategy("감사보고서", "20250311001085.xml", []byte(dsd))).To(Equal(xbrl.StrategyHTML))
+	})
+
+	It("rejects invalid rules", func() {
+		_, err := xbrl.NewParsers("감사보고서")
+		Expect(err).To(MatchError(ContainSubstring("invalid parser rule")))
+
+		_, err = xbrl.NewParsers("감사보고서=xml")
+		Expect(err).To(MatchError(ContainSubstring("unknown parser strategy \"xml\"")))
+
+		_, err = xbrl.NewParsers("감사보고서=regex")
+		Expect(err).To(MatchError(ContainSubstring("unknown parser strategy \"regex\", expected one of html, instance")))
+	})
+
+	It("parses with the chosen strategy", func() {
+		ps, err := xbrl.NewParsers("감사보고서=instance")
+		Expect(err).NotTo(HaveOccurred())
+
+		facts, err := ps.Parse("감사보고서", "20250311001085.xml", []byte(dsd))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(facts.Sections).To(BeEmpty())
+
+		report, err := ps.Parse("사업보고서", "20250311001085.xml", []byte(dsd))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Sections).To(HaveLen(1))
+	})
+
+	It("parses a stream with the strategy of its head", func() {
+		instance := `<?xml version="1.0" encoding="UTF-8"?>
+<xbrli:xbrl xmlns:xbrli="http://www.xbrl.org/2003/instance" xmlns:ifrs-full="https://xbrl.ifrs.org/taxonomy/2023-03-23/ifrs-full">
+  <xbrli:context id="CFY2024eFY"><xbrli:entity><xbrli:identifier scheme="http://dart.fss.or.kr">00126380</xbrli:identifier></xbrli:entity><xbrli:period><xbrli:instant>2024-12-31</xbrli:instant></xbrli:period></xbrli:context>
+  <ifrs-full:Assets contextRef="CFY2024eFY" decimals="-6">514531948000000</ifrs-full:Assets>
+</xbrli:xbrl>`
+		ps, err := xbrl.NewParsers("")
+		Expect(err).NotTo(HaveOccurred())
+
+		facts, err := ps.ParseReader("사업보고서", "20250311001085.xml", strings.NewReader(instance))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(facts.Facts).To(HaveLen(1))
+
+		report, err := ps.ParseReader("사업보고서", "20250311001085.xml", strings.NewReader(dsd))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Sections).To(HaveLen(1))
+	})
+
+	// testdata/golden/retired records what the node tree path, retired for the html strategy, read of the DART
+	// documents of testdata. It dropped empty cells, the html strategy keeps them so columns stay aligned.
+	It("reads what the retired node tree path read of DART documents", func() {
+		goldens, err := filepath.Glob("testdata/golden/retired/*.json")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(goldens).NotTo(BeEmpty())
+
+		html, ok := xbrl.ParserOf(xbrl.StrategyHTML)
+		Expect(ok).To(BeTrue())
+
+		for _, golden := range goldens {
+			expected, err := os.ReadFile(golden)
+			Expect(err).NotTo(HaveOccurred())
+
+			raw, err := os.ReadFile(filepath.Join("testdata", strings.TrimSuffix(filepath.Base(golden), ".json")+".xml"))
+			Expect(err).NotTo(HaveOccurred())
+			report, err := html.Parse(bytes.NewReader(raw))
+			Expect(err).NotTo(HaveOccurred())
+
+			tables := [][][]string{}
+			for _, table := range report.Tables {
+				var rows [][]string
+				for _, row := range table {
+					var cells []string
+					for _, cell := range row {
+						if cell != "" {
+							cells = append(cells, cell)
+						}
+					}
+					rows = append(rows, cells)
+				}
+				tables = append(tables, rows)
+			}
+
+			actual, err := json.Marshal(map[string]any{
+				"report_title":   report.ReportTitle,
+				"company_name":   report.CompanyName,
+				"company_cik":    report.CompanyCIK,
+				"tables":         tables,
+				"key_paragraphs": report.KeyParagraphs,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actual).To(MatchJSON(expected), "the html strategy differs from the retired path on %s", golden)
+		}
+	})
+})
+
+// BenchmarkParsers parses the periodic and audit reports and the XBRL instance of testdata with each strategy
+func BenchmarkParsers(b *testing.B) {
+	files, err := filepath.Glob("testdata/*.*")
+	if err != nil || len(files) == 0 {
+		b.Fatalf("no fixtures: %v", err)
+	}
+
+	for _, strategy := range xbrl.Strategies() {
+		parser, _ := xbrl.ParserOf(strategy)
+		for _, file := range files {
+			raw, err := os.ReadFile(file)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			b.Run(strategy+"/"+filepath.Base(file), func(b *testing.B) {
+				b.SetBytes(int64(len(raw)))
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					if _, err := parser.Parse(bytes.NewReader(raw)); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
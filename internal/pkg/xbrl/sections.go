@@ -0,0 +1,149 @@
+package xbrl
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// Section is a SECTION-n of a DART document, e.g. "II. 사업의 내용", with its content in document order
+type Section struct {
+	Title    string    `json:"title"`
+	Level    int       `json:"level"`          // n of SECTION-n
+	ID       string    `json:"id,omitempty"`   // AASSOCNOTE of the TITLE, e.g. D-0-2-0-0
+	ATOC     bool      `json:"atoc,omitempty"` // the TITLE is listed in the table of contents
+	Blocks   []Block   `json:"blocks,omitempty"`
+	Sections []Section `json:"sections,omitempty"`
+}
+
+// Block is a paragraph or a table of a section
+type Block struct {
+	Paragraph string `json:"paragraph,omitempty"`
+	Table     *Table `json:"table,omitempty"`
+}
+
+// Paragraphs returns the paragraphs of the section and its subsections in document order
+func (s *Section) Paragraphs() []string {
+	var out []string
+	s.walk(func(b Block) {
+		if b.Paragraph != "" {
+			out = append(out, b.Paragraph)
+		}
+	})
+	return out
+}
+
+// Tables returns the tables of the section and its subsections in document order
+func (s *Section) Tables() []*Table {
+	var out []*Table
+	s.walk(func(b Block) {
+		if b.Table != nil {
+			out = append(out, b.Table)
+		}
+	})
+	return out
+}
+
+// walk visits the blocks of the section before the ones of its subsections, as a section
+// starts with its own content
+func (s *Section) walk(fn func(Block)) {
+	for _, b := range s.Blocks {
+		fn(b)
+	}
+	for i := range s.Sections {
+		s.Sections[i].walk(fn)
+	}
+}
+
+// Select returns the sections whose title contains any of titles, e.g. Select("재무에 관한 사항").
+// A selected section comes with all its subsections.
+func (r *UsefulReport) Select(titles ...string) []Section {
+	var out []Section
+	var find func(sections []Section)
+	find = func(sections []Section) {
+		for _, s := range sections {
+			if matchesAny(s.Title, titles) {
+				out = append(out, s)
+				continue
+			}
+			find(s.Sections)
+		}
+	}
+	find(r.Sections)
+	return out
+}
+
+func matchesAny(title string, titles []string) bool {
+	for _, t := range titles {
+		if strings.Contains(title, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSections returns the outermost sections under root
+func parseSections(root *html.Node) []Section {
+	var sections []Section
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if level := sectionLevel(c); level > 0 {
+				sections = append(sections, parseSection(c, level))
+				continue
+			}
+			walk(c)
+		}
+	}
+	walk(root)
+	return sections
+}
+
+// sectionLevel returns n of a SECTION-n element, 0 for any other node
+func sectionLevel(n *html.Node) int {
+	if n.Type != html.ElementNode || !strings.HasPrefix(n.Data, "section-") {
+		return 0
+	}
+	level, err := strconv.Atoi(strings.TrimPrefix(n.Data, "section-"))
+	if err != nil {
+		return 0
+	}
+	return level
+}
+
+func parseSection(n *html.Node, level int) Section {
+	s := Section{Level: level}
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+
+			sel := goquery.NewDocumentFromNode(c).Selection
+			switch {
+			case sectionLevel(c) > 0:
+				s.Sections = append(s.Sections, parseSection(c, sectionLevel(c)))
+			case c.Data == "title" && s.Title == "":
+				s.Title = strings.TrimSpace(sel.Text())
+				s.ID = sel.AttrOr("aassocnote", "")
+				s.ATOC = strings.EqualFold(sel.AttrOr("atoc", ""), "Y")
+			case c.Data == "table":
+				s.Blocks = append(s.Blocks, Block{Table: ParseTable(sel)})
+			case c.Data == "p" && sel.Find("table").Length() == 0:
+				if text := strings.TrimSpace(sel.Text()); text != "" {
+					s.Blocks = append(s.Blocks, Block{Paragraph: text})
+				}
+			default:
+				// wrappers such as LIBRARY, and paragraphs holding tables
+				walk(c)
+			}
+		}
+	}
+	walk(n)
+
+	return s
+}
@@ -0,0 +1,90 @@
+package xbrl_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"kosis/internal/pkg/xbrl"
+)
+
+var _ = Describe("Sections", func() {
+	const sampleDSD = `
+<DOCUMENT>
+  <DOCUMENT-NAME ACODE="11011">사업보고서</DOCUMENT-NAME>
+  <COMPANY-NAME AREGCIK="00126380">삼성전자</COMPANY-NAME>
+  <BODY>
+    <SECTION-1 ACLASS="MANDATORY">
+      <TITLE ATOC="Y" AASSOCNOTE="D-0-2-0-0">II. 사업의 내용</TITLE>
+      <SECTION-2 ACLASS="MANDATORY">
+        <TITLE ATOC="Y" AASSOCNOTE="D-0-2-1-0">1. 사업의 개요</TITLE>
+        <P>당사는 본사를 거점으로 한국과 DX 부문 산하 해외 9개 지역총괄을 운영하고 있습니다.</P>
+        <LIBRARY>
+          <P><TABLE><TR><TH>부문</TH><TH>주요 제품</TH></TR><TR><TD>DX</TD><TU>TV, 스마트폰</TU></TR></TABLE></P>
+        </LIBRARY>
+        <P>   </P>
+      </SECTION-2>
+    </SECTION-1>
+    <SECTION-1 ACLASS="MANDATORY">
+      <TITLE ATOC="Y" AASSOCNOTE="D-0-3-0-0">III. 재무에 관한 사항</TITLE>
+      <P>요약재무정보</P>
+      <SECTION-2 ACLASS="MANDATORY">
+        <TITLE ATOC="Y" AASSOCNOTE="D-0-3-2-0">2. 연결재무제표</TITLE>
+        <TABLE><TR><TD>자산총계</TD><TE>455,905,980</TE></TR></TABLE>
+      </SECTION-2>
+    </SECTION-1>
+  </BODY>
+</DOCUMENT>
+`
+
+	var report xbrl.UsefulReport
+
+	BeforeEach(func() {
+		report = mustParseReport(sampleDSD)
+	})
+
+	It("keeps the section tree with titles, levels and table of contents ids", func() {
+		Expect(report.Sections).To(HaveLen(2))
+
+		business := report.Sections[0]
+		Expect(business.Title).To(Equal("II. 사업의 내용"))
+		Expect(business.Level).To(Equal(1))
+		Expect(business.ID).To(Equal("D-0-2-0-0"))
+		Expect(business.ATOC).To(BeTrue())
+		Expect(business.Blocks).To(BeEmpty())
+
+		Expect(business.Sections).To(HaveLen(1))
+		overview := business.Sections[0]
+		Expect(overview.Title).To(Equal("1. 사업의 개요"))
+		Expect(overview.Level).To(Equal(2))
+		Expect(overview.ID).To(Equal("D-0-2-1-0"))
+	})
+
+	It("keeps paragraphs and tables in document order", func() {
+		overview := report.Sections[0].Sections[0]
+		Expect(overview.Blocks).To(HaveLen(2))
+		Expect(overview.Blocks[0].Paragraph).To(Equal("당사는 본사를 거점으로 한국과 DX 부문 산하 해외 9개 지역총괄을 운영하고 있습니다."))
+		Expect(overview.Blocks[0].Table).To(BeNil())
+
+		table := overview.Blocks[1].Table
+		Expect(table).NotTo(BeNil())
+		Expect(table.ColumnNames()).To(Equal([]string{"부문", "주요 제품"}))
+		Expect(table.Body()[0][1].Text).To(Equal("TV, 스마트폰"))
+	})
+
+	It("selects sections by title with their subsections", func() {
+		selected := report.Select("재무에 관한 사항")
+		Expect(selected).To(HaveLen(1))
+		Expect(selected[0].Paragraphs()).To(Equal([]string{"요약재무정보"}))
+		tables := selected[0].Tables()
+		Expect(tables).To(HaveLen(1))
+		Expect(tables[0].Rows[0][0].Text).To(Equal("자산총계"))
+		Expect(*tables[0].Rows[0][1].Number).To(Equal(455905980.0))
+
+		Expect(report.Select("연결재무제표")[0].Level).To(Equal(2))
+		Expect(report.Select("감사인의 감사의견")).To(BeEmpty())
+	})
+
+	It("still lists every table of the document", func() {
+		Expect(report.Tables).To(HaveLen(2))
+	})
+})
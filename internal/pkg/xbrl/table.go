@@ -0,0 +1,415 @@
+package xbrl
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// Table is a TABLE of a DART document with its spans expanded and its cells parsed.
+// Every row has the same number of cells, a cell spanning several rows or columns
+// is repeated in each of them.
+type Table struct {
+	Caption    string   `json:"caption,omitempty"`    // e.g. (단위 : 백만원)
+	Unit       string   `json:"unit,omitempty"`       // e.g. 백만원, the first one when the caption has several
+	Multiplier float64  `json:"multiplier,omitempty"` // e.g. 1,000,000 for 백만원
+	HeaderRows int      `json:"header_rows"`
+	Rows       [][]Cell `json:"rows"`
+}
+
+// Cell is a cell of a Table
+type Cell struct {
+	Text    string   `json:"text"`
+	Number  *float64 `json:"number,omitempty"`  // the number as written, nil when the cell is not one
+	Spanned bool     `json:"spanned,omitempty"` // a copy of a cell spanning from above or the left
+}
+
+// maxSpan bounds COLSPAN and ROWSPAN, a malformed document must not blow up the grid
+const maxSpan = 100
+
+// maxGuessedHeaderRows is the most header rows guessed for a table without TH cells
+const maxGuessedHeaderRows = 3
+
+// 단위 captions, e.g. "(단위 : 백만원)", "[단위: 주, %]"
+var reUnitCaption = regexp.MustCompile(`[(\[]?\s*단위\s*[:：]\s*([^)\],\s]+)[^)\]]*[)\]]?`)
+
+// unitPrefixes are the magnitudes written before a unit, longest first
+var unitPrefixes = []struct {
+	prefix     string
+	multiplier float64
+}{
+	{"십억", 1e9},
+	{"천만", 1e7},
+	{"백만", 1e6},
+	{"십만", 1e5},
+	{"조", 1e12},
+	{"억", 1e8},
+	{"만", 1e4},
+	{"천", 1e3},
+	{"백", 1e2},
+}
+
+// Header returns the header rows
+func (t *Table) Header() [][]Cell {
+	return t.Rows[:t.HeaderRows]
+}
+
+// Body returns the rows below the header
+func (t *Table) Body() [][]Cell {
+	return t.Rows[t.HeaderRows:]
+}
+
+// ColumnNames joins the header rows of each column, e.g. "당기 금액" under a "당기" spanning two columns
+func (t *Table) ColumnNames() []string {
+	if len(t.Rows) == 0 {
+		return nil
+	}
+
+	names := make([]string, len(t.Rows[0]))
+	for col := range names {
+		var parts []string
+		for _, row := range t.Header() {
+			text := row[col].Text
+			if text == "" || len(parts) > 0 && parts[len(parts)-1] == text {
+				continue
+			}
+			parts = append(parts, text)
+		}
+		names[col] = strings.Join(parts, " ")
+	}
+	return names
+}
+
+// Column returns the index of the first column whose name contains name, -1 when there is none
+func (t *Table) Column(name string) int {
+	for i, n := range t.ColumnNames() {
+		if strings.Contains(n, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Row returns the index in Body of the first row whose first cell contains label, -1 when there is none
+func (t *Table) Row(label string) int {
+	for i, row := range t.Body() {
+		if len(row) > 0 && strings.Contains(row[0].Text, label) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Value returns the number of a body cell in plain units, e.g. 1,234 in a 백만원 table is 1,234,000,000
+func (t *Table) Value(row, col int) (float64, bool) {
+	body := t.Body()
+	if row < 0 || row >= len(body) || col < 0 || col >= len(body[row]) || body[row][col].Number == nil {
+		return 0, false
+	}
+
+	multiplier := t.Multiplier
+	if multiplier == 0 {
+		multiplier = 1
+	}
+	return *body[row][col].Number * multiplier, true
+}
+
+// Markdown renders the table with its joined header rows as the header
+func (t *Table) Markdown() string {
+	if len(t.Rows) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	if t.Caption != "" {
+		b.WriteString(t.Caption + "\n\n")
+	}
+
+	header := t.ColumnNames()
+	body := t.Body()
+	if t.HeaderRows == 0 {
+		header = make([]string, len(t.Rows[0]))
+	}
+
+	writeRow := func(cells []string) {
+		b.WriteString("|")
+		for _, cell := range cells {
+			b.WriteString(" " + strings.ReplaceAll(cell, "|", "\\|") + " |")
+		}
+		b.WriteString("\n")
+	}
+
+	writeRow(header)
+	b.WriteString("|" + strings.Repeat(" --- |", len(header)) + "\n")
+	for _, row := range body {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = cell.Text
+		}
+		writeRow(cells)
+	}
+
+	return b.String()
+}
+
+// ParseTable reads a TABLE element. Rows of a THEAD or made of TH cells are the header, a table
+// without them is taken to have its header above the first row holding numbers.
+func ParseTable(table *goquery.Selection) *Table {
+	t := &Table{}
+
+	type span struct {
+		cell Cell
+		rows int // rows left to fill
+	}
+	spans := map[int]*span{}
+
+	var headers []bool
+	width := 0
+	for _, tr := range ownRows(table) {
+		var row []Cell
+		fill := func() {
+			for s := spans[len(row)]; s != nil; s = spans[len(row)] {
+				if s.rows--; s.rows == 0 {
+					delete(spans, len(row))
+				}
+				row = append(row, s.cell)
+			}
+		}
+
+		// TH cells may label the rows of the body too, a header row is made of them only
+		allTH := true
+		hasCells := false
+		for c := tr.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode || !isCell(c.Data) {
+				continue
+			}
+			hasCells = true
+			allTH = allTH && c.Data == "th"
+
+			fill()
+			sel := goquery.NewDocumentFromNode(c).Selection
+			cell := Cell{Text: cellText(sel)}
+			if n, ok := ParseNumber(cell.Text); ok {
+				cell.Number = &n
+			}
+
+			colspan := spanAttr(sel, "colspan")
+			rowspan := spanAttr(sel, "rowspan")
+			copied := cell
+			copied.Spanned = true
+			for i := 0; i < colspan; i++ {
+				if rowspan > 1 {
+					spans[len(row)] = &span{cell: copied, rows: rowspan - 1}
+				}
+				if i == 0 {
+					row = append(row, cell)
+				} else {
+					row = append(row, copied)
+				}
+			}
+		}
+		fill()
+
+		if !hasCells && len(row) == 0 {
+			continue
+		}
+		t.Rows = append(t.Rows, row)
+		headers = append(headers, inHead(tr) || hasCells && allTH)
+		width = max(width, len(row))
+	}
+
+	for i := range t.Rows {
+		for len(t.Rows[i]) < width {
+			t.Rows[i] = append(t.Rows[i], Cell{})
+		}
+	}
+
+	t.HeaderRows = headerRows(t.Rows, headers)
+	t.Caption, t.Unit, t.Multiplier = tableUnit(table, t)
+
+	return t
+}
+
+// ownRows returns the TR of a table without the ones of the tables nested in it
+func ownRows(table *goquery.Selection) []*html.Node {
+	if len(table.Nodes) == 0 {
+		return nil
+	}
+	root := table.Nodes[0]
+
+	var rows []*html.Node
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode || c.Data == "table" {
+				continue
+			}
+			if c.Data == "tr" {
+				rows = append(rows, c)
+				continue
+			}
+			walk(c)
+		}
+	}
+	walk(root)
+	return rows
+}
+
+func inHead(tr *html.Node) bool {
+	for n := tr.Parent; n != nil && n.Data != "table"; n = n.Parent {
+		if n.Data == "thead" {
+			return true
+		}
+	}
+	return false
+}
+
+// isCell reports whether tag is a cell, TU and TE are cells of DART documents
+func isCell(tag string) bool {
+	return tag == "td" || tag == "th" || tag == "tu" || tag == "te"
+}
+
+func cellText(sel *goquery.Selection) string {
+	return strings.Join(strings.Fields(sel.Text()), " ")
+}
+
+func spanAttr(sel *goquery.Selection, name string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(sel.AttrOr(name, "1")))
+	if err != nil || n < 1 {
+		return 1
+	}
+	return min(n, maxSpan)
+}
+
+// headerRows counts the rows marked as header, or guesses them from the first row holding numbers
+func headerRows(rows [][]Cell, marked []bool) int {
+	n := 0
+	for n < len(rows) && marked[n] {
+		n++
+	}
+	if n > 0 {
+		return n
+	}
+
+	for i, row := range rows {
+		if rowHasNumber(row) {
+			if i > maxGuessedHeaderRows {
+				break
+			}
+			return i
+		}
+	}
+	return min(1, len(rows))
+}
+
+// rowHasNumber reports whether a row holds a number besides its label
+func rowHasNumber(row []Cell) bool {
+	for i, cell := range row {
+		if i == 0 && len(row) > 1 {
+			continue
+		}
+		if cell.Number != nil && cell.Text != "-" {
+			return true
+		}
+	}
+	return false
+}
+
+// tableUnit finds the 단위 caption of a table in its own cells, or in the elements right before it
+func tableUnit(table *goquery.Selection, t *Table) (string, string, float64) {
+	if len(table.Nodes) == 0 {
+		return "", "", 0
+	}
+
+	candidates := []string{}
+	for _, row := range t.Rows {
+		for _, cell := range row {
+			candidates = append(candidates, cell.Text)
+		}
+	}
+
+	node := table.Nodes[0]
+	// a paragraph holding the table may hold its caption too
+	if p := node.Parent; p != nil && p.Data == "p" {
+		candidates = append(candidates, strings.Join(strings.Fields(textBefore(p, node)), " "))
+		node = p
+	}
+	for prev, i := node.PrevSibling, 0; prev != nil && i < 2; prev = prev.PrevSibling {
+		if prev.Type != html.ElementNode {
+			continue
+		}
+		candidates = append(candidates, cellText(goquery.NewDocumentFromNode(prev).Selection))
+		i++
+	}
+
+	for _, text := range candidates {
+		if m := reUnitCaption.FindStringSubmatch(text); m != nil {
+			return strings.TrimSpace(m[0]), m[1], UnitMultiplier(m[1])
+		}
+	}
+	return "", "", 0
+}
+
+// textBefore returns the text of parent before its child
+func textBefore(parent, child *html.Node) string {
+	var b strings.Builder
+	for c := parent.FirstChild; c != nil && c != child; c = c.NextSibling {
+		b.WriteString(goquery.NewDocumentFromNode(c).Text())
+	}
+	return b.String()
+}
+
+// UnitMultiplier returns what a number written in unit is multiplied by, e.g. 1,000,000 for 백만원, 1 for 원, 주 or %
+func UnitMultiplier(unit string) float64 {
+	unit = strings.TrimSpace(unit)
+	for _, p := range unitPrefixes {
+		// 만 alone is not a unit, 만원 is
+		if strings.HasPrefix(unit, p.prefix) && len(unit) > len(p.prefix) {
+			return p.multiplier
+		}
+	}
+	return 1
+}
+
+// ParseNumber reads a number of a DART table. Commas are ignored, "(1,234)" and "△1,234" are negative,
+// "-" alone is zero and a trailing % is dropped.
+func ParseNumber(s string) (float64, bool) {
+	s = strings.TrimSpace(s)
+	switch s {
+	case "":
+		return 0, false
+	case "-", "−", "–":
+		return 0, true
+	}
+
+	neg := false
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		neg = true
+		s = strings.TrimSpace(s[1 : len(s)-1])
+	}
+	for _, sign := range []string{"△", "▲", "-", "−"} {
+		if strings.HasPrefix(s, sign) {
+			neg = !neg
+			s = strings.TrimSpace(strings.TrimPrefix(s, sign))
+			break
+		}
+	}
+	s = strings.TrimSuffix(s, "%")
+	s = strings.ReplaceAll(s, ",", "")
+
+	// ParseFloat would take Inf, NaN or 1e3 too
+	if s == "" || strings.Trim(s, "0123456789.") != "" {
+		return 0, false
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false
+	}
+	if neg {
+		n = -n
+	}
+	return n, true
+}
@@ -0,0 +1,141 @@
+package xbrl_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"kosis/internal/pkg/xbrl"
+)
+
+var _ = Describe("Table", func() {
+	const statement = `
+<DOCUMENT>
+  <P ALIGN="RIGHT">(단위 : 백만원)</P>
+  <TABLE>
+    <THEAD>
+      <TR><TH ROWSPAN="2">과 목</TH><TH COLSPAN="2">제 56 기</TH><TH COLSPAN="2">제 55 기</TH></TR>
+      <TR><TH>3개월</TH><TH>누적</TH><TH>3개월</TH><TH>누적</TH></TR>
+    </THEAD>
+    <TBODY>
+      <TR><TD>매출액</TD><TE>79,098,716</TE><TE>232,793,656</TE><TE>67,404,652</TE><TE>197,011,300</TE></TR>
+      <TR><TD>영업이익(손실)</TD><TE>(1,234)</TE><TE>△5,678</TE><TE>-</TE><TE>-12.5</TE></TR>
+      <TR><TD ROWSPAN="2">기타</TD><TD COLSPAN="2">해당사항 없음</TD><TD>1</TD><TD>2</TD></TR>
+      <TR><TD>3</TD><TD>4</TD><TD>5</TD><TD>6</TD></TR>
+    </TBODY>
+  </TABLE>
+</DOCUMENT>
+`
+
+	var table *xbrl.Table
+
+	BeforeEach(func() {
+		report, err := xbrl.ParseXBRL([]byte(statement))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.TypedTables).To(HaveLen(1))
+		table = report.TypedTables[0]
+	})
+
+	It("expands column and row spans into a rectangular grid", func() {
+		Expect(table.Rows).To(HaveLen(6))
+		for _, row := range table.Rows {
+			Expect(row).To(HaveLen(5))
+		}
+
+		Expect(table.Rows[1][0].Text).To(Equal("과 목"))
+		Expect(table.Rows[1][0].Spanned).To(BeTrue())
+
+		other := table.Body()[2]
+		Expect(other[1].Text).To(Equal("해당사항 없음"))
+		Expect(other[2].Text).To(Equal("해당사항 없음"))
+		Expect(other[2].Spanned).To(BeTrue())
+
+		Expect(table.Body()[3][0].Text).To(Equal("기타"))
+		Expect(*table.Body()[3][1].Number).To(Equal(3.0))
+	})
+
+	It("joins multi-row headers into column names", func() {
+		Expect(table.HeaderRows).To(Equal(2))
+		Expect(table.ColumnNames()).To(Equal([]string{"과 목", "제 56 기 3개월", "제 56 기 누적", "제 55 기 3개월", "제 55 기 누적"}))
+		Expect(table.Column("제 55 기 누적")).To(Equal(4))
+	})
+
+	It("parses the unit caption into a multiplier", func() {
+		Expect(table.Caption).To(Equal("(단위 : 백만원)"))
+		Expect(table.Unit).To(Equal("백만원"))
+		Expect(table.Multiplier).To(Equal(1e6))
+
+		revenue, ok := table.Value(table.Row("매출액"), table.Column("제 56 기 누적"))
+		Expect(ok).To(BeTrue())
+		Expect(revenue).To(Equal(232793656e6))
+	})
+
+	It("parses commas, parentheses, triangles and dashes", func() {
+		operating := table.Body()[table.Row("영업이익")]
+		Expect(*operating[1].Number).To(Equal(-1234.0))
+		Expect(*operating[2].Number).To(Equal(-5678.0))
+		Expect(*operating[3].Number).To(Equal(0.0))
+		Expect(*operating[4].Number).To(Equal(-12.5))
+
+		_, ok := table.Value(table.Row("기타"), 1)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("guesses the header of a table without TH cells", func() {
+		report, err := xbrl.ParseXBRL([]byte(`<TABLE>
+			<TR><TD>(단위 : 주, %)</TD><TD></TD></TR>
+			<TR><TD>구분</TD><TD>주식수</TD></TR>
+			<TR><TD>보통주</TD><TD>5,969,782,550</TD></TR>
+		</TABLE>`))
+		Expect(err).NotTo(HaveOccurred())
+
+		t := report.TypedTables[0]
+		Expect(t.HeaderRows).To(Equal(2))
+		Expect(t.Unit).To(Equal("주"))
+		Expect(t.Multiplier).To(Equal(1.0))
+		shares, ok := t.Value(0, 1)
+		Expect(ok).To(BeTrue())
+		Expect(shares).To(Equal(5969782550.0))
+	})
+
+	It("renders markdown with the joined header", func() {
+		md := table.Markdown()
+		Expect(md).To(HavePrefix("(단위 : 백만원)\n\n| 과 목 | 제 56 기 3개월 |"))
+		Expect(strings.Count(md, "\n")).To(Equal(2 + 2 + 4))
+	})
+
+	DescribeTable("ParseNumber",
+		func(s string, expected float64, ok bool) {
+			n, parsed := xbrl.ParseNumber(s)
+			Expect(parsed).To(Equal(ok))
+			if ok {
+				Expect(n).To(Equal(expected))
+			}
+		},
+		Entry("commas", "1,234,567", 1234567.0, true),
+		Entry("parentheses", "(1,234)", -1234.0, true),
+		Entry("triangle", "△ 1,234", -1234.0, true),
+		Entry("dash", "-", 0.0, true),
+		Entry("negative", "-1,234.5", -1234.5, true),
+		Entry("percent", "12.3%", 12.3, true),
+		Entry("text", "해당사항 없음", 0.0, false),
+		Entry("empty", "", 0.0, false),
+		Entry("infinity", "Inf", 0.0, false),
+		Entry("exponent", "1e3", 0.0, false),
+	)
+
+	DescribeTable("UnitMultiplier",
+		func(unit string, expected float64) {
+			Expect(xbrl.UnitMultiplier(unit)).To(Equal(expected))
+		},
+		Entry("원", "원", 1.0),
+		Entry("천원", "천원", 1e3),
+		Entry("백만원", "백만원", 1e6),
+		Entry("억원", "억원", 1e8),
+		Entry("십억원", "십억원", 1e9),
+		Entry("조원", "조원", 1e12),
+		Entry("천주", "천주", 1e3),
+		Entry("%", "%", 1.0),
+	)
+})
@@ -83,6 +83,18 @@ func SetupRouter(db *gorm.DB, cfg *config.Config) *gin.Engine {
 		// Companies endpoints
 		api.GET("/companies", financialController.GetCompanies)
 
+		// Financial statements time series
+		api.GET("/companies/:corp_code/financials", financialController.GetFinancials)
+
+		// Dividends by year
+		api.GET("/companies/:corp_code/dividends", financialController.GetDividends)
+
+		// Treasury stock events and buyback authorizations
+		api.GET("/companies/:corp_code/treasury-stock", financialController.GetTreasuryStock)
+
+		// Insider and major holder net buying per company
+		api.GET("/insider-trading", financialController.GetInsiderNetBuying)
+
 		// MCP-friendly endpoints
 		api.GET("/mcp/reports/by-corp-name", financialController.GetReportsByCorpName)
 
@@ -98,6 +110,10 @@ func SetupRouter(db *gorm.DB, cfg *config.Config) *gin.Engine {
 		// Summary + raw report by receipt number
 		api.GET("/mcp/reports/receipt/:receipt_number", financialController.GetReportSummaryByReceiptNumber)
 
+		// Main document and attachments of a filing
+		api.GET("/reports/receipt/:receipt_number/files", financialController.GetReportFiles)
+		api.GET("/reports/receipt/:receipt_number/files/:filename", financialController.GetReportFile)
+
 		// Reports endpoints
 		api.GET("/reports", financialController.GetAllReports)
 
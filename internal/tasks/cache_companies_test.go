@@ -35,6 +35,29 @@ var _ = Describe("HandleFetchCompaniesTask", func() {
         <modify_date>20170630</modify_date>
     </list>
 </result>`
+	var listedXML = `<?xml version="1.0" encoding="UTF-8"?>
+<result>
+    <list>
+        <corp_code>00126380</corp_code>
+        <corp_name>삼성전자</corp_name>
+        <corp_eng_name>SAMSUNG ELECTRONICS CO,.LTD</corp_eng_name>
+        <stock_code>005930</stock_code>
+        <modify_date>20250301</modify_date>
+    </list>
+</result>`
+	var companyInfo = `{
+		"status": "000",
+		"message": "정상",
+		"corp_code": "00126380",
+		"corp_name": "삼성전자(주)",
+		"stock_code": "005930",
+		"ceo_nm": "전영현",
+		"corp_cls": "Y",
+		"adres": "경기도 수원시 영통구 삼성로 129 (매탄동)",
+		"hm_url": "www.samsung.com/sec",
+		"induty_code": "264",
+		"acc_mt": "12"
+	}`
 
 	BeforeEach(func() {
 		cfg, err := config.LoadConfig()
@@ -45,7 +68,8 @@ var _ = Describe("HandleFetchCompaniesTask", func() {
 
 		testhelpers.CleanupDB(dbConn)
 
-		p = tasks.NewTaskProcessor(dbConn, cfg)
+		p, err = tasks.NewTaskProcessor(dbConn, cfg, &testhelpers.RecordingEnqueuer{})
+		Expect(err).NotTo(HaveOccurred())
 
 		testhelpers.Activate()
 		p.GetDartClient().UseDefaultClient()
@@ -112,4 +136,114 @@ var _ = Describe("HandleFetchCompaniesTask", func() {
 		Expect(companies[1].CorpEngName).To(Equal("Good & LS Co.,Ltd."))
 		Expect(companies[1].LastModifiedDate).To(Equal(time.Date(2017, 6, 30, 0, 0, 0, 0, time.UTC)))
 	})
+
+	It("fills listed companies from company.json", func() {
+		zipDocument, err := testhelpers.CreateMockZipArchive("corpCode.xml", []byte(listedXML))
+		Expect(err).NotTo(HaveOccurred())
+
+		testhelpers.New("https://opendart.fss.or.kr").Get("/api/corpCode.xml").Reply(200).Body(zipDocument).Header("Content-Type", "application/zip").Header("Content-Disposition", `attachment; filename="corpCode.zip"`)
+		testhelpers.New("https://opendart.fss.or.kr").Get("/api/company.json?corp_code=00126380").Reply(200).BodyString(companyInfo)
+
+		ctx := context.Background()
+		err = p.HandleFetchCompaniesTask(ctx, asynq.NewTask(tasks.TypeTaskFetchCompanies, []byte("{}")))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(testhelpers.IsDone()).To(BeTrue())
+
+		company, err := gorm.G[models.Company](dbConn).Where("corp_code = ?", "00126380").First(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(company.StockCode).To(Equal("005930"))
+		Expect(company.Category).To(Equal("Y"))
+		Expect(company.CEOName).To(Equal("전영현"))
+		Expect(company.IndustryCode).To(Equal("264"))
+		Expect(company.Homepage).To(Equal("www.samsung.com/sec"))
+		Expect(company.FiscalMonth).To(Equal("12"))
+		Expect(company.Address).To(Equal("경기도 수원시 영통구 삼성로 129 (매탄동)"))
+		Expect(company.InfoUpdatedAt).NotTo(BeNil())
+	})
+
+	It("leaves the category of a new listed company unknown until company.json tells it", func() {
+		zipDocument, err := testhelpers.CreateMockZipArchive("corpCode.xml", []byte(listedXML))
+		Expect(err).NotTo(HaveOccurred())
+
+		testhelpers.New("https://opendart.fss.or.kr").Get("/api/corpCode.xml").Reply(200).Body(zipDocument).Header("Content-Type", "application/zip").Header("Content-Disposition", `attachment; filename="corpCode.zip"`)
+		testhelpers.New("https://opendart.fss.or.kr").Get("/api/company.json?corp_code=00126380").Reply(200).BodyString(`{"status": "013", "message": "조회된 데이타가 없습니다."}`)
+
+		ctx := context.Background()
+		err = p.HandleFetchCompaniesTask(ctx, asynq.NewTask(tasks.TypeTaskFetchCompanies, []byte("{}")))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(testhelpers.IsDone()).To(BeTrue())
+
+		company, err := gorm.G[models.Company](dbConn).Where("corp_code = ?", "00126380").First(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(company.Category).To(BeEmpty())
+		Expect(company.InfoUpdatedAt).To(BeNil())
+	})
+
+	It("keeps a history of name and classification changes", func() {
+		ctx := context.Background()
+		infoUpdatedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		company := models.Company{
+			CorpCode:         "00126380",
+			CorpName:         "삼성전자공업",
+			CorpEngName:      "SAMSUNG ELECTRONICS CO,.LTD",
+			StockCode:        "005930",
+			Category:         "K",
+			LastModifiedDate: time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC),
+			InfoUpdatedAt:    &infoUpdatedAt,
+		}
+		Expect(gorm.G[models.Company](dbConn).Create(ctx, &company)).To(Succeed())
+
+		zipDocument, err := testhelpers.CreateMockZipArchive("corpCode.xml", []byte(listedXML))
+		Expect(err).NotTo(HaveOccurred())
+
+		testhelpers.New("https://opendart.fss.or.kr").Get("/api/corpCode.xml").Reply(200).Body(zipDocument).Header("Content-Type", "application/zip").Header("Content-Disposition", `attachment; filename="corpCode.zip"`)
+		testhelpers.New("https://opendart.fss.or.kr").Get("/api/company.json?corp_code=00126380").Reply(200).BodyString(companyInfo)
+
+		err = p.HandleFetchCompaniesTask(ctx, asynq.NewTask(tasks.TypeTaskFetchCompanies, []byte("{}")))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(testhelpers.IsDone()).To(BeTrue())
+
+		history, err := gorm.G[models.CompanyHistory](dbConn).Where("corp_code = ?", "00126380").Order("field").Find(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(history).To(HaveLen(2))
+		Expect(history[0].Field).To(Equal("category"))
+		Expect(history[0].OldValue).To(Equal("K"))
+		Expect(history[0].NewValue).To(Equal("Y"))
+		Expect(history[1].Field).To(Equal("corp_name"))
+		Expect(history[1].OldValue).To(Equal("삼성전자공업"))
+		Expect(history[1].NewValue).To(Equal("삼성전자"))
+	})
+
+	It("leaves companies whose modify date did not change untouched", func() {
+		ctx := context.Background()
+		company := models.Company{
+			CorpCode:         "00434003",
+			CorpName:         "다코",
+			CorpEngName:      "Daco (renamed without a new modify date)",
+			Category:         "E",
+			LastModifiedDate: time.Date(2017, 6, 30, 0, 0, 0, 0, time.UTC),
+		}
+		Expect(gorm.G[models.Company](dbConn).Create(ctx, &company)).To(Succeed())
+
+		zipDocument, err := testhelpers.CreateMockZipArchive("corpCode.xml", []byte(companiesXML))
+		Expect(err).NotTo(HaveOccurred())
+
+		testhelpers.New("https://opendart.fss.or.kr").Get("/api/corpCode.xml").Reply(200).Body(zipDocument).Header("Content-Type", "application/zip").Header("Content-Disposition", `attachment; filename="corpCode.zip"`)
+
+		err = p.HandleFetchCompaniesTask(ctx, asynq.NewTask(tasks.TypeTaskFetchCompanies, []byte("{}")))
+		Expect(err).NotTo(HaveOccurred())
+
+		unchanged, err := gorm.G[models.Company](dbConn).Where("corp_code = ?", "00434003").First(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(unchanged.CorpEngName).To(Equal("Daco (renamed without a new modify date)"))
+		Expect(unchanged.UpdatedAt).To(BeTemporally("~", company.UpdatedAt, time.Millisecond))
+
+		count, err := gorm.G[models.Company](dbConn).Count(ctx, "id")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(count).To(Equal(int64(2)))
+
+		history, err := gorm.G[models.CompanyHistory](dbConn).Find(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(history).To(BeEmpty())
+	})
 })
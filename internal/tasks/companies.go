@@ -0,0 +1,215 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"kosis/internal/models"
+	"kosis/internal/pkg/dart"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// unlistedCategory is the corp_cls of companies without a stock code
+const unlistedCategory = "E"
+
+// companyBatchSize is the number of corpCode.xml rows staged and upserted at a time
+const companyBatchSize = 1000
+
+// FetchCompaniesResult is the result of a fetch companies task
+type FetchCompaniesResult struct {
+	Inserted    int64 `json:"inserted"`
+	Updated     int64 `json:"updated"`
+	Unchanged   int64 `json:"unchanged"`
+	InfoUpdated int64 `json:"info_updated"` // listed companies refreshed from company.json
+}
+
+// companyStaging is a row of the company_staging temporary table
+type companyStaging struct {
+	CorpCode    string
+	CorpName    string
+	CorpEngName string
+	StockCode   string
+	ModifyDate  time.Time
+}
+
+// syncCompanies streams corpCode.xml into a staging table and upserts it into companies in batches.
+// Only companies whose modify_date changed are touched, their name, stock code or classification
+// changes are kept in company_histories.
+func (p *TaskProcessor) syncCompanies(ctx context.Context) (*FetchCompaniesResult, error) {
+	result := &FetchCompaniesResult{}
+
+	err := p.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// the temporary table only lives on the connection of the transaction
+		err := tx.Exec(`CREATE TEMPORARY TABLE company_staging (
+			seq           BIGSERIAL,
+			corp_code     VARCHAR(64) NOT NULL,
+			corp_name     VARCHAR(255) NOT NULL,
+			corp_eng_name VARCHAR(255) NOT NULL,
+			stock_code    VARCHAR(16) NOT NULL,
+			modify_date   DATE NOT NULL
+		) ON COMMIT DROP`).Error
+		if err != nil {
+			return err
+		}
+
+		var staged int64
+		batch := make([]companyStaging, 0, companyBatchSize)
+		flush := func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+			if err := tx.Table("company_staging").Create(&batch).Error; err != nil {
+				return err
+			}
+			staged += int64(len(batch))
+			batch = batch[:0]
+			return nil
+		}
+
+		err = p.dartClient.EachCompany(func(company dart.Company) error {
+			modifyDate, err := time.Parse("20060102", company.ModifyDate)
+			if err != nil {
+				return fmt.Errorf("failed to parse last modified date of %s: %w", company.CorpCode, err)
+			}
+
+			batch = append(batch, companyStaging{
+				CorpCode:    company.CorpCode,
+				CorpName:    company.CorpName,
+				CorpEngName: company.CorpEngName,
+				StockCode:   company.StockCode,
+				ModifyDate:  modifyDate,
+			})
+			if len(batch) < companyBatchSize {
+				return nil
+			}
+			return flush()
+		})
+		if err != nil {
+			return err
+		}
+		if err := flush(); err != nil {
+			return err
+		}
+
+		log.Printf("staged %d companies", staged)
+
+		err = tx.Raw(`SELECT
+			COUNT(*) FILTER (WHERE c.id IS NULL) AS inserted,
+			COUNT(*) FILTER (WHERE c.id IS NOT NULL AND c.last_modified_date <> s.modify_date) AS updated,
+			COUNT(*) FILTER (WHERE c.last_modified_date = s.modify_date) AS unchanged
+			FROM company_staging s
+			LEFT JOIN companies c ON c.corp_code = s.corp_code`).Scan(result).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Exec(`INSERT INTO company_histories (corp_code, field, old_value, new_value, created_at)
+			SELECT c.corp_code, f.field, f.old_value, f.new_value, now()
+			FROM company_staging s
+			JOIN companies c ON c.corp_code = s.corp_code
+			CROSS JOIN LATERAL (VALUES
+				('corp_name', c.corp_name, s.corp_name),
+				('corp_eng_name', c.corp_eng_name, s.corp_eng_name),
+				('stock_code', c.stock_code, s.stock_code),
+				('category', COALESCE(c.category, ''), CASE
+					WHEN s.stock_code = '' THEN ?
+					WHEN c.stock_code = '' THEN ''
+					ELSE COALESCE(c.category, '')
+				END)
+			) AS f(field, old_value, new_value)
+			WHERE c.last_modified_date <> s.modify_date AND f.old_value <> f.new_value`, unlistedCategory).Error
+		if err != nil {
+			return err
+		}
+
+		// the category of a listed company is unknown until company.json tells it, one that was
+		// listed keeps its category until then
+		for from := int64(0); from < staged; from += companyBatchSize {
+			err := tx.Exec(`INSERT INTO companies (corp_code, corp_name, corp_eng_name, stock_code, last_modified_date, category, created_at, updated_at)
+				SELECT DISTINCT ON (corp_code) corp_code, corp_name, corp_eng_name, stock_code, modify_date,
+					CASE WHEN stock_code = '' THEN ? ELSE '' END, now(), now()
+				FROM company_staging
+				WHERE seq > ? AND seq <= ?
+				ORDER BY corp_code, seq DESC
+				ON CONFLICT (corp_code) DO UPDATE SET
+					corp_name = EXCLUDED.corp_name,
+					corp_eng_name = EXCLUDED.corp_eng_name,
+					stock_code = EXCLUDED.stock_code,
+					last_modified_date = EXCLUDED.last_modified_date,
+					category = CASE WHEN EXCLUDED.stock_code = '' OR companies.stock_code = '' THEN EXCLUDED.category ELSE companies.category END,
+					updated_at = EXCLUDED.updated_at
+				WHERE companies.last_modified_date IS DISTINCT FROM EXCLUDED.last_modified_date`,
+				unlistedCategory, from, from+companyBatchSize).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// listed companies that are new or changed since their last company.json
+	companies, err := gorm.G[models.Company](p.DB).
+		Where("stock_code <> '' AND (info_updated_at IS NULL OR info_updated_at < updated_at)").
+		Find(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, company := range companies {
+		ok, err := p.updateCompanyInfo(ctx, company)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			result.InfoUpdated++
+		}
+	}
+
+	return result, nil
+}
+
+// updateCompanyInfo fills a listed company from company.json. A company DART has no overview for is
+// left as is and tried again by the next sync, so it reports whether the company was updated.
+func (p *TaskProcessor) updateCompanyInfo(ctx context.Context, company models.Company) (bool, error) {
+	info, err := p.dartClient.GetCompanyInfo(company.CorpCode)
+	if classifyDartError(err) == dartStop {
+		return false, err
+	}
+	if err != nil {
+		log.Printf("failed to get company info of %s: %v", company.CorpCode, err)
+		return false, nil
+	}
+
+	return true, p.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// a new company has no category worth recording yet
+		if company.InfoUpdatedAt != nil && company.Category != info.CorpCls {
+			history := models.CompanyHistory{
+				CorpCode: company.CorpCode,
+				Field:    "category",
+				OldValue: company.Category,
+				NewValue: info.CorpCls,
+			}
+			if err := tx.Create(&history).Error; err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		return tx.Model(&company).UpdateColumns(map[string]any{
+			"category":        info.CorpCls,
+			"ceo_name":        info.CEOName,
+			"industry_code":   info.IndustryCode,
+			"homepage":        info.Homepage,
+			"fiscal_month":    info.AccMonth,
+			"address":         info.Address,
+			"info_updated_at": now,
+			"updated_at":      now,
+		}).Error
+	})
+}
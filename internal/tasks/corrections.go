@@ -0,0 +1,296 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"kosis/internal/models"
+	"kosis/internal/pkg/dart"
+	"log"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// fieldChange is an entry of raw_reports.correction_diff
+type fieldChange struct {
+	Path   string `json:"path"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+// correctionIgnoredFields differ between every correction and its original by nature
+var correctionIgnoredFields = map[string]bool{
+	"rcept_no":  true,
+	"amendment": true,
+}
+
+// originalOf returns the receipt number of the filing a correction amends. It is empty when the filing is not
+// a correction or its original cannot be found yet, the correction is then linked once the original is stored.
+func (p *TaskProcessor) originalOf(ctx context.Context, rawReport *models.RawReport) (string, error) {
+	if !dart.IsCorrection(rawReport.ReportName) {
+		return "", nil
+	}
+
+	amendment, err := dart.ParseAmendmentHTML(string(rawReport.BlobData))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse amendment: %w", err)
+	}
+
+	original, err := p.resolveOriginal(ctx, rawReport, amendment)
+	if err != nil {
+		return "", err
+	}
+	if original == "" {
+		log.Printf("original of correction not found: %s %s", rawReport.ReceiptNumber, rawReport.ReportName)
+	}
+	return original, nil
+}
+
+// linkCorrections records which filing a correction amends and the fields its analysis changed. When the filing
+// is an original, the corrections stored before it are linked to it now. It runs in the transaction that stores
+// the analysis of the filing, so a filing is only stored once it is linked.
+func (p *TaskProcessor) linkCorrections(tx *gorm.DB, rawReport *models.RawReport, original string, analysis json.RawMessage) error {
+	if original != "" {
+		before, err := analysisOf(tx, original)
+		if err != nil {
+			return err
+		}
+		diff, err := correctionDiff(before, analysis)
+		if err != nil {
+			return err
+		}
+
+		rawReport.CorrectsReceiptNumber = &original
+		rawReport.CorrectionDiff = diff
+		if err := tx.Model(rawReport).Select("corrects_receipt_number", "correction_diff").Updates(rawReport).Error; err != nil {
+			return err
+		}
+	}
+
+	if dart.IsCorrection(rawReport.ReportName) {
+		return nil
+	}
+	return p.relinkCorrections(tx, rawReport, analysis)
+}
+
+// relinkCorrections links the corrections of an original that were stored before it. They either could not find
+// it or found it before it was analyzed, so they have no diff.
+func (p *TaskProcessor) relinkCorrections(tx *gorm.DB, original *models.RawReport, analysis json.RawMessage) error {
+	base := dart.BaseReportName(original.ReportName)
+
+	var corrections []models.RawReport
+	err := tx.Model(&models.RawReport{}).
+		Select("id", "receipt_number", "corp_code", "report_name", "corrects_receipt_number").
+		Where("corp_code = ? AND receipt_number > ?", original.CorpCode, original.ReceiptNumber).
+		Where("corrects_receipt_number IS NULL OR corrects_receipt_number = ?", original.ReceiptNumber).
+		Order("receipt_number").
+		Find(&corrections).Error
+	if err != nil {
+		return err
+	}
+
+	for _, c := range corrections {
+		if !dart.IsCorrection(c.ReportName) || dart.BaseReportName(c.ReportName) != base {
+			continue
+		}
+
+		if c.CorrectsReceiptNumber == nil {
+			// the document is only loaded for the corrections of the same report
+			var blobs [][]byte
+			if err := tx.Model(&models.RawReport{}).Where("id = ?", c.ID).Pluck("blob_data", &blobs).Error; err != nil {
+				return err
+			}
+			if len(blobs) == 0 {
+				continue
+			}
+			amendment, err := dart.ParseAmendmentHTML(string(blobs[0]))
+			if err != nil {
+				return fmt.Errorf("failed to parse amendment of %s: %w", c.ReceiptNumber, err)
+			}
+			resolved, err := localOriginal(tx, &c, amendment)
+			if err != nil {
+				return err
+			}
+			if resolved != original.ReceiptNumber {
+				continue
+			}
+		}
+
+		after, err := analysisOf(tx, c.ReceiptNumber)
+		if err != nil {
+			return err
+		}
+		diff, err := correctionDiff(analysis, after)
+		if err != nil {
+			return err
+		}
+
+		c.CorrectsReceiptNumber = &original.ReceiptNumber
+		c.CorrectionDiff = diff
+		if err := tx.Model(&c).Select("corrects_receipt_number", "correction_diff").Updates(&c).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveOriginal returns the receipt number of the first filing a correction amends, empty when it is unknown.
+// Earlier filings of the company with the same report name are searched first, then the filings DART lists for
+// the submission date of the wrapper when it has one.
+func (p *TaskProcessor) resolveOriginal(ctx context.Context, rawReport *models.RawReport, amendment *dart.Amendment) (string, error) {
+	original, err := localOriginal(p.DB.WithContext(ctx), rawReport, amendment)
+	if err != nil || original != "" {
+		return original, err
+	}
+
+	if amendment == nil || amendment.OriginalDate == nil {
+		return "", nil
+	}
+
+	d := *amendment.OriginalDate
+	list, err := p.dartClient.GetAllRawReports(rawReport.CorpCode, d, d, 0)
+	if err != nil {
+		return "", fmt.Errorf("failed to list filings of %s: %w", d.Format("20060102"), err)
+	}
+	base := dart.BaseReportName(rawReport.ReportName)
+	for _, item := range list {
+		if item.RceptNo < rawReport.ReceiptNumber && !dart.IsCorrection(item.ReportNm) && dart.BaseReportName(item.ReportNm) == base {
+			return item.RceptNo, nil
+		}
+	}
+
+	return "", nil
+}
+
+// localOriginal searches the earlier filings of the company with the same report name for the original of a
+// correction, on the submission date of the wrapper when it has one
+func localOriginal(db *gorm.DB, rawReport *models.RawReport, amendment *dart.Amendment) (string, error) {
+	base := dart.BaseReportName(rawReport.ReportName)
+
+	date := ""
+	if amendment != nil && amendment.OriginalDate != nil {
+		date = amendment.OriginalDate.Format("20060102")
+	}
+
+	var candidates []models.RawReport
+	err := db.
+		Model(&models.RawReport{}).
+		Select("id", "receipt_number", "report_name", "corrects_receipt_number").
+		Where("corp_code = ? AND receipt_number < ?", rawReport.CorpCode, rawReport.ReceiptNumber).
+		Order("receipt_number DESC").
+		Find(&candidates).Error
+	if err != nil {
+		return "", err
+	}
+
+	for _, c := range candidates {
+		if dart.BaseReportName(c.ReportName) != base || date != "" && !strings.HasPrefix(c.ReceiptNumber, date) {
+			continue
+		}
+		// an earlier correction points at the same original
+		if c.CorrectsReceiptNumber != nil {
+			return *c.CorrectsReceiptNumber, nil
+		}
+		if !dart.IsCorrection(c.ReportName) {
+			return c.ReceiptNumber, nil
+		}
+	}
+
+	return "", nil
+}
+
+// correctionDiff compares the analysis of a correction with the one of its original, nil when either is missing
+func correctionDiff(before, after json.RawMessage) (json.RawMessage, error) {
+	if before == nil || after == nil {
+		return nil, nil
+	}
+
+	changes, err := diffAnalysis(before, after)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(changes)
+}
+
+// analysisOf returns the analysis of a raw report, nil when there is none
+func analysisOf(db *gorm.DB, receiptNumber string) (json.RawMessage, error) {
+	var analysis models.Analysis
+	err := db.
+		Model(&models.Analysis{}).
+		Select("analyses.*").
+		Joins("JOIN raw_reports ON analyses.raw_report_id = raw_reports.id").
+		Where("raw_reports.receipt_number = ?", receiptNumber).
+		First(&analysis).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return analysis.Analysis, nil
+}
+
+// diffAnalysis lists the leaf fields that differ between two analyses, sorted by path.
+// Objects are flattened into dotted paths and arrays into indexes, e.g. "investors[0].amount_krw".
+func diffAnalysis(before, after json.RawMessage) ([]fieldChange, error) {
+	var b, a any
+	if err := json.Unmarshal(before, &b); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal original analysis: %w", err)
+	}
+	if err := json.Unmarshal(after, &a); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal corrected analysis: %w", err)
+	}
+
+	bf, af := map[string]any{}, map[string]any{}
+	flattenJSON("", b, bf)
+	flattenJSON("", a, af)
+
+	paths := map[string]bool{}
+	for path := range bf {
+		paths[path] = true
+	}
+	for path := range af {
+		paths[path] = true
+	}
+
+	changes := []fieldChange{}
+	for path := range paths {
+		root := path
+		if i := strings.IndexAny(root, ".["); i >= 0 {
+			root = root[:i]
+		}
+		if correctionIgnoredFields[root] || reflect.DeepEqual(bf[path], af[path]) {
+			continue
+		}
+		changes = append(changes, fieldChange{Path: path, Before: bf[path], After: af[path]})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+
+	return changes, nil
+}
+
+func flattenJSON(prefix string, v any, out map[string]any) {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, child := range t {
+			path := k
+			if prefix != "" {
+				path = prefix + "." + k
+			}
+			flattenJSON(path, child, out)
+		}
+	case []any:
+		for i, child := range t {
+			flattenJSON(prefix+"["+strconv.Itoa(i)+"]", child, out)
+		}
+	default:
+		out[prefix] = t
+	}
+}
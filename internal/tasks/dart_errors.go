@@ -0,0 +1,76 @@
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"kosis/internal/pkg/dart"
+	"log"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+// dartFailure is what a task does about an error of a DART call
+type dartFailure int
+
+const (
+	dartRetry dartFailure = iota // transient, e.g. 800, 900 or a network error: asynq retries the task
+	dartSkip                     // retrying cannot change the answer, e.g. 013, 014 or 100: the task is dropped
+	dartStop                     // the key cannot call DART for now, e.g. 010, 012, 020 or 901: every DART task waits for the quota reset
+)
+
+// errDartHalted is returned by tasks that would call DART after a stopping error
+var errDartHalted = errors.New("DART calls halted")
+
+// dartHalt is the stopping error DART calls were halted on, for the rest of its quota day
+type dartHalt struct {
+	day   string
+	cause error
+}
+
+func classifyDartError(err error) dartFailure {
+	switch {
+	case errors.Is(err, dart.ErrNoData),
+		errors.Is(err, dart.ErrDocumentNotFound),
+		errors.Is(err, dart.ErrInvalidField):
+		return dartSkip
+	case errors.Is(err, dart.ErrUnregisteredKey),
+		errors.Is(err, dart.ErrUnavailableKey),
+		errors.Is(err, dart.ErrForbiddenIP),
+		errors.Is(err, dart.ErrAccountExpired),
+		errors.Is(err, dart.ErrRequestLimitExceeded),
+		errors.Is(err, dart.ErrQuotaExceeded):
+		return dartStop
+	}
+	return dartRetry
+}
+
+// dartError prepares an error of a DART call for asynq: skipped errors are not retried
+// and stopping errors halt the DART calls of every task of the processor
+func (p *TaskProcessor) dartError(err error) error {
+	switch classifyDartError(err) {
+	case dartSkip:
+		return fmt.Errorf("%w: %w", err, asynq.SkipRetry)
+	case dartStop:
+		p.haltDart(err)
+	}
+	return err
+}
+
+// haltDart stops DART calls until the quota resets when err is a stopping error
+func (p *TaskProcessor) haltDart(err error) {
+	if classifyDartError(err) != dartStop {
+		return
+	}
+	log.Printf("halting DART calls until the quota resets: %v", err)
+	p.halt.Store(&dartHalt{day: dart.QuotaDay(time.Now()), cause: err})
+}
+
+// checkDart returns an error when DART calls are halted for today, so tasks fail without calling DART
+func (p *TaskProcessor) checkDart() error {
+	h := p.halt.Load()
+	if h == nil || h.day != dart.QuotaDay(time.Now()) {
+		return nil
+	}
+	return fmt.Errorf("%w: %w", errDartHalted, h.cause)
+}
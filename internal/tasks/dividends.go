@@ -0,0 +1,130 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"kosis/internal/models"
+	"kosis/internal/pkg/dart"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// HandleFetchDividendsTask stores the dividends of a company's periodic report, the business report
+// unless the payload names another. Without a corp code it enqueues one task per listed company.
+func (p *TaskProcessor) HandleFetchDividendsTask(ctx context.Context, t *asynq.Task) error {
+	var payload FetchDividendsPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	reportCode := dart.BUSINESS_REPORT
+	if payload.ReprtCode != nil {
+		reportCode = *payload.ReprtCode
+	}
+
+	// quarterly and half-year reports of a year are filed within it, its business report the year after
+	bsnsYear := time.Now().Year()
+	if reportCode == dart.BUSINESS_REPORT {
+		bsnsYear--
+	}
+	if payload.BsnsYear != nil {
+		year, err := strconv.Atoi(*payload.BsnsYear)
+		if err != nil {
+			return fmt.Errorf("invalid bsns_year %q: %w", *payload.BsnsYear, asynq.SkipRetry)
+		}
+		bsnsYear = year
+	}
+	year := strconv.Itoa(bsnsYear)
+
+	if payload.CorpCode == nil {
+		return p.enqueueFetchDividends(ctx, year, reportCode)
+	}
+
+	log.Printf("Fetching dividends of %s for %s (%s)", *payload.CorpCode, year, reportCode)
+
+	if err := p.checkDart(); err != nil {
+		return err
+	}
+
+	items, err := p.dartClient.GetDividends(*payload.CorpCode, year, reportCode)
+	if errors.Is(err, dart.ErrNoData) {
+		log.Printf("no dividends of %s for %s", *payload.CorpCode, year)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get dividends: %w", p.dartError(err))
+	}
+
+	dividends := []models.Dividend{}
+	for _, d := range dart.DividendsByYear(items, bsnsYear) {
+		if d.StockKind == "" {
+			continue
+		}
+
+		dividends = append(dividends, models.Dividend{
+			CorpCode:          *payload.CorpCode,
+			Year:              d.Year,
+			StockKind:         d.StockKind,
+			DividendPerShare:  d.DividendPerShare,
+			DividendYield:     d.DividendYield,
+			PayoutRatio:       d.PayoutRatio,
+			EPS:               d.EPS,
+			TotalCashDividend: d.TotalCashDividend,
+			SettlementDate:    d.SettlementDate,
+			SourceYear:        bsnsYear,
+			ReceiptNumber:     d.ReceiptNumber,
+		})
+	}
+
+	if len(dividends) == 0 {
+		return nil
+	}
+
+	// a later report restates the previous years, an older one must not overwrite it. The dividends a quarterly
+	// report has of its own year so far are replaced by the business report of the year.
+	return p.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "corp_code"}, {Name: "year"}, {Name: "stock_kind"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"dividend_per_share", "dividend_yield", "payout_ratio", "eps", "total_cash_dividend",
+			"settlement_date", "source_year", "receipt_number", "updated_at",
+		}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "dividends.source_year <= excluded.source_year"},
+		}},
+	}).Create(&dividends).Error
+}
+
+// enqueueFetchDividends enqueues a task per company with a stock code, the only ones DART has dividends of
+func (p *TaskProcessor) enqueueFetchDividends(ctx context.Context, bsnsYear string, reportCode dart.ReportType) error {
+	companies, err := gorm.G[models.Company](p.DB).Where("stock_code <> ''").Find(ctx)
+	if err != nil {
+		return err
+	}
+
+	enqueued := 0
+	for _, company := range companies {
+		task, err := NewFetchDividendsTask(&company.CorpCode, &bsnsYear, &reportCode)
+		if err != nil {
+			return err
+		}
+
+		_, err = p.enqueuer.EnqueueContext(ctx, task)
+		if errors.Is(err, asynq.ErrDuplicateTask) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to enqueue fetch dividends task: %w", err)
+		}
+		enqueued++
+	}
+
+	log.Printf("enqueued %d fetch dividends tasks for %s (%s)", enqueued, bsnsYear, reportCode)
+	return nil
+}
@@ -0,0 +1,163 @@
+package tasks_test
+
+import (
+	"context"
+	"kosis/internal/config"
+	"kosis/internal/db"
+	"kosis/internal/models"
+	"kosis/internal/pkg/dart"
+	"kosis/internal/tasks"
+	"kosis/internal/testhelpers"
+
+	"github.com/hibiken/asynq"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("HandleFetchDividendsTask", func() {
+	var dbConn *gorm.DB
+	var p *tasks.TaskProcessor
+	var enqueuer *testhelpers.RecordingEnqueuer
+	var dividends = `{
+		"status": "000",
+		"message": "정상",
+		"list": [
+			{"rcept_no": "20250311001085", "corp_cls": "Y", "corp_code": "00126380", "corp_name": "삼성전자", "se": "주당액면가액(원)", "thstrm": "100", "frmtrm": "100", "lwfr": "100", "stlm_dt": "2024-12-31"},
+			{"rcept_no": "20250311001085", "corp_cls": "Y", "corp_code": "00126380", "corp_name": "삼성전자", "se": "(연결)주당순이익(원)", "thstrm": "4,950", "frmtrm": "2,131", "lwfr": "8,057", "stlm_dt": "2024-12-31"},
+			{"rcept_no": "20250311001085", "corp_cls": "Y", "corp_code": "00126380", "corp_name": "삼성전자", "se": "현금배당금총액(백만원)", "thstrm": "9,810,767", "frmtrm": "9,809,438", "lwfr": "9,809,438", "stlm_dt": "2024-12-31"},
+			{"rcept_no": "20250311001085", "corp_cls": "Y", "corp_code": "00126380", "corp_name": "삼성전자", "se": "(연결)현금배당성향(%)", "thstrm": "29.20", "frmtrm": "67.80", "lwfr": "17.90", "stlm_dt": "2024-12-31"},
+			{"rcept_no": "20250311001085", "corp_cls": "Y", "corp_code": "00126380", "corp_name": "삼성전자", "se": "현금배당수익률(%)", "stock_knd": "보통주", "thstrm": "2.70", "frmtrm": "1.90", "lwfr": "2.50", "stlm_dt": "2024-12-31"},
+			{"rcept_no": "20250311001085", "corp_cls": "Y", "corp_code": "00126380", "corp_name": "삼성전자", "se": "현금배당수익률(%)", "stock_knd": "우선주", "thstrm": "3.40", "frmtrm": "2.40", "lwfr": "2.80", "stlm_dt": "2024-12-31"},
+			{"rcept_no": "20250311001085", "corp_cls": "Y", "corp_code": "00126380", "corp_name": "삼성전자", "se": "주당 현금배당금(원)", "stock_knd": "보통주", "thstrm": "1,446", "frmtrm": "1,444", "lwfr": "1,444", "stlm_dt": "2024-12-31"},
+			{"rcept_no": "20250311001085", "corp_cls": "Y", "corp_code": "00126380", "corp_name": "삼성전자", "se": "주당 현금배당금(원)", "stock_knd": "우선주", "thstrm": "1,447", "frmtrm": "1,445", "lwfr": "1,445", "stlm_dt": "2024-12-31"}
+		]
+	}`
+
+	BeforeEach(func() {
+		cfg, err := config.LoadConfig()
+		Expect(err).NotTo(HaveOccurred())
+
+		dbConn, err = db.InitDB(cfg.DatabaseURL)
+		Expect(err).NotTo(HaveOccurred())
+
+		testhelpers.CleanupDB(dbConn)
+
+		enqueuer = &testhelpers.RecordingEnqueuer{}
+		p, err = tasks.NewTaskProcessor(dbConn, cfg, enqueuer)
+		Expect(err).NotTo(HaveOccurred())
+
+		testhelpers.Activate()
+		p.GetDartClient().UseDefaultClient()
+	})
+
+	AfterEach(func() {
+		testhelpers.Deactivate()
+	})
+
+	It("stores dividends of the business year and the two years before", func() {
+		testhelpers.New("https://opendart.fss.or.kr").
+			Get("/api/alotMatter.json?corp_code=00126380&bsns_year=2024&reprt_code=11011").Reply(200).
+			BodyString(dividends).
+			Header("Content-Type", "application/json")
+
+		corpCode, year := "00126380", "2024"
+		task, err := tasks.NewFetchDividendsTask(&corpCode, &year, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		ctx := context.Background()
+		Expect(p.HandleFetchDividendsTask(ctx, task)).To(Succeed())
+		Expect(testhelpers.IsDone()).To(BeTrue())
+
+		rows, err := gorm.G[models.Dividend](dbConn).Order("year DESC, stock_kind").Find(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rows).To(HaveLen(6))
+		Expect(rows[0].Year).To(Equal(2024))
+		Expect(rows[0].StockKind).To(Equal("보통주"))
+		Expect(*rows[0].DividendPerShare).To(Equal(int64(1446)))
+		Expect(*rows[0].PayoutRatio).To(Equal(29.2))
+		Expect(rows[0].SourceYear).To(Equal(2024))
+	})
+
+	It("does not overwrite a year restated by a later report", func() {
+		ctx := context.Background()
+		dps := int64(2000)
+		Expect(gorm.G[models.Dividend](dbConn).Create(ctx, &models.Dividend{
+			CorpCode:         "00126380",
+			Year:             2023,
+			StockKind:        "보통주",
+			DividendPerShare: &dps,
+			SourceYear:       2025,
+			ReceiptNumber:    "20260310000001",
+		})).To(Succeed())
+
+		testhelpers.New("https://opendart.fss.or.kr").
+			Get("/api/alotMatter.json").Reply(200).
+			BodyString(dividends).
+			Header("Content-Type", "application/json")
+
+		corpCode, year := "00126380", "2024"
+		task, err := tasks.NewFetchDividendsTask(&corpCode, &year, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.HandleFetchDividendsTask(ctx, task)).To(Succeed())
+
+		row, err := gorm.G[models.Dividend](dbConn).Where("year = ? AND stock_kind = ?", 2023, "보통주").First(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*row.DividendPerShare).To(Equal(int64(2000)))
+		Expect(row.SourceYear).To(Equal(2025))
+	})
+
+	It("ignores companies without dividends", func() {
+		testhelpers.New("https://opendart.fss.or.kr").
+			Get("/api/alotMatter.json").Reply(200).
+			BodyString(`{"status": "013", "message": "조회된 데이타가 없습니다."}`).
+			Header("Content-Type", "application/json")
+
+		corpCode, year := "00126380", "2024"
+		task, err := tasks.NewFetchDividendsTask(&corpCode, &year, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.HandleFetchDividendsTask(context.Background(), task)).To(Succeed())
+	})
+
+	It("enqueues one task per listed company", func() {
+		ctx := context.Background()
+		Expect(gorm.G[models.Company](dbConn).Create(ctx, &models.Company{CorpCode: "00126380", CorpName: "삼성전자", StockCode: "005930", Category: "Y"})).To(Succeed())
+		// the baseline classified every company without a telling name as listed
+		Expect(gorm.G[models.Company](dbConn).Create(ctx, &models.Company{CorpCode: "00000001", CorpName: "어떤 주식회사", Category: "Y"})).To(Succeed())
+		Expect(gorm.G[models.Company](dbConn).Create(ctx, &models.Company{CorpCode: "00000002", CorpName: "어떤 유한회사", Category: "E"})).To(Succeed())
+
+		year := "2024"
+		task, err := tasks.NewFetchDividendsTask(nil, &year, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.HandleFetchDividendsTask(ctx, task)).To(Succeed())
+
+		Expect(enqueuer.Tasks).To(HaveLen(1))
+		Expect(enqueuer.Tasks[0].Type()).To(Equal(tasks.TypeTaskFetchDividends))
+		Expect(enqueuer.Tasks[0].Payload()).To(MatchJSON(`{"corp_code": "00126380", "bsns_year": "2024", "reprt_code": "11011"}`))
+	})
+
+	It("stores the dividends of a quarterly report", func() {
+		testhelpers.New("https://opendart.fss.or.kr").
+			Get("/api/alotMatter.json?corp_code=00126380&bsns_year=2025&reprt_code=11014").Reply(200).
+			BodyString(dividends).
+			Header("Content-Type", "application/json")
+
+		corpCode, year, reportCode := "00126380", "2025", dart.THIRD_QUARTER
+		task, err := tasks.NewFetchDividendsTask(&corpCode, &year, &reportCode)
+		Expect(err).NotTo(HaveOccurred())
+
+		ctx := context.Background()
+		Expect(p.HandleFetchDividendsTask(ctx, task)).To(Succeed())
+		Expect(testhelpers.IsDone()).To(BeTrue())
+
+		rows, err := gorm.G[models.Dividend](dbConn).Where("year = ?", 2025).Find(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rows).To(HaveLen(2))
+		Expect(rows[0].SourceYear).To(Equal(2025))
+	})
+
+	It("rejects an invalid business year", func() {
+		err := p.HandleFetchDividendsTask(context.Background(), asynq.NewTask(tasks.TypeTaskFetchDividends, []byte(`{"bsns_year": "last"}`)))
+		Expect(err).To(MatchError(asynq.SkipRetry))
+	})
+})
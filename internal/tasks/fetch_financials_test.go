@@ -0,0 +1,214 @@
+package tasks_test
+
+import (
+	"context"
+	"kosis/internal/config"
+	"kosis/internal/db"
+	"kosis/internal/models"
+	"kosis/internal/pkg/dart"
+	"kosis/internal/tasks"
+	"kosis/internal/testhelpers"
+	"time"
+
+	"github.com/hibiken/asynq"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("HandleFetchFinancialsTask", func() {
+	var dbConn *gorm.DB
+	var p *tasks.TaskProcessor
+	var majorAccounts = `{
+		"status": "000",
+		"message": "정상",
+		"list": [
+			{
+				"rcept_no": "20251114001374",
+				"bsns_year": "2025",
+				"corp_code": "00356361",
+				"stock_code": "051910",
+				"reprt_code": "11014",
+				"account_nm": "자산총계",
+				"fs_div": "CFS",
+				"fs_nm": "연결재무제표",
+				"sj_div": "BS",
+				"sj_nm": "재무상태표",
+				"thstrm_nm": "제 25 기 3분기말",
+				"thstrm_dt": "2025.09.30 현재",
+				"thstrm_amount": "82,734,127,000,000",
+				"currency": "KRW"
+			},
+			{
+				"rcept_no": "20251114001374",
+				"bsns_year": "2025",
+				"corp_code": "00356361",
+				"stock_code": "051910",
+				"reprt_code": "11014",
+				"account_nm": "매출액",
+				"fs_div": "OFS",
+				"fs_nm": "재무제표",
+				"sj_div": "IS",
+				"sj_nm": "손익계산서",
+				"thstrm_nm": "제 25 기 3분기",
+				"thstrm_dt": "2025.07.01 ~ 2025.09.30",
+				"thstrm_amount": "4,331,000,000,000",
+				"thstrm_add_amount": "13,126,000,000,000",
+				"currency": "KRW"
+			},
+			{
+				"rcept_no": "20251114001374",
+				"bsns_year": "2025",
+				"corp_code": "00356361",
+				"stock_code": "051910",
+				"reprt_code": "11014",
+				"account_nm": "기타포괄손익",
+				"fs_div": "OFS",
+				"sj_div": "IS",
+				"thstrm_amount": "1,000",
+				"currency": "KRW"
+			}
+		]
+	}`
+
+	BeforeEach(func() {
+		cfg, err := config.LoadConfig()
+		Expect(err).NotTo(HaveOccurred())
+
+		dbConn, err = db.InitDB(cfg.DatabaseURL)
+		Expect(err).NotTo(HaveOccurred())
+
+		testhelpers.CleanupDB(dbConn)
+
+		p, err = tasks.NewTaskProcessor(dbConn, cfg, &testhelpers.RecordingEnqueuer{})
+		Expect(err).NotTo(HaveOccurred())
+
+		testhelpers.Activate()
+		p.GetDartClient().UseDefaultClient()
+	})
+
+	AfterEach(func() {
+		testhelpers.Deactivate()
+	})
+
+	It("stores known accounts as financial facts", func() {
+		testhelpers.New("https://opendart.fss.or.kr").
+			Get("/api/fnlttSinglAcnt.json?corp_code=00356361&bsns_year=2025&reprt_code=11014").Reply(200).
+			BodyString(majorAccounts).
+			Header("Content-Type", "application/json")
+
+		task, err := tasks.NewFetchFinancialsTask("00356361", "2025", dart.THIRD_QUARTER)
+		Expect(err).NotTo(HaveOccurred())
+
+		ctx := context.Background()
+		Expect(p.HandleFetchFinancialsTask(ctx, task)).To(Succeed())
+		Expect(testhelpers.IsDone()).To(BeTrue())
+
+		facts, err := gorm.G[models.FinancialFact](dbConn).Order("account").Find(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(facts).To(HaveLen(2))
+
+		Expect(facts[0].Account).To(Equal("sales"))
+		Expect(facts[0].Statement).To(Equal(models.StatementIncomeStatement))
+		Expect(facts[0].Consolidated).To(BeFalse())
+		Expect(facts[0].PeriodType).To(Equal(models.PeriodTypeQ3))
+		Expect(facts[0].PeriodEnd.Format("2006-01-02")).To(Equal("2025-09-30"))
+		Expect(facts[0].Value).To(Equal(int64(13126000000000)))
+		Expect(facts[0].Source).To(Equal(models.FinancialFactSourceDartAPI))
+
+		Expect(facts[1].Account).To(Equal("total_assets"))
+		Expect(facts[1].Statement).To(Equal(models.StatementBalanceSheet))
+		Expect(facts[1].Consolidated).To(BeTrue())
+		Expect(facts[1].Value).To(Equal(int64(82734127000000)))
+		Expect(facts[1].ReceiptNumber).To(Equal("20251114001374"))
+	})
+
+	It("updates facts that were already stored", func() {
+		ctx := context.Background()
+		Expect(gorm.G[models.FinancialFact](dbConn).Create(ctx, &models.FinancialFact{
+			CorpCode:      "00356361",
+			PeriodEnd:     time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC),
+			PeriodType:    models.PeriodTypeQ3,
+			Statement:     models.StatementBalanceSheet,
+			Account:       "total_assets",
+			Consolidated:  true,
+			Value:         1,
+			Unit:          "KRW",
+			ReceiptNumber: "20251114000001",
+			Source:        models.FinancialFactSourceDartAPI,
+		})).To(Succeed())
+
+		testhelpers.New("https://opendart.fss.or.kr").
+			Get("/api/fnlttSinglAcnt.json").Reply(200).
+			BodyString(majorAccounts).
+			Header("Content-Type", "application/json")
+
+		task, err := tasks.NewFetchFinancialsTask("00356361", "2025", dart.THIRD_QUARTER)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.HandleFetchFinancialsTask(ctx, task)).To(Succeed())
+
+		fact, err := gorm.G[models.FinancialFact](dbConn).Where("account = ?", "total_assets").First(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fact.Value).To(Equal(int64(82734127000000)))
+		Expect(fact.ReceiptNumber).To(Equal("20251114001374"))
+	})
+
+	It("stores an account DART names twice once", func() {
+		testhelpers.New("https://opendart.fss.or.kr").
+			Get("/api/fnlttSinglAcnt.json").Reply(200).
+			BodyString(`{
+				"status": "000",
+				"message": "정상",
+				"list": [
+					{"rcept_no": "20251114001374", "bsns_year": "2025", "corp_code": "00356361", "reprt_code": "11014", "account_nm": "매출액", "fs_div": "CFS", "sj_div": "IS", "thstrm_dt": "2025.07.01 ~ 2025.09.30", "thstrm_add_amount": "13,126,000,000,000", "currency": "KRW"},
+					{"rcept_no": "20251114001374", "bsns_year": "2025", "corp_code": "00356361", "reprt_code": "11014", "account_nm": "수익(매출액)", "fs_div": "CFS", "sj_div": "CIS", "thstrm_dt": "2025.07.01 ~ 2025.09.30", "thstrm_add_amount": "13,126,000,000,000", "currency": "KRW"}
+				]
+			}`).
+			Header("Content-Type", "application/json")
+
+		task, err := tasks.NewFetchFinancialsTask("00356361", "2025", dart.THIRD_QUARTER)
+		Expect(err).NotTo(HaveOccurred())
+
+		ctx := context.Background()
+		Expect(p.HandleFetchFinancialsTask(ctx, task)).To(Succeed())
+
+		facts, err := gorm.G[models.FinancialFact](dbConn).Find(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(facts).To(HaveLen(1))
+		Expect(facts[0].Account).To(Equal("sales"))
+		Expect(facts[0].Value).To(Equal(int64(13126000000000)))
+	})
+
+	It("reads account names as the tables of a report write them", func() {
+		testhelpers.New("https://opendart.fss.or.kr").
+			Get("/api/fnlttSinglAcnt.json").Reply(200).
+			BodyString(`{
+				"status": "000",
+				"message": "정상",
+				"list": [
+					{"rcept_no": "20251114001374", "bsns_year": "2025", "corp_code": "00356361", "reprt_code": "11014", "account_nm": "영업손익", "fs_div": "CFS", "sj_div": "IS", "thstrm_dt": "2025.07.01 ~ 2025.09.30", "thstrm_add_amount": "-120,000,000,000", "currency": "KRW"},
+					{"rcept_no": "20251114001374", "bsns_year": "2025", "corp_code": "00356361", "reprt_code": "11014", "account_nm": "분기순이익(손실)", "fs_div": "CFS", "sj_div": "IS", "thstrm_dt": "2025.07.01 ~ 2025.09.30", "thstrm_add_amount": "-80,000,000,000", "currency": "KRW"}
+				]
+			}`).
+			Header("Content-Type", "application/json")
+
+		task, err := tasks.NewFetchFinancialsTask("00356361", "2025", dart.THIRD_QUARTER)
+		Expect(err).NotTo(HaveOccurred())
+
+		ctx := context.Background()
+		Expect(p.HandleFetchFinancialsTask(ctx, task)).To(Succeed())
+
+		facts, err := gorm.G[models.FinancialFact](dbConn).Order("account").Find(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(facts).To(HaveLen(2))
+		Expect(facts[0].Account).To(Equal("net_income"))
+		Expect(facts[0].Value).To(Equal(int64(-80000000000)))
+		Expect(facts[1].Account).To(Equal("operating_income"))
+		Expect(facts[1].Value).To(Equal(int64(-120000000000)))
+	})
+
+	It("rejects an unknown report code", func() {
+		err := p.HandleFetchFinancialsTask(context.Background(), asynq.NewTask(tasks.TypeTaskFetchFinancials, []byte(`{"corp_code": "00356361", "bsns_year": "2025", "reprt_code": "99999"}`)))
+		Expect(err).To(MatchError(asynq.SkipRetry))
+	})
+})
@@ -7,10 +7,14 @@ import (
 	"kosis/internal/config"
 	"kosis/internal/db"
 	"kosis/internal/models"
+	"kosis/internal/pkg/dart"
 	"kosis/internal/pkg/openai"
+	"kosis/internal/pkg/xbrl"
 	"kosis/internal/tasks"
 	"kosis/internal/testhelpers"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/hibiken/asynq"
 	. "github.com/onsi/ginkgo/v2"
@@ -21,6 +25,7 @@ import (
 var _ = Describe("HandleFetchReportsTask", func() {
 	var dbConn *gorm.DB
 	var p *tasks.TaskProcessor
+	var enqueuer *testhelpers.RecordingEnqueuer
 	var listWithOneReport = `{
 		"status": "000",
 		"message": "정상",
@@ -145,7 +150,9 @@ var _ = Describe("HandleFetchReportsTask", func() {
 
 		testhelpers.CleanupDB(dbConn)
 
-		p = tasks.NewTaskProcessor(dbConn, cfg)
+		enqueuer = &testhelpers.RecordingEnqueuer{}
+		p, err = tasks.NewTaskProcessor(dbConn, cfg, enqueuer)
+		Expect(err).NotTo(HaveOccurred())
 
 		testhelpers.Activate()
 		p.GetDartClient().UseDefaultClient()
@@ -155,93 +162,59 @@ var _ = Describe("HandleFetchReportsTask", func() {
 		testhelpers.Deactivate()
 	})
 
-	It("stores raw reports", func() {
+	It("enqueues one analyze report task per filing", func() {
 		testhelpers.New("https://opendart.fss.or.kr").
 			Get("/api/list.json").Reply(200).
 			BodyString(listWithOneReport).
 			Header("Content-Type", "application/json")
 
-		zipDocument, err := testhelpers.CreateMockZipArchive("document.xml", []byte(testDocument))
-		Expect(err).NotTo(HaveOccurred())
-
-		testhelpers.New("https://opendart.fss.or.kr").Get("/api/document.xml").Reply(200).Body(zipDocument).Header("Content-Type", "application/zip").Header("Content-Disposition", `attachment; filename="document.zip"`)
-
-		rawData := `{ \"company_name\": \"LG화학\", \"date\": \"2025-09-30\", \"type\": \"report\", \"summary\": \"LG화학의 2025년 3분기 보고서\" }`
-		testhelpers.New("https://api.openai.com").
-			Post("/v1/responses").Reply(200).
-			BodyString(fmt.Sprintf(openaiResFmt, rawData)).
-			Header("Content-Type", "application/json")
-
 		ctx := context.Background()
-		err = p.HandleFetchReportsTask(ctx, asynq.NewTask(tasks.TypeTaskFetchReports, []byte("{}")))
+		err := p.HandleFetchReportsTask(ctx, asynq.NewTask(tasks.TypeTaskFetchReports, []byte("{}")))
 		Expect(err).NotTo(HaveOccurred())
+		Expect(testhelpers.IsDone()).To(BeTrue())
 
-		result, err := gorm.G[models.RawReport](dbConn).Where("corp_code = ?", "00356361").First(ctx)
-		Expect(err).NotTo(HaveOccurred())
-		Expect(result.ReceiptNumber).To(Equal("20251114001374"))
-		Expect(result.CorpCode).To(Equal("00356361"))
-		Expect(result.ReportName).To(Equal("분기보고서 (2025.09)"))
-		Expect(strings.TrimSpace(string(result.BlobData))).To(Equal(strings.TrimSpace(testDocument)))
-		Expect(result.JSONData).To(MatchJSON(`{"company_name": "ACME Corp", "report_title": "Form 10-K", "company_cik": "00001234", "tables": [[["Metric", "Amount"], ["Revenue", "1000"], ["Profit", "500"]], [["Line Item", "Value"], ["Total Assets", "2000"]]], "key_paragraphs": ["Primary discussion.", "Secondary paragraph"]}`))
+		Expect(enqueuer.Tasks).To(HaveLen(1))
+		Expect(enqueuer.Tasks[0].Type()).To(Equal(tasks.TypeTaskAnalyzeReport))
+		Expect(enqueuer.Tasks[0].Payload()).To(MatchJSON(`{"rcept_no": "20251114001374", "corp_code": "00356361", "corp_name": "LG화학", "report_nm": "분기보고서 (2025.09)", "rcept_dt": "20251114"}`))
 
-		analysis, err := gorm.G[models.Analysis](dbConn).Where("raw_report_id = ?", result.ID).First(ctx)
+		count, err := gorm.G[models.RawReport](dbConn).Count(ctx, "id")
 		Expect(err).NotTo(HaveOccurred())
-
-		Expect(analysis.UsedTokens).To(Equal(int64(123)))
-		var analysisData map[string]interface{}
-		Expect(json.Unmarshal(analysis.Analysis, &analysisData)).NotTo(HaveOccurred())
-		Expect(analysisData["company_name"]).To(Equal("LG화학"))
-		Expect(analysisData["date"]).To(Equal("2025-09-30"))
-		Expect(analysisData["type"]).To(Equal("report"))
-		Expect(analysisData["summary"]).To(Equal("LG화학의 2025년 3분기 보고서"))
+		Expect(count).To(BeZero())
 	})
 
-	It("sets company name if not set", func() {
+	It("enqueues a filing again when asynq archived its task", func() {
 		testhelpers.New("https://opendart.fss.or.kr").
 			Get("/api/list.json").Reply(200).
 			BodyString(listWithOneReport).
 			Header("Content-Type", "application/json")
 
-		zipDocument, err := testhelpers.CreateMockZipArchive("document.xml", []byte(testDocument))
-		Expect(err).NotTo(HaveOccurred())
-
-		testhelpers.New("https://opendart.fss.or.kr").Get("/api/document.xml").Reply(200).Body(zipDocument).Header("Content-Type", "application/zip").Header("Content-Disposition", `attachment; filename="document.zip"`)
-
-		company := models.Company{
-			CorpCode: "00356361",
-			CorpName: "LG화학",
+		enqueuer.Existing = map[string]*asynq.TaskInfo{
+			"20251114001374": {ID: "20251114001374", Queue: "default", Type: tasks.TypeTaskAnalyzeReport, State: asynq.TaskStateArchived},
 		}
 
 		ctx := context.Background()
-		dbResult := gorm.WithResult()
-		err = gorm.G[models.Company](dbConn, dbResult).Create(ctx, &company)
-		Expect(err).NotTo(HaveOccurred())
-		Expect(dbResult.RowsAffected).To(Equal(int64(1)))
+		Expect(p.HandleFetchReportsTask(ctx, asynq.NewTask(tasks.TypeTaskFetchReports, []byte("{}")))).To(Succeed())
 
-		rawData := `{ \"company_name\": \"\", \"date\": \"2025-09-30\", \"type\": \"report\", \"summary\": \"LG화학의 2025년 3분기 보고서\" }`
-		testhelpers.New("https://api.openai.com").
-			Post("/v1/responses").Reply(200).
-			BodyString(fmt.Sprintf(openaiResFmt, rawData)).
+		Expect(enqueuer.Existing).To(BeEmpty())
+		Expect(enqueuer.Tasks).To(HaveLen(1))
+		Expect(enqueuer.Tasks[0].Type()).To(Equal(tasks.TypeTaskAnalyzeReport))
+	})
+
+	It("skips a filing whose task is still queued", func() {
+		testhelpers.New("https://opendart.fss.or.kr").
+			Get("/api/list.json").Reply(200).
+			BodyString(listWithOneReport).
 			Header("Content-Type", "application/json")
 
-		err = p.HandleFetchReportsTask(ctx, asynq.NewTask(tasks.TypeTaskFetchReports, []byte("{}")))
-		Expect(err).NotTo(HaveOccurred())
+		enqueuer.Existing = map[string]*asynq.TaskInfo{
+			"20251114001374": {ID: "20251114001374", Queue: "default", Type: tasks.TypeTaskAnalyzeReport, State: asynq.TaskStateRetry},
+		}
 
-		result, err := gorm.G[models.RawReport](dbConn).Where("corp_code = ?", "00356361").First(ctx)
-		Expect(err).NotTo(HaveOccurred())
-		Expect(result.ReceiptNumber).To(Equal("20251114001374"))
-		Expect(result.CorpCode).To(Equal("00356361"))
-		Expect(strings.TrimSpace(string(result.BlobData))).To(Equal(strings.TrimSpace(testDocument)))
-		Expect(result.JSONData).To(MatchJSON(`{"company_name": "ACME Corp", "report_title": "Form 10-K", "company_cik": "00001234", "tables": [[["Metric", "Amount"], ["Revenue", "1000"], ["Profit", "500"]], [["Line Item", "Value"], ["Total Assets", "2000"]]], "key_paragraphs": ["Primary discussion.", "Secondary paragraph"]}`))
+		ctx := context.Background()
+		Expect(p.HandleFetchReportsTask(ctx, asynq.NewTask(tasks.TypeTaskFetchReports, []byte("{}")))).To(Succeed())
 
-		analysis, err := gorm.G[models.Analysis](dbConn).Where("raw_report_id = ?", result.ID).First(ctx)
-		Expect(err).NotTo(HaveOccurred())
-		var analysisData map[string]interface{}
-		Expect(json.Unmarshal(analysis.Analysis, &analysisData)).NotTo(HaveOccurred())
-		Expect(analysisData["company_name"]).To(Equal("LG화학"))
-		Expect(analysisData["date"]).To(Equal("2025-09-30"))
-		Expect(analysisData["type"]).To(Equal("report"))
-		Expect(analysisData["summary"]).To(Equal("LG화학의 2025년 3분기 보고서"))
+		Expect(enqueuer.Existing).To(HaveLen(1))
+		Expect(enqueuer.Tasks).To(BeEmpty())
 	})
 
 	It("skips if raw report already exists", func() {
@@ -264,6 +237,958 @@ var _ = Describe("HandleFetchReportsTask", func() {
 
 		err = p.HandleFetchReportsTask(ctx, asynq.NewTask(tasks.TypeTaskFetchReports, []byte("{}")))
 		Expect(err).NotTo(HaveOccurred())
+		Expect(enqueuer.Tasks).To(BeEmpty())
+	})
+
+	It("backfills reports for a single company and date range", func() {
+		testhelpers.New("https://opendart.fss.or.kr").
+			Get("/api/list.json?corp_code=00356361&bgn_de=20250101&end_de=20250331").Reply(200).
+			BodyString(`{"status": "000", "message": "정상", "page_no": 1, "page_count": 0, "total_count": 0, "total_page": 1, "list": []}`).
+			Header("Content-Type", "application/json")
+
+		testhelpers.New("https://opendart.fss.or.kr").
+			Get("/api/list.json?corp_code=00356361&bgn_de=20250401&end_de=20250630").Reply(200).
+			BodyString(listWithOneReport).
+			Header("Content-Type", "application/json")
+
+		rawReport := models.RawReport{
+			ReceiptNumber: "20251114001374",
+			CorpCode:      "00356361",
+			BlobData:      []byte("<DOCUMENT></DOCUMENT>"),
+			BlobSize:      21,
+			JSONData:      []byte(`{}`),
+		}
+
+		ctx := context.Background()
+		Expect(gorm.G[models.RawReport](dbConn).Create(ctx, &rawReport)).To(Succeed())
+
+		corpCode := "00356361"
+		task, err := tasks.NewBackfillReportsTask(&corpCode, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC), nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = p.HandleFetchReportsTask(ctx, task)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(testhelpers.IsDone()).To(BeTrue())
+	})
+
+	It("rejects an invalid date range", func() {
+		ctx := context.Background()
+		err := p.HandleFetchReportsTask(ctx, asynq.NewTask(tasks.TypeTaskFetchReports, []byte(`{"start_date": "2025-13-01"}`)))
+		Expect(err).To(MatchError(asynq.SkipRetry))
+	})
+
+	Describe("HandleAnalyzeReportTask", func() {
+		var analyzeTask *asynq.Task
+
+		BeforeEach(func() {
+			var err error
+			analyzeTask, err = tasks.NewAnalyzeReportTask(dart.List{
+				RceptNo:  "20251114001374",
+				CorpCode: "00356361",
+				CorpName: "LG화학",
+				ReportNm: "분기보고서 (2025.09)",
+				RceptDt:  "20251114",
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("stores raw reports", func() {
+			zipDocument, err := testhelpers.CreateMockZipArchive("document.xml", []byte(testDocument))
+			Expect(err).NotTo(HaveOccurred())
+
+			testhelpers.New("https://opendart.fss.or.kr").Get("/api/document.xml").Reply(200).Body(zipDocument).Header("Content-Type", "application/zip").Header("Content-Disposition", `attachment; filename="document.zip"`)
+
+			rawData := `{ \"company_name\": \"LG화학\", \"date\": \"2025-09-30\", \"type\": \"report\", \"summary\": \"LG화학의 2025년 3분기 보고서\" }`
+			testhelpers.New("https://api.openai.com").
+				Post("/v1/responses").Reply(200).
+				BodyString(fmt.Sprintf(openaiResFmt, rawData)).
+				Header("Content-Type", "application/json")
+
+			ctx := context.Background()
+			err = p.HandleAnalyzeReportTask(ctx, analyzeTask)
+			Expect(err).NotTo(HaveOccurred())
+
+			result, err := gorm.G[models.RawReport](dbConn).Where("corp_code = ?", "00356361").First(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.ReceiptNumber).To(Equal("20251114001374"))
+			Expect(result.CorpCode).To(Equal("00356361"))
+			Expect(result.ReportName).To(Equal("분기보고서 (2025.09)"))
+			Expect(strings.TrimSpace(string(result.BlobData))).To(Equal(strings.TrimSpace(testDocument)))
+			Expect(result.JSONData).To(MatchJSON(`{"company_name": "ACME Corp", "report_title": "Form 10-K", "company_cik": "00001234", "tables": [[["Metric", "Amount"], ["Revenue", "1000"], ["Profit", "500"]], [["Line Item", "Value"], ["Total Assets", "2000"]]], "key_paragraphs": ["Primary discussion.", "Secondary paragraph"]}`))
+
+			analysis, err := gorm.G[models.Analysis](dbConn).Where("raw_report_id = ?", result.ID).First(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(analysis.UsedTokens).To(Equal(int64(123)))
+			var analysisData map[string]interface{}
+			Expect(json.Unmarshal(analysis.Analysis, &analysisData)).NotTo(HaveOccurred())
+			Expect(analysisData["company_name"]).To(Equal("LG화학"))
+			Expect(analysisData["date"]).To(Equal("2025-09-30"))
+			Expect(analysisData["type"]).To(Equal("report"))
+			Expect(analysisData["summary"]).To(Equal("LG화학의 2025년 3분기 보고서"))
+
+			Expect(enqueuer.Tasks).To(HaveLen(1))
+			Expect(enqueuer.Tasks[0].Type()).To(Equal(tasks.TypeTaskFetchFinancials))
+			Expect(enqueuer.Tasks[0].Payload()).To(MatchJSON(`{"corp_code": "00356361", "bsns_year": "2025", "reprt_code": "11014"}`))
+		})
+
+		It("keeps the main document and its attachments as separate files", func() {
+			auditReport := `<DOCUMENT><DOCUMENT-NAME>감사보고서</DOCUMENT-NAME><COMPANY-NAME AREGCIK="00999999">삼일회계법인</COMPANY-NAME></DOCUMENT>`
+			zipDocument, err := testhelpers.CreateMockZipArchiveFiles(
+				testhelpers.MockZipFile{Name: "20251114001374_00760.xml", Body: auditReport},
+				testhelpers.MockZipFile{Name: "20251114001374.xml", Body: testDocument},
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			testhelpers.New("https://opendart.fss.or.kr").Get("/api/document.xml").Reply(200).Body(zipDocument).Header("Content-Type", "application/zip")
+			rawData := `{ \"company_name\": \"LG화학\", \"type\": \"report\" }`
+			testhelpers.New("https://api.openai.com").
+				Post("/v1/responses").Reply(200).
+				BodyString(fmt.Sprintf(openaiResFmt, rawData)).
+				Header("Content-Type", "application/json")
+
+			ctx := context.Background()
+			Expect(p.HandleAnalyzeReportTask(ctx, analyzeTask)).To(Succeed())
+
+			result, err := gorm.G[models.RawReport](dbConn).Where("receipt_number = ?", "20251114001374").First(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(result.BlobData)).To(Equal(testDocument))
+
+			var doc xbrl.UsefulReport
+			Expect(json.Unmarshal(result.JSONData, &doc)).To(Succeed())
+			Expect(doc.ReportTitle).To(Equal("Form 10-K"))
+			Expect(doc.CompanyName).To(Equal("ACME Corp"))
+
+			files, err := gorm.G[models.RawReportFile](dbConn).Where("receipt_number = ?", "20251114001374").Order("filename").Find(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(HaveLen(2))
+
+			Expect(files[0].Filename).To(Equal("20251114001374.xml"))
+			Expect(files[0].Main).To(BeTrue())
+			Expect(files[0].BlobData).To(BeEmpty())
+			Expect(files[0].BlobSize).To(Equal(len(testDocument)))
+			Expect(files[0].JSONData).To(BeEmpty())
+
+			Expect(files[1].Filename).To(Equal("20251114001374_00760.xml"))
+			Expect(files[1].Main).To(BeFalse())
+			Expect(string(files[1].BlobData)).To(Equal(auditReport))
+			Expect(json.Unmarshal(files[1].JSONData, &doc)).To(Succeed())
+			Expect(doc.ReportTitle).To(Equal("감사보고서"))
+			Expect(doc.CompanyName).To(Equal("삼일회계법인"))
+		})
+
+		It("adds the facts of XBRL instances to the main document", func() {
+			instance := `<?xml version="1.0" encoding="UTF-8"?>
+<xbrli:xbrl xmlns:xbrli="http://www.xbrl.org/2003/instance" xmlns:ifrs-full="https://xbrl.ifrs.org/taxonomy/2023-03-23/ifrs-full" xmlns:iso4217="http://www.xbrl.org/2003/iso4217">
+  <xbrli:context id="CFY2025eFY"><xbrli:entity><xbrli:identifier scheme="http://dart.fss.or.kr">00356361</xbrli:identifier></xbrli:entity><xbrli:period><xbrli:instant>2025-09-30</xbrli:instant></xbrli:period></xbrli:context>
+  <xbrli:unit id="KRW"><xbrli:measure>iso4217:KRW</xbrli:measure></xbrli:unit>
+  <ifrs-full:Assets contextRef="CFY2025eFY" unitRef="KRW" decimals="-6">80000000000000</ifrs-full:Assets>
+</xbrli:xbrl>`
+			zipDocument, err := testhelpers.CreateMockZipArchiveFiles(
+				testhelpers.MockZipFile{Name: "20251114001374.xml", Body: testDocument},
+				testhelpers.MockZipFile{Name: "20251114001374.xbrl", Body: instance},
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			testhelpers.New("https://opendart.fss.or.kr").Get("/api/document.xml").Reply(200).Body(zipDocument).Header("Content-Type", "application/zip")
+			rawData := `{ \"company_name\": \"LG화학\", \"type\": \"report\" }`
+			testhelpers.New("https://api.openai.com").
+				Post("/v1/responses").Reply(200).
+				BodyString(fmt.Sprintf(openaiResFmt, rawData)).
+				Header("Content-Type", "application/json")
+
+			ctx := context.Background()
+			Expect(p.HandleAnalyzeReportTask(ctx, analyzeTask)).To(Succeed())
+
+			result, err := gorm.G[models.RawReport](dbConn).Where("receipt_number = ?", "20251114001374").First(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			var doc xbrl.UsefulReport
+			Expect(json.Unmarshal(result.JSONData, &doc)).To(Succeed())
+			Expect(doc.ReportTitle).To(Equal("Form 10-K"))
+			Expect(doc.Facts).To(HaveLen(1))
+			Expect(doc.Facts[0].Concept).To(Equal("ifrs-full:Assets"))
+			Expect(*doc.Facts[0].Number).To(Equal(80000000000000.0))
+			Expect(doc.Facts[0].Currency).To(Equal("KRW"))
+			Expect(doc.Facts[0].Instant).To(Equal("2025-09-30"))
+			Expect(doc.Facts[0].Scope).To(Equal(xbrl.ScopeConsolidated))
+		})
+
+		It("fills the statements of a periodic report from its tables", func() {
+			quarterlyReport := `<DOCUMENT>
+  <DOCUMENT-NAME ACODE="11014">분기보고서</DOCUMENT-NAME>
+  <COMPANY-NAME AREGCIK="00356361">LG화학</COMPANY-NAME>
+  <BODY>
+    <SECTION-1>
+      <TITLE ATOC="Y">III. 재무에 관한 사항</TITLE>
+      <SECTION-2>
+        <TITLE ATOC="Y">2. 연결재무제표</TITLE>
+        <P>연결 재무상태표</P>
+        <TABLE><TR><TD>제 25 기 3분기말 2025.09.30 현재</TD></TR></TABLE>
+        <P>(단위 : 백만원)</P>
+        <TABLE>
+          <THEAD><TR><TH></TH><TH>제 25 기 3분기말</TH></TR></THEAD>
+          <TBODY><TR><TD>자산총계</TD><TE>82,734,127</TE></TR></TBODY>
+        </TABLE>
+      </SECTION-2>
+    </SECTION-1>
+  </BODY>
+</DOCUMENT>`
+			zipDocument, err := testhelpers.CreateMockZipArchive("20251114001374.xml", []byte(quarterlyReport))
+			Expect(err).NotTo(HaveOccurred())
+
+			testhelpers.New("https://opendart.fss.or.kr").Get("/api/document.xml").Reply(200).Body(zipDocument).Header("Content-Type", "application/zip")
+			rawData := `{ \"company_name\": \"LG화학\", \"consolidated_financials_million_krw\": { \"balance_sheet\": { \"period_2025_09_30\": { \"total_assets\": 80000000, \"capital\": 391406 } } } }`
+			testhelpers.New("https://api.openai.com").
+				Post("/v1/responses").Reply(200).
+				BodyString(fmt.Sprintf(openaiResFmt, rawData)).
+				Header("Content-Type", "application/json")
+
+			ctx := context.Background()
+			Expect(p.HandleAnalyzeReportTask(ctx, analyzeTask)).To(Succeed())
+
+			result, err := gorm.G[models.RawReport](dbConn).Where("receipt_number = ?", "20251114001374").First(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			analysis, err := gorm.G[models.Analysis](dbConn).Where("raw_report_id = ?", result.ID).First(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			var report openai.Report
+			Expect(json.Unmarshal(analysis.Analysis, &report)).To(Succeed())
+			Expect(report.CompanyName).To(Equal("LG화학"))
+			bs := report.Consolidated.BalanceSheet["period_2025_09_30"]
+			Expect(bs.TotalAssets).To(Equal(int64(82734127)))
+			Expect(bs.Capital).To(Equal(int64(391406)))
+
+			facts, err := gorm.G[models.FinancialFact](dbConn).Where("account = ?", "total_assets").Order("source").Find(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(facts).To(HaveLen(2))
+			Expect(facts[0].Source).To(Equal(models.FinancialFactSourceLLM))
+			Expect(facts[0].Value).To(Equal(int64(80000000000000)))
+			Expect(facts[1].Source).To(Equal(models.FinancialFactSourceReportTable))
+			Expect(facts[1].Value).To(Equal(int64(82734127000000)))
+			Expect(facts[1].PeriodType).To(Equal(models.PeriodTypeQ3))
+			Expect(facts[1].Consolidated).To(BeTrue())
+		})
+
+		It("keeps zero amounts and reads period keys with the fiscal year of the company", func() {
+			ctx := context.Background()
+			Expect(gorm.G[models.Company](dbConn).Create(ctx, &models.Company{CorpCode: "00356361", CorpName: "LG화학", FiscalMonth: "03"})).To(Succeed())
+
+			zipDocument, err := testhelpers.CreateMockZipArchive("document.xml", []byte(testDocument))
+			Expect(err).NotTo(HaveOccurred())
+
+			testhelpers.New("https://opendart.fss.or.kr").Get("/api/document.xml").Reply(200).Body(zipDocument).Header("Content-Type", "application/zip")
+			rawData := `{ \"company_name\": \"LG화학\", \"separate_financials_million_krw\": { \"income_statement\": { \"period_2025_H1\": { \"sales\": 1200, \"operating_income\": 0, \"net_income\": null } } } }`
+			testhelpers.New("https://api.openai.com").
+				Post("/v1/responses").Reply(200).
+				BodyString(fmt.Sprintf(openaiResFmt, rawData)).
+				Header("Content-Type", "application/json")
+
+			Expect(p.HandleAnalyzeReportTask(ctx, analyzeTask)).To(Succeed())
+
+			facts, err := gorm.G[models.FinancialFact](dbConn).Where("source = ?", models.FinancialFactSourceLLM).Order("account").Find(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(facts).To(HaveLen(2))
+			Expect(facts[0].Account).To(Equal("operating_income"))
+			Expect(facts[0].Value).To(BeZero())
+			Expect(facts[1].Account).To(Equal("sales"))
+			Expect(facts[1].Value).To(Equal(int64(1200000000)))
+			for _, fact := range facts {
+				Expect(fact.PeriodEnd).To(Equal(time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC)))
+				Expect(fact.PeriodType).To(Equal(models.PeriodTypeH1))
+				Expect(fact.Consolidated).To(BeFalse())
+			}
+		})
+
+		It("stores the statements of the XBRL instances of a periodic report", func() {
+			quarterlyReport := `<DOCUMENT>
+  <DOCUMENT-NAME ACODE="11014">분기보고서</DOCUMENT-NAME>
+  <COMPANY-NAME AREGCIK="00356361">LG화학</COMPANY-NAME>
+</DOCUMENT>`
+			instance := `<?xml version="1.0" encoding="UTF-8"?>
+<xbrli:xbrl xmlns:xbrli="http://www.xbrl.org/2003/instance" xmlns:xbrldi="http://xbrl.org/2006/xbrldi" xmlns:ifrs-full="https://xbrl.ifrs.org/taxonomy/2023-03-23/ifrs-full" xmlns:iso4217="http://www.xbrl.org/2003/iso4217">
+  <xbrli:context id="I"><xbrli:entity><xbrli:identifier scheme="http://dart.fss.or.kr">00356361</xbrli:identifier></xbrli:entity><xbrli:period><xbrli:instant>2025-09-30</xbrli:instant></xbrli:period></xbrli:context>
+  <xbrli:context id="S"><xbrli:entity><xbrli:identifier scheme="http://dart.fss.or.kr">00356361</xbrli:identifier><xbrli:segment><xbrldi:explicitMember dimension="ifrs-full:ConsolidatedAndSeparateFinancialStatementsAxis">ifrs-full:SeparateMember</xbrldi:explicitMember></xbrli:segment></xbrli:entity><xbrli:period><xbrli:instant>2025-09-30</xbrli:instant></xbrli:period></xbrli:context>
+  <xbrli:context id="D9M"><xbrli:entity><xbrli:identifier scheme="http://dart.fss.or.kr">00356361</xbrli:identifier></xbrli:entity><xbrli:period><xbrli:startDate>2025-01-01</xbrli:startDate><xbrli:endDate>2025-09-30</xbrli:endDate></xbrli:period></xbrli:context>
+  <xbrli:context id="D3M"><xbrli:entity><xbrli:identifier scheme="http://dart.fss.or.kr">00356361</xbrli:identifier></xbrli:entity><xbrli:period><xbrli:startDate>2025-07-01</xbrli:startDate><xbrli:endDate>2025-09-30</xbrli:endDate></xbrli:period></xbrli:context>
+  <xbrli:unit id="KRW"><xbrli:measure>iso4217:KRW</xbrli:measure></xbrli:unit>
+  <ifrs-full:Assets contextRef="I" unitRef="KRW" decimals="-6">82734127000000</ifrs-full:Assets>
+  <ifrs-full:Assets contextRef="S" unitRef="KRW" decimals="-6">45000000000000</ifrs-full:Assets>
+  <ifrs-full:Revenue contextRef="D9M" unitRef="KRW" decimals="-6">13126000000000</ifrs-full:Revenue>
+  <ifrs-full:Revenue contextRef="D3M" unitRef="KRW" decimals="-6">4331000000000</ifrs-full:Revenue>
+  <ifrs-full:CashAndCashEquivalents contextRef="I" unitRef="KRW" decimals="-6">1000000000</ifrs-full:CashAndCashEquivalents>
+</xbrli:xbrl>`
+			zipDocument, err := testhelpers.CreateMockZipArchiveFiles(
+				testhelpers.MockZipFile{Name: "20251114001374.xml", Body: quarterlyReport},
+				testhelpers.MockZipFile{Name: "20251114001374.xbrl", Body: instance},
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			testhelpers.New("https://opendart.fss.or.kr").Get("/api/document.xml").Reply(200).Body(zipDocument).Header("Content-Type", "application/zip")
+			rawData := `{ \"company_name\": \"LG화학\" }`
+			testhelpers.New("https://api.openai.com").
+				Post("/v1/responses").Reply(200).
+				BodyString(fmt.Sprintf(openaiResFmt, rawData)).
+				Header("Content-Type", "application/json")
+
+			ctx := context.Background()
+			Expect(p.HandleAnalyzeReportTask(ctx, analyzeTask)).To(Succeed())
+
+			facts, err := gorm.G[models.FinancialFact](dbConn).Where("source = ?", models.FinancialFactSourceXBRL).Order("account, consolidated").Find(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(facts).To(HaveLen(3))
+
+			Expect(facts[0].Account).To(Equal("sales"))
+			Expect(facts[0].Statement).To(Equal(models.StatementIncomeStatement))
+			Expect(facts[0].PeriodType).To(Equal(models.PeriodTypeQ3))
+			Expect(facts[0].Value).To(Equal(int64(13126000000000)))
+
+			Expect(facts[1].Account).To(Equal("total_assets"))
+			Expect(facts[1].Consolidated).To(BeFalse())
+			Expect(facts[1].Value).To(Equal(int64(45000000000000)))
+
+			Expect(facts[2].Account).To(Equal("total_assets"))
+			Expect(facts[2].Consolidated).To(BeTrue())
+			Expect(facts[2].Statement).To(Equal(models.StatementBalanceSheet))
+			Expect(facts[2].PeriodEnd.Format("2006-01-02")).To(Equal("2025-09-30"))
+			Expect(facts[2].Value).To(Equal(int64(82734127000000)))
+			Expect(facts[2].ReceiptNumber).To(Equal("20251114001374"))
+		})
+
+		It("stores a period the analysis wrote twice once", func() {
+			zipDocument, err := testhelpers.CreateMockZipArchive("document.xml", []byte(testDocument))
+			Expect(err).NotTo(HaveOccurred())
+
+			testhelpers.New("https://opendart.fss.or.kr").Get("/api/document.xml").Reply(200).Body(zipDocument).Header("Content-Type", "application/zip")
+			rawData := `{ \"company_name\": \"LG화학\", \"consolidated_financials_million_krw\": { \"income_statement\": { \"period_2025_09_30\": { \"sales\": 4331 }, \"period_2025_Q3\": { \"sales\": 13126 } } } }`
+			testhelpers.New("https://api.openai.com").
+				Post("/v1/responses").Reply(200).
+				BodyString(fmt.Sprintf(openaiResFmt, rawData)).
+				Header("Content-Type", "application/json")
+
+			ctx := context.Background()
+			Expect(p.HandleAnalyzeReportTask(ctx, analyzeTask)).To(Succeed())
+
+			facts, err := gorm.G[models.FinancialFact](dbConn).Where("source = ?", models.FinancialFactSourceLLM).Find(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(facts).To(HaveLen(1))
+			Expect(facts[0].Account).To(Equal("sales"))
+			Expect(facts[0].PeriodType).To(Equal(models.PeriodTypeQ3))
+			Expect(facts[0].Value).To(Equal(int64(13126000000)))
+		})
+
+		It("sets company name if not set", func() {
+			zipDocument, err := testhelpers.CreateMockZipArchive("document.xml", []byte(testDocument))
+			Expect(err).NotTo(HaveOccurred())
+
+			testhelpers.New("https://opendart.fss.or.kr").Get("/api/document.xml").Reply(200).Body(zipDocument).Header("Content-Type", "application/zip").Header("Content-Disposition", `attachment; filename="document.zip"`)
+
+			company := models.Company{
+				CorpCode: "00356361",
+				CorpName: "LG화학",
+			}
+
+			ctx := context.Background()
+			dbResult := gorm.WithResult()
+			err = gorm.G[models.Company](dbConn, dbResult).Create(ctx, &company)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dbResult.RowsAffected).To(Equal(int64(1)))
+
+			rawData := `{ \"company_name\": \"\", \"date\": \"2025-09-30\", \"type\": \"report\", \"summary\": \"LG화학의 2025년 3분기 보고서\" }`
+			testhelpers.New("https://api.openai.com").
+				Post("/v1/responses").Reply(200).
+				BodyString(fmt.Sprintf(openaiResFmt, rawData)).
+				Header("Content-Type", "application/json")
+
+			err = p.HandleAnalyzeReportTask(ctx, analyzeTask)
+			Expect(err).NotTo(HaveOccurred())
+
+			result, err := gorm.G[models.RawReport](dbConn).Where("corp_code = ?", "00356361").First(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.ReceiptNumber).To(Equal("20251114001374"))
+			Expect(result.CorpCode).To(Equal("00356361"))
+			Expect(strings.TrimSpace(string(result.BlobData))).To(Equal(strings.TrimSpace(testDocument)))
+			Expect(result.JSONData).To(MatchJSON(`{"company_name": "ACME Corp", "report_title": "Form 10-K", "company_cik": "00001234", "tables": [[["Metric", "Amount"], ["Revenue", "1000"], ["Profit", "500"]], [["Line Item", "Value"], ["Total Assets", "2000"]]], "key_paragraphs": ["Primary discussion.", "Secondary paragraph"]}`))
+
+			analysis, err := gorm.G[models.Analysis](dbConn).Where("raw_report_id = ?", result.ID).First(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			var analysisData map[string]interface{}
+			Expect(json.Unmarshal(analysis.Analysis, &analysisData)).NotTo(HaveOccurred())
+			Expect(analysisData["company_name"]).To(Equal("LG화학"))
+			Expect(analysisData["date"]).To(Equal("2025-09-30"))
+			Expect(analysisData["type"]).To(Equal("report"))
+			Expect(analysisData["summary"]).To(Equal("LG화학의 2025년 3분기 보고서"))
+		})
+
+		It("resumes an analyzed filing without calling OpenAI again", func() {
+			ctx := context.Background()
+			rawReport := models.RawReport{
+				ReceiptNumber: "20251114001374",
+				CorpCode:      "00356361",
+				ReportName:    "분기보고서 (2025.09)",
+				BlobData:      []byte(testDocument),
+				BlobSize:      len(testDocument),
+				JSONData:      []byte(`{"company_name": "ACME Corp", "report_title": "분기보고서"}`),
+			}
+			Expect(gorm.G[models.RawReport](dbConn).Create(ctx, &rawReport)).To(Succeed())
+
+			state := models.FilingState{
+				ReceiptNumber: "20251114001374",
+				CorpCode:      "00356361",
+				State:         models.FilingStateFailed,
+				PreviousState: models.FilingStateAnalyzed,
+				FailureReason: "connection reset",
+				Analysis:      []byte(`{"company_name": "LG화학"}`),
+				UsedTokens:    321,
+				Attempts:      1,
+			}
+			Expect(gorm.G[models.FilingState](dbConn).Create(ctx, &state)).To(Succeed())
+
+			err := p.HandleAnalyzeReportTask(ctx, analyzeTask)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(testhelpers.IsDone()).To(BeTrue())
+
+			analysis, err := gorm.G[models.Analysis](dbConn).Where("raw_report_id = ?", rawReport.ID).First(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(analysis.UsedTokens).To(Equal(int64(321)))
+			Expect(analysis.Analysis).To(MatchJSON(`{"company_name": "LG화학"}`))
+
+			stored, err := gorm.G[models.FilingState](dbConn).Where("receipt_number = ?", "20251114001374").First(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stored.State).To(Equal(models.FilingStateStored))
+			Expect(stored.FailureReason).To(BeEmpty())
+		})
+
+		It("parses a filing fetched by an earlier run from its stored document", func() {
+			ctx := context.Background()
+			rawReport := models.RawReport{
+				ReceiptNumber: "20251114001374",
+				CorpCode:      "00356361",
+				ReportName:    "분기보고서 (2025.09)",
+				BlobData:      []byte(testDocument),
+				BlobSize:      len(testDocument),
+				JSONData:      json.RawMessage(`{}`),
+			}
+			Expect(gorm.G[models.RawReport](dbConn).Create(ctx, &rawReport)).To(Succeed())
+
+			state := models.FilingState{
+				ReceiptNumber: "20251114001374",
+				CorpCode:      "00356361",
+				State:         models.FilingStateFailed,
+				PreviousState: models.FilingStateFetched,
+				FailureReason: "connection reset",
+				Attempts:      1,
+			}
+			Expect(gorm.G[models.FilingState](dbConn).Create(ctx, &state)).To(Succeed())
+
+			rawData := `{ \"company_name\": \"LG화학\", \"type\": \"report\" }`
+			testhelpers.New("https://api.openai.com").
+				Post("/v1/responses").Reply(200).
+				BodyString(fmt.Sprintf(openaiResFmt, rawData)).
+				Header("Content-Type", "application/json")
+
+			Expect(p.HandleAnalyzeReportTask(ctx, analyzeTask)).To(Succeed())
+
+			result, err := gorm.G[models.RawReport](dbConn).Where("receipt_number = ?", "20251114001374").First(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			var doc xbrl.UsefulReport
+			Expect(json.Unmarshal(result.JSONData, &doc)).To(Succeed())
+			Expect(doc.ReportTitle).To(Equal("Form 10-K"))
+			Expect(doc.CompanyName).To(Equal("ACME Corp"))
+		})
+
+		It("keeps one analysis when a filing is stored again", func() {
+			ctx := context.Background()
+			rawReport := models.RawReport{
+				ReceiptNumber: "20251114001374",
+				CorpCode:      "00356361",
+				ReportName:    "분기보고서 (2025.09)",
+				BlobData:      []byte(testDocument),
+				BlobSize:      len(testDocument),
+				JSONData:      []byte(`{"company_name": "ACME Corp", "report_title": "분기보고서"}`),
+			}
+			Expect(gorm.G[models.RawReport](dbConn).Create(ctx, &rawReport)).To(Succeed())
+			Expect(gorm.G[models.Analysis](dbConn).Create(ctx, &models.Analysis{
+				RawReportID: rawReport.ID,
+				UsedTokens:  321,
+				Analysis:    json.RawMessage(`{"company_name": "LG"}`),
+			})).To(Succeed())
+
+			state := models.FilingState{
+				ReceiptNumber: "20251114001374",
+				CorpCode:      "00356361",
+				State:         models.FilingStateFailed,
+				PreviousState: models.FilingStateAnalyzed,
+				FailureReason: "connection reset",
+				Analysis:      []byte(`{"company_name": "LG화학"}`),
+				UsedTokens:    321,
+				Attempts:      1,
+			}
+			Expect(gorm.G[models.FilingState](dbConn).Create(ctx, &state)).To(Succeed())
+
+			Expect(p.HandleAnalyzeReportTask(ctx, analyzeTask)).To(Succeed())
+
+			analyses, err := gorm.G[models.Analysis](dbConn).Where("raw_report_id = ?", rawReport.ID).Find(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(analyses).To(HaveLen(1))
+			Expect(analyses[0].Analysis).To(MatchJSON(`{"company_name": "LG화학"}`))
+		})
+
+		It("records a filing whose document is missing without retrying it or charging an attempt", func() {
+			testhelpers.New("https://opendart.fss.or.kr").
+				Get("/api/document.xml").Reply(200).
+				BodyString(`<?xml version="1.0" encoding="UTF-8"?><result><status>014</status><message>파일이 존재하지 않습니다.</message></result>`).
+				Header("Content-Type", "application/xml;charset=UTF-8")
+
+			ctx := context.Background()
+			err := p.HandleAnalyzeReportTask(ctx, analyzeTask)
+			Expect(err).To(MatchError(asynq.SkipRetry))
+
+			state, err := gorm.G[models.FilingState](dbConn).Where("receipt_number = ?", "20251114001374").First(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state.State).To(Equal(models.FilingStateFailed))
+			Expect(state.PreviousState).To(BeEmpty())
+			Expect(state.FailureReason).To(Equal("DART error 014: 파일이 존재하지 않습니다."))
+			Expect(state.Attempts).To(BeZero())
+		})
+
+		It("halts DART calls without charging the filing when the key is refused", func() {
+			testhelpers.New("https://opendart.fss.or.kr").
+				Get("/api/document.xml").Reply(200).
+				BodyString(`<?xml version="1.0" encoding="UTF-8"?><result><status>010</status><message>등록되지 않은 키입니다.</message></result>`).
+				Header("Content-Type", "application/xml;charset=UTF-8")
+
+			ctx := context.Background()
+			err := p.HandleAnalyzeReportTask(ctx, analyzeTask)
+			Expect(err).To(MatchError(dart.ErrUnregisteredKey))
+			Expect(err).NotTo(MatchError(asynq.SkipRetry))
+
+			state, err := gorm.G[models.FilingState](dbConn).Where("receipt_number = ?", "20251114001374").First(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state.State).To(Equal(models.FilingStateFailed))
+			Expect(state.Attempts).To(BeZero())
+
+			// the retry fails without calling DART again
+			err = p.HandleAnalyzeReportTask(ctx, analyzeTask)
+			Expect(err).To(MatchError(dart.ErrUnregisteredKey))
+		})
+
+		It("stores the output of a dedicated parser without calling OpenAI", func() {
+			dividendHTML, err := os.ReadFile("../pkg/dart/testdata/dividend-decision.html")
+			Expect(err).NotTo(HaveOccurred())
+
+			zipDocument, err := testhelpers.CreateMockZipArchive("document.xml", dividendHTML)
+			Expect(err).NotTo(HaveOccurred())
+
+			testhelpers.New("https://opendart.fss.or.kr").Get("/api/document.xml").Reply(200).Body(zipDocument).Header("Content-Type", "application/zip").Header("Content-Disposition", `attachment; filename="document.zip"`)
+
+			task, err := tasks.NewAnalyzeReportTask(dart.List{
+				RceptNo:  "20250731000123",
+				CorpCode: "00126380",
+				CorpName: "삼성전자",
+				ReportNm: "현금ㆍ현물배당결정",
+				RceptDt:  "20250731",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			ctx := context.Background()
+			Expect(p.HandleAnalyzeReportTask(ctx, task)).To(Succeed())
+			Expect(testhelpers.IsDone()).To(BeTrue())
+
+			rawReport, err := gorm.G[models.RawReport](dbConn).Where("receipt_number = ?", "20250731000123").First(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			analysis, err := gorm.G[models.Analysis](dbConn).Where("raw_report_id = ?", rawReport.ID).First(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(analysis.Analyzer).To(Equal(models.AnalyzerParser))
+			Expect(analysis.UsedTokens).To(BeZero())
+
+			var decision dart.DividendDecision
+			Expect(json.Unmarshal(analysis.Analysis, &decision)).To(Succeed())
+			Expect(decision.RceptNo).To(Equal("20250731000123"))
+			Expect(decision.CommonDividendPerShare).To(Equal("370"))
+			Expect(decision.RecordDate).To(Equal("2025-06-30"))
+		})
+
+		It("computes the dilution of a bond issuance from the latest periodic report", func() {
+			ctx := context.Background()
+
+			periodic := models.RawReport{ReceiptNumber: "20250515000100", CorpCode: "00126380", ReportName: "분기보고서 (2025.03)", JSONData: json.RawMessage(`{}`)}
+			Expect(gorm.G[models.RawReport](dbConn).Create(ctx, &periodic)).To(Succeed())
+			Expect(gorm.G[models.Analysis](dbConn).Create(ctx, &models.Analysis{
+				RawReportID: periodic.ID,
+				Analyzer:    models.AnalyzerOpenAI,
+				Analysis:    json.RawMessage(`{"share_info": {"issued_common_shares": 45000000, "outstanding_common_shares": 44475000}}`),
+			})).To(Succeed())
+
+			bondHTML, err := os.ReadFile("../pkg/dart/testdata/convertible-bond.html")
+			Expect(err).NotTo(HaveOccurred())
+
+			zipDocument, err := testhelpers.CreateMockZipArchive("document.xml", bondHTML)
+			Expect(err).NotTo(HaveOccurred())
+
+			testhelpers.New("https://opendart.fss.or.kr").Get("/api/document.xml").Reply(200).Body(zipDocument).Header("Content-Type", "application/zip").Header("Content-Disposition", `attachment; filename="document.zip"`)
+
+			task, err := tasks.NewAnalyzeReportTask(dart.List{
+				RceptNo:  "20250620000321",
+				CorpCode: "00126380",
+				CorpName: "삼성전자",
+				ReportNm: "주요사항보고서(전환사채권발행결정)",
+				RceptDt:  "20250620",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(p.HandleAnalyzeReportTask(ctx, task)).To(Succeed())
+			Expect(testhelpers.IsDone()).To(BeTrue())
+
+			rawReport, err := gorm.G[models.RawReport](dbConn).Where("receipt_number = ?", "20250620000321").First(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			analysis, err := gorm.G[models.Analysis](dbConn).Where("raw_report_id = ?", rawReport.ID).First(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(analysis.Analyzer).To(Equal(models.AnalyzerParser))
+
+			var bond dart.BondIssuanceDoc
+			Expect(json.Unmarshal(analysis.Analysis, &bond)).To(Succeed())
+			Expect(*bond.OutstandingShares).To(Equal(int64(44475000)))
+			Expect(bond.SharesBasis.Source).To(Equal(dart.ShareSourceReport))
+			Expect(bond.SharesBasis.ReceiptNumber).To(Equal("20250515000100"))
+			Expect(bond.SharesBasis.AsOf.Format("2006-01-02")).To(Equal("2025-03-31"))
+			Expect(*bond.Dilution).To(Equal(5.12))
+		})
+
+		It("asks DART for the shares outstanding when the latest periodic report is too old", func() {
+			ctx := context.Background()
+
+			periodic := models.RawReport{ReceiptNumber: "20240515000100", CorpCode: "00126380", ReportName: "분기보고서 (2024.03)", JSONData: json.RawMessage(`{}`)}
+			Expect(gorm.G[models.RawReport](dbConn).Create(ctx, &periodic)).To(Succeed())
+			Expect(gorm.G[models.Analysis](dbConn).Create(ctx, &models.Analysis{
+				RawReportID: periodic.ID,
+				Analyzer:    models.AnalyzerOpenAI,
+				Analysis:    json.RawMessage(`{"share_info": {"issued_common_shares": 40000000, "outstanding_common_shares": 40000000}}`),
+			})).To(Succeed())
+
+			bondHTML, err := os.ReadFile("../pkg/dart/testdata/convertible-bond.html")
+			Expect(err).NotTo(HaveOccurred())
+
+			zipDocument, err := testhelpers.CreateMockZipArchive("document.xml", bondHTML)
+			Expect(err).NotTo(HaveOccurred())
+
+			testhelpers.New("https://opendart.fss.or.kr").Get("/api/document.xml").Reply(200).Body(zipDocument).Header("Content-Type", "application/zip").Header("Content-Disposition", `attachment; filename="document.zip"`)
+			testhelpers.New("https://opendart.fss.or.kr").
+				Get("/api/stockTotqySttus.json?corp_code=00126380&bsns_year=2025&reprt_code=11013").
+				Reply(200).
+				BodyString(`{"status": "000", "message": "정상", "list": [{"rcept_no": "20250515000100", "corp_code": "00126380", "se": "보통주", "istc_totqy": "45,000,000", "tesstk_co": "525,000", "distb_stock_co": "44,475,000", "stlm_dt": "2025-03-31"}]}`)
+
+			task, err := tasks.NewAnalyzeReportTask(dart.List{
+				RceptNo:  "20250620000321",
+				CorpCode: "00126380",
+				CorpName: "삼성전자",
+				ReportNm: "주요사항보고서(전환사채권발행결정)",
+				RceptDt:  "20250620",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(p.HandleAnalyzeReportTask(ctx, task)).To(Succeed())
+			Expect(testhelpers.IsDone()).To(BeTrue())
+
+			rawReport, err := gorm.G[models.RawReport](dbConn).Where("receipt_number = ?", "20250620000321").First(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			analysis, err := gorm.G[models.Analysis](dbConn).Where("raw_report_id = ?", rawReport.ID).First(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			var bond dart.BondIssuanceDoc
+			Expect(json.Unmarshal(analysis.Analysis, &bond)).To(Succeed())
+			Expect(*bond.OutstandingShares).To(Equal(int64(44475000)))
+			Expect(bond.SharesBasis.Source).To(Equal(dart.ShareSourceDartAPI))
+			Expect(bond.SharesBasis.AsOf.Format("2006-01-02")).To(Equal("2025-03-31"))
+			Expect(*bond.Dilution).To(Equal(5.12))
+		})
+
+		It("stores insider transactions from an ownership report", func() {
+			ownershipHTML, err := os.ReadFile("../pkg/dart/testdata/insider-ownership.html")
+			Expect(err).NotTo(HaveOccurred())
+
+			zipDocument, err := testhelpers.CreateMockZipArchive("document.xml", ownershipHTML)
+			Expect(err).NotTo(HaveOccurred())
+
+			testhelpers.New("https://opendart.fss.or.kr").Get("/api/document.xml").Reply(200).Body(zipDocument).Header("Content-Type", "application/zip").Header("Content-Disposition", `attachment; filename="document.zip"`)
+
+			task, err := tasks.NewAnalyzeReportTask(dart.List{
+				RceptNo:  "20250616000111",
+				CorpCode: "00126380",
+				CorpName: "삼성전자",
+				ReportNm: "임원ㆍ주요주주특정증권등소유상황보고서",
+				RceptDt:  "20250616",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			ctx := context.Background()
+			Expect(p.HandleAnalyzeReportTask(ctx, task)).To(Succeed())
+			Expect(testhelpers.IsDone()).To(BeTrue())
+
+			transactions, err := gorm.G[models.InsiderTransaction](dbConn).Where("receipt_number = ?", "20250616000111").Order("seq").Find(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(transactions).To(HaveLen(3))
+			Expect(transactions[0].CorpCode).To(Equal("00126380"))
+			Expect(transactions[0].HolderName).To(Equal("홍길동"))
+			Expect(transactions[0].ChangeDate.Format("2006-01-02")).To(Equal("2025-06-10"))
+			Expect(transactions[0].SharesChange).To(Equal(int64(10000)))
+			Expect(*transactions[0].Price).To(Equal(int64(15200)))
+			Expect(transactions[2].SharesChange).To(Equal(int64(-2000)))
+		})
+
+		It("stores a treasury stock event with the market cap at its reference price", func() {
+			ctx := context.Background()
+
+			periodic := models.RawReport{ReceiptNumber: "20241114000100", CorpCode: "00126380", ReportName: "분기보고서 (2024.09)", JSONData: json.RawMessage(`{}`)}
+			Expect(gorm.G[models.RawReport](dbConn).Create(ctx, &periodic)).To(Succeed())
+			Expect(gorm.G[models.Analysis](dbConn).Create(ctx, &models.Analysis{
+				RawReportID: periodic.ID,
+				Analyzer:    models.AnalyzerOpenAI,
+				Analysis:    json.RawMessage(`{"share_info": {"issued_common_shares": 50000000, "outstanding_common_shares": 0}}`),
+			})).To(Succeed())
+
+			treasuryHTML, err := os.ReadFile("../pkg/dart/testdata/treasury-acquisition.html")
+			Expect(err).NotTo(HaveOccurred())
+
+			zipDocument, err := testhelpers.CreateMockZipArchive("document.xml", treasuryHTML)
+			Expect(err).NotTo(HaveOccurred())
+
+			testhelpers.New("https://opendart.fss.or.kr").Get("/api/document.xml").Reply(200).Body(zipDocument).Header("Content-Type", "application/zip").Header("Content-Disposition", `attachment; filename="document.zip"`)
+
+			task, err := tasks.NewAnalyzeReportTask(dart.List{
+				RceptNo:  "20250318000222",
+				CorpCode: "00126380",
+				CorpName: "삼성전자",
+				ReportNm: "주요사항보고서(자기주식취득결정)",
+				RceptDt:  "20250318",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(p.HandleAnalyzeReportTask(ctx, task)).To(Succeed())
+			Expect(testhelpers.IsDone()).To(BeTrue())
+
+			event, err := gorm.G[models.TreasuryStockEvent](dbConn).Where("receipt_number = ?", "20250318000222").First(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(event.Event).To(Equal(models.TreasuryStockAcquisition))
+			Expect(*event.Amount).To(Equal(int64(70000000000)))
+			Expect(*event.ReferencePrice).To(Equal(int64(70000)))
+			Expect(*event.OutstandingShares).To(Equal(int64(50000000)))
+			Expect(*event.MarketCap).To(Equal(int64(3500000000000)))
+		})
+
+		It("replaces the treasury stock event of the filing a correction amends", func() {
+			ctx := context.Background()
+
+			periodic := models.RawReport{ReceiptNumber: "20241114000100", CorpCode: "00126380", ReportName: "분기보고서 (2024.09)", JSONData: json.RawMessage(`{}`)}
+			Expect(gorm.G[models.RawReport](dbConn).Create(ctx, &periodic)).To(Succeed())
+			Expect(gorm.G[models.Analysis](dbConn).Create(ctx, &models.Analysis{
+				RawReportID: periodic.ID,
+				Analyzer:    models.AnalyzerOpenAI,
+				Analysis:    json.RawMessage(`{"share_info": {"issued_common_shares": 50000000, "outstanding_common_shares": 0}}`),
+			})).To(Succeed())
+
+			original := models.RawReport{ReceiptNumber: "20250318000222", CorpCode: "00126380", ReportName: "주요사항보고서(자기주식취득결정)", JSONData: json.RawMessage(`{}`)}
+			Expect(gorm.G[models.RawReport](dbConn).Create(ctx, &original)).To(Succeed())
+			amount := int64(50000000000)
+			Expect(gorm.G[models.TreasuryStockEvent](dbConn).Create(ctx, &models.TreasuryStockEvent{
+				CorpCode:      "00126380",
+				ReceiptNumber: "20250318000222",
+				Event:         models.TreasuryStockAcquisition,
+				Amount:        &amount,
+			})).To(Succeed())
+
+			treasuryHTML, err := os.ReadFile("../pkg/dart/testdata/treasury-acquisition.html")
+			Expect(err).NotTo(HaveOccurred())
+
+			zipDocument, err := testhelpers.CreateMockZipArchive("document.xml", treasuryHTML)
+			Expect(err).NotTo(HaveOccurred())
+
+			testhelpers.New("https://opendart.fss.or.kr").Get("/api/document.xml").Reply(200).Body(zipDocument).Header("Content-Type", "application/zip").Header("Content-Disposition", `attachment; filename="document.zip"`)
+
+			task, err := tasks.NewAnalyzeReportTask(dart.List{
+				RceptNo:  "20250320000333",
+				CorpCode: "00126380",
+				CorpName: "삼성전자",
+				ReportNm: "[기재정정]주요사항보고서(자기주식취득결정)",
+				RceptDt:  "20250320",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(p.HandleAnalyzeReportTask(ctx, task)).To(Succeed())
+			Expect(testhelpers.IsDone()).To(BeTrue())
+
+			events, err := gorm.G[models.TreasuryStockEvent](dbConn).Where("corp_code = ?", "00126380").Find(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(HaveLen(1))
+			Expect(events[0].ReceiptNumber).To(Equal("20250320000333"))
+			Expect(*events[0].Amount).To(Equal(int64(70000000000)))
+		})
+
+		It("links a correction to the filing it amends and records the changed fields", func() {
+			ctx := context.Background()
+
+			originalHTML, err := os.ReadFile("../pkg/dart/testdata/supply-contract.html")
+			Expect(err).NotTo(HaveOccurred())
+			parsed, err := dart.ParseSupplyContractHTML(string(originalHTML), "20241230000789")
+			Expect(err).NotTo(HaveOccurred())
+			parsedJSON, err := json.Marshal(parsed)
+			Expect(err).NotTo(HaveOccurred())
+
+			original := models.RawReport{ReceiptNumber: "20241230000789", CorpCode: "00356361", ReportName: "단일판매ㆍ공급계약체결", JSONData: json.RawMessage(`{}`)}
+			Expect(gorm.G[models.RawReport](dbConn).Create(ctx, &original)).To(Succeed())
+			Expect(gorm.G[models.Analysis](dbConn).Create(ctx, &models.Analysis{
+				RawReportID: original.ID,
+				Analyzer:    models.AnalyzerParser,
+				Analysis:    parsedJSON,
+			})).To(Succeed())
+
+			amendmentHTML, err := os.ReadFile("../pkg/dart/testdata/supply-contract-amendment.html")
+			Expect(err).NotTo(HaveOccurred())
+
+			zipDocument, err := testhelpers.CreateMockZipArchive("document.xml", amendmentHTML)
+			Expect(err).NotTo(HaveOccurred())
+
+			testhelpers.New("https://opendart.fss.or.kr").Get("/api/document.xml").Reply(200).Body(zipDocument).Header("Content-Type", "application/zip").Header("Content-Disposition", `attachment; filename="document.zip"`)
+
+			task, err := tasks.NewAnalyzeReportTask(dart.List{
+				RceptNo:  "20250602000456",
+				CorpCode: "00356361",
+				CorpName: "LG화학",
+				ReportNm: "[기재정정]단일판매ㆍ공급계약체결",
+				RceptDt:  "20250602",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(p.HandleAnalyzeReportTask(ctx, task)).To(Succeed())
+			Expect(testhelpers.IsDone()).To(BeTrue())
+
+			correction, err := gorm.G[models.RawReport](dbConn).Where("receipt_number = ?", "20250602000456").First(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(correction.CorrectsReceiptNumber).NotTo(BeNil())
+			Expect(*correction.CorrectsReceiptNumber).To(Equal("20241230000789"))
+
+			var diff []struct {
+				Path   string `json:"path"`
+				Before any    `json:"before"`
+				After  any    `json:"after"`
+			}
+			Expect(json.Unmarshal(correction.CorrectionDiff, &diff)).To(Succeed())
+			paths := []string{}
+			for _, change := range diff {
+				paths = append(paths, change.Path)
+			}
+			Expect(paths).To(ContainElements("amount_krw", "ratio_to_sales", "term_to"))
+			Expect(paths).NotTo(ContainElement("rcept_no"))
+			Expect(paths).NotTo(ContainElement("counterparty"))
+			Expect(diff[0].Path).To(Equal("amount_krw"))
+			Expect(diff[0].Before).To(BeNumerically("==", 1234567890000))
+			Expect(diff[0].After).To(BeNumerically("==", 1500000000000))
+		})
+
+		It("links a correction stored before its original once the original is stored", func() {
+			ctx := context.Background()
+
+			amendmentHTML, err := os.ReadFile("../pkg/dart/testdata/supply-contract-amendment.html")
+			Expect(err).NotTo(HaveOccurred())
+			zipAmendment, err := testhelpers.CreateMockZipArchive("document.xml", amendmentHTML)
+			Expect(err).NotTo(HaveOccurred())
+
+			testhelpers.New("https://opendart.fss.or.kr").Get("/api/document.xml").Reply(200).Body(zipAmendment).Header("Content-Type", "application/zip").Header("Content-Disposition", `attachment; filename="document.zip"`)
+			// DART does not list the original yet
+			testhelpers.New("https://opendart.fss.or.kr").
+				Get("/api/list.json?corp_code=00356361&bgn_de=20241230&end_de=20241230").Reply(200).
+				BodyString(`{"status": "000", "message": "정상", "page_no": 1, "page_count": 0, "total_count": 0, "total_page": 1, "list": []}`).
+				Header("Content-Type", "application/json")
+
+			correctionTask, err := tasks.NewAnalyzeReportTask(dart.List{
+				RceptNo:  "20250602000456",
+				CorpCode: "00356361",
+				CorpName: "LG화학",
+				ReportNm: "[기재정정]단일판매ㆍ공급계약체결",
+				RceptDt:  "20250602",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p.HandleAnalyzeReportTask(ctx, correctionTask)).To(Succeed())
+
+			correction, err := gorm.G[models.RawReport](dbConn).Where("receipt_number = ?", "20250602000456").First(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(correction.CorrectsReceiptNumber).To(BeNil())
+
+			originalHTML, err := os.ReadFile("../pkg/dart/testdata/supply-contract.html")
+			Expect(err).NotTo(HaveOccurred())
+			zipOriginal, err := testhelpers.CreateMockZipArchive("document.xml", originalHTML)
+			Expect(err).NotTo(HaveOccurred())
+
+			testhelpers.New("https://opendart.fss.or.kr").Get("/api/document.xml").Reply(200).Body(zipOriginal).Header("Content-Type", "application/zip").Header("Content-Disposition", `attachment; filename="document.zip"`)
+
+			originalTask, err := tasks.NewAnalyzeReportTask(dart.List{
+				RceptNo:  "20241230000789",
+				CorpCode: "00356361",
+				CorpName: "LG화학",
+				ReportNm: "단일판매ㆍ공급계약체결",
+				RceptDt:  "20241230",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p.HandleAnalyzeReportTask(ctx, originalTask)).To(Succeed())
+			Expect(testhelpers.IsDone()).To(BeTrue())
+
+			correction, err = gorm.G[models.RawReport](dbConn).Where("receipt_number = ?", "20250602000456").First(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(correction.CorrectsReceiptNumber).NotTo(BeNil())
+			Expect(*correction.CorrectsReceiptNumber).To(Equal("20241230000789"))
+
+			var diff []struct {
+				Path string `json:"path"`
+			}
+			Expect(json.Unmarshal(correction.CorrectionDiff, &diff)).To(Succeed())
+			Expect(diff).To(ContainElement(HaveField("Path", "amount_krw")))
+		})
+
+		It("keeps a correction to retry when its original cannot be looked up", func() {
+			ctx := context.Background()
+
+			amendmentHTML, err := os.ReadFile("../pkg/dart/testdata/supply-contract-amendment.html")
+			Expect(err).NotTo(HaveOccurred())
+			zipAmendment, err := testhelpers.CreateMockZipArchive("document.xml", amendmentHTML)
+			Expect(err).NotTo(HaveOccurred())
+
+			// list.json is not mocked, looking up the original fails
+			testhelpers.New("https://opendart.fss.or.kr").Get("/api/document.xml").Reply(200).Body(zipAmendment).Header("Content-Type", "application/zip").Header("Content-Disposition", `attachment; filename="document.zip"`)
+
+			task, err := tasks.NewAnalyzeReportTask(dart.List{
+				RceptNo:  "20250602000456",
+				CorpCode: "00356361",
+				CorpName: "LG화학",
+				ReportNm: "[기재정정]단일판매ㆍ공급계약체결",
+				RceptDt:  "20250602",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p.HandleAnalyzeReportTask(ctx, task)).NotTo(Succeed())
+
+			state, err := gorm.G[models.FilingState](dbConn).Where("receipt_number = ?", "20250602000456").First(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state.State).To(Equal(models.FilingStateFailed))
+			Expect(state.PreviousState).To(Equal(models.FilingStateAnalyzed))
+
+			count, err := gorm.G[models.Analysis](dbConn).Count(ctx, "id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(BeZero())
+
+			testhelpers.New("https://opendart.fss.or.kr").
+				Get("/api/list.json?corp_code=00356361&bgn_de=20241230&end_de=20241230").Reply(200).
+				BodyString(`{"status": "000", "message": "정상", "page_no": 1, "page_count": 1, "total_count": 1, "total_page": 1, "list": [{"corp_code": "00356361", "corp_name": "LG화학", "stock_code": "051910", "corp_cls": "Y", "report_nm": "단일판매ㆍ공급계약체결", "rcept_no": "20241230000789", "flr_nm": "LG화학", "rcept_dt": "20241230", "rm": "유"}]}`).
+				Header("Content-Type", "application/json")
+
+			Expect(p.HandleAnalyzeReportTask(ctx, task)).To(Succeed())
+
+			correction, err := gorm.G[models.RawReport](dbConn).Where("receipt_number = ?", "20250602000456").First(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(correction.CorrectsReceiptNumber).NotTo(BeNil())
+			Expect(*correction.CorrectsReceiptNumber).To(Equal("20241230000789"))
+		})
 	})
 
 	DescribeTable("Handle errors from Dart API",
@@ -0,0 +1,540 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"kosis/internal/models"
+	"kosis/internal/pkg/dart"
+	"kosis/internal/pkg/openai"
+	"kosis/internal/pkg/xbrl"
+	"log"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// the analysis reports financial statements in million KRW
+const millionKRW = 1_000_000
+
+var periodTypes = map[dart.ReportType]string{
+	dart.FIRST_QUARTER:   models.PeriodTypeQ1,
+	dart.HALF_YEAR:       models.PeriodTypeH1,
+	dart.THIRD_QUARTER:   models.PeriodTypeQ3,
+	dart.BUSINESS_REPORT: models.PeriodTypeFY,
+}
+
+// periodic report names end with the period they cover, e.g. 분기보고서 (2025.09)
+var rePeriodicReport = regexp.MustCompile(`(사업|반기|분기)보고서\s*\((\d{4})\.(\d{2})\)`)
+
+// "period_2024_12_31" for balance sheets, "period_2025_H1" or "period_2024" for income statements
+var rePeriodKey = regexp.MustCompile(`^period_(\d{4})(?:_(\d{2})_(\d{2})|_(H1|Q1|Q3))?$`)
+
+// "2024.12.31 현재" or "2024.01.01 ~ 2024.12.31"
+var reAccountDate = regexp.MustCompile(`(\d{4})\.(\d{2})\.(\d{2})\s*(?:현재)?\s*$`)
+
+// HandleFetchFinancialsTask stores the financial statements DART publishes for a periodic report
+func (p *TaskProcessor) HandleFetchFinancialsTask(ctx context.Context, t *asynq.Task) error {
+	var payload FetchFinancialsPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	if payload.CorpCode == "" || payload.BsnsYear == "" || periodTypes[payload.ReprtCode] == "" {
+		return fmt.Errorf("invalid payload %+v: %w", payload, asynq.SkipRetry)
+	}
+
+	log.Printf("Fetching financials for %+v", payload)
+
+	if err := p.checkDart(); err != nil {
+		return err
+	}
+
+	items, err := p.dartClient.GetMajorAccounts(payload.CorpCode, payload.BsnsYear, payload.ReprtCode)
+	if err != nil {
+		return fmt.Errorf("failed to get major accounts: %w", p.dartError(err))
+	}
+
+	facts := factsFromAccounts(items)
+	if err := p.upsertFinancialFacts(ctx, facts); err != nil {
+		return err
+	}
+
+	log.Printf("stored %d financial facts of %s", len(facts), payload.CorpCode)
+	return nil
+}
+
+// enqueueFetchFinancials asks for the DART financial statements of a periodic report
+func (p *TaskProcessor) enqueueFetchFinancials(ctx context.Context, corpCode, reportName string) error {
+	bsnsYear, reportCode, ok := periodOfReport(reportName)
+	if !ok {
+		return nil
+	}
+
+	task, err := NewFetchFinancialsTask(corpCode, bsnsYear, reportCode)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.enqueuer.EnqueueContext(ctx, task)
+	if err != nil && !errors.Is(err, asynq.ErrDuplicateTask) {
+		return fmt.Errorf("failed to enqueue fetch financials task: %w", err)
+	}
+
+	return nil
+}
+
+func (p *TaskProcessor) upsertFinancialFacts(ctx context.Context, facts []models.FinancialFact) error {
+	if len(facts) == 0 {
+		return nil
+	}
+
+	// Postgres rejects an insert that updates the same row twice
+	facts = uniqueFacts(facts)
+
+	// a later filing, a correction or a report restating the period, must not be overwritten by an earlier one
+	// stored after it
+	return p.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{
+			{Name: "corp_code"}, {Name: "period_end"}, {Name: "period_type"}, {Name: "statement"},
+			{Name: "account"}, {Name: "consolidated"}, {Name: "source"},
+		},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "unit", "receipt_number", "updated_at"}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "financial_facts.receipt_number <= excluded.receipt_number"},
+		}},
+	}).Create(&facts).Error
+}
+
+// uniqueFacts keeps one fact per row of financial_facts, that of the latest filing or else the first one.
+// DART names an account twice, e.g. 매출액 and 수익(매출액), and an analysis a period, e.g. period_2025_Q3
+// and period_2025_09_30.
+func uniqueFacts(facts []models.FinancialFact) []models.FinancialFact {
+	type key struct {
+		corpCode, periodEnd, periodType, statement, account string
+		consolidated                                        bool
+		source                                              string
+	}
+
+	seen := map[key]int{} // index in out
+	out := make([]models.FinancialFact, 0, len(facts))
+	for _, f := range facts {
+		k := key{f.CorpCode, f.PeriodEnd.Format("2006-01-02"), f.PeriodType, f.Statement, f.Account, f.Consolidated, f.Source}
+		i, ok := seen[k]
+		switch {
+		case !ok:
+			seen[k] = len(out)
+			out = append(out, f)
+		case f.ReceiptNumber > out[i].ReceiptNumber:
+			out[i] = f
+		}
+	}
+	return out
+}
+
+// storeReportFacts keeps the financial statements of a periodic report, the ones of its XBRL instances,
+// the ones read from its tables and the ones the analysis extracted. It returns the analysis with its statements replaced by the amounts
+// of the tables, the analysis only cross-checks them.
+func (p *TaskProcessor) storeReportFacts(ctx context.Context, rawReport *models.RawReport, doc *xbrl.UsefulReport, analysis json.RawMessage) (json.RawMessage, error) {
+	var report openai.Report
+	if err := json.Unmarshal(analysis, &report); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal report: %w", err)
+	}
+
+	fiscalEnd, err := p.fiscalYearEnd(ctx, rawReport.CorpCode)
+	if err != nil {
+		return nil, err
+	}
+
+	facts, err := factsFromReport(rawReport.CorpCode, rawReport.ReceiptNumber, fiscalEnd, analysis)
+	if err != nil {
+		return nil, err
+	}
+
+	facts = append(facts, factsFromInstance(rawReport.CorpCode, rawReport.ReceiptNumber, doc.Facts, fiscalEnd)...)
+
+	accounts := tableAccounts(doc.RecognizeAccounts(), fiscalEnd)
+	if len(accounts) > 0 {
+		facts = append(facts, factsFromTables(rawReport.CorpCode, rawReport.ReceiptNumber, accounts, fiscalEnd)...)
+
+		applyTableAccounts(&report, accounts, rawReport.ReceiptNumber, fiscalEnd)
+		j, err := replaceStatements(analysis, &report)
+		if err != nil {
+			return nil, err
+		}
+		analysis = j
+	}
+
+	if err := p.upsertFinancialFacts(ctx, facts); err != nil {
+		return nil, err
+	}
+	return analysis, nil
+}
+
+// fiscalYearEnd returns the month the fiscal year of a company ends in, December until company.json tells it
+func (p *TaskProcessor) fiscalYearEnd(ctx context.Context, corpCode string) (time.Month, error) {
+	company, err := gorm.G[models.Company](p.DB).Where("corp_code = ?", corpCode).First(ctx)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return time.December, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get company %s: %w", corpCode, err)
+	}
+
+	month, err := strconv.Atoi(company.FiscalMonth)
+	if err != nil || month < 1 || month > 12 {
+		return time.December, nil
+	}
+	return time.Month(month), nil
+}
+
+// periodOfReport returns the business year and report code of a periodic report name
+func periodOfReport(reportName string) (string, dart.ReportType, bool) {
+	m := rePeriodicReport.FindStringSubmatch(reportName)
+	if m == nil {
+		return "", "", false
+	}
+
+	switch {
+	case m[1] == "사업":
+		return m[2], dart.BUSINESS_REPORT, true
+	case m[1] == "반기":
+		return m[2], dart.HALF_YEAR, true
+	case m[3] == "03":
+		return m[2], dart.FIRST_QUARTER, true
+	case m[3] == "09":
+		return m[2], dart.THIRD_QUARTER, true
+	}
+
+	return "", "", false
+}
+
+// periodEndOfReport returns the end of the period a periodic report name covers, the last day of its month
+func periodEndOfReport(reportName string) (time.Time, bool) {
+	m := rePeriodicReport.FindStringSubmatch(reportName)
+	if m == nil {
+		return time.Time{}, false
+	}
+
+	year, _ := strconv.Atoi(m[2])
+	month, _ := strconv.Atoi(m[3])
+	if month < 1 || month > 12 {
+		return time.Time{}, false
+	}
+	return time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC), true
+}
+
+// factsFromAccounts converts DART account rows to financial facts in KRW.
+// Income statement facts use the cumulative amount so they line up with half year and annual figures.
+func factsFromAccounts(items []dart.AccountItem) []models.FinancialFact {
+	facts := []models.FinancialFact{}
+	for _, item := range items {
+		account, _, ok := xbrl.CanonicalAccount(item.AccountNm)
+		if !ok {
+			continue
+		}
+
+		periodType := periodTypes[item.ReprtCode]
+		if periodType == "" {
+			continue
+		}
+
+		var statement string
+		var value *int64
+		switch item.SjDiv {
+		case "BS":
+			statement = models.StatementBalanceSheet
+			value = item.CurrentAmount()
+		case "IS", "CIS":
+			statement = models.StatementIncomeStatement
+			value = item.CumulativeAmount()
+			if value == nil {
+				value = item.CurrentAmount()
+			}
+		case "CF":
+			statement = models.StatementCashFlow
+			value = item.CurrentAmount()
+		default:
+			continue
+		}
+		if value == nil {
+			continue
+		}
+
+		periodEnd, ok := accountPeriodEnd(item)
+		if !ok {
+			log.Printf("unknown period end for %s %s %s", item.CorpCode, item.BsnsYear, item.ReprtCode)
+			continue
+		}
+
+		unit := item.Currency
+		if unit == "" {
+			unit = "KRW"
+		}
+
+		facts = append(facts, models.FinancialFact{
+			CorpCode:      item.CorpCode,
+			PeriodEnd:     periodEnd,
+			PeriodType:    periodType,
+			Statement:     statement,
+			Account:       account,
+			Consolidated:  item.FsDiv == dart.CONSOLIDATED,
+			Value:         *value,
+			Unit:          unit,
+			ReceiptNumber: item.RceptNo,
+			Source:        models.FinancialFactSourceDartAPI,
+		})
+	}
+
+	return facts
+}
+
+// factsFromInstance converts the facts of the XBRL instances of a report to financial facts. Facts broken down
+// by another dimension than the scope, e.g. by segment, are left out, as is income of a period not starting
+// with the fiscal year.
+func factsFromInstance(corpCode, receiptNumber string, values []xbrl.FactValue, fiscalEnd time.Month) []models.FinancialFact {
+	months := map[string]int{models.PeriodTypeQ1: 3, models.PeriodTypeH1: 6, models.PeriodTypeQ3: 9, models.PeriodTypeFY: 12}
+
+	facts := []models.FinancialFact{}
+	for _, v := range values {
+		if v.Number == nil || v.Currency == "" || len(v.Dimensions) > 0 {
+			continue
+		}
+		account, statement, ok := xbrl.CanonicalAccount(v.Concept)
+		if !ok {
+			continue
+		}
+
+		date := v.Instant
+		if statement == xbrl.StatementIncomeStatement {
+			date = v.EndDate
+		}
+		end, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			continue
+		}
+		periodType := periodTypeOfMonth(end.Month(), fiscalEnd)
+
+		fact := models.FinancialFact{
+			CorpCode:      corpCode,
+			PeriodEnd:     end,
+			PeriodType:    periodType,
+			Statement:     models.StatementBalanceSheet,
+			Account:       account,
+			Consolidated:  v.Scope == xbrl.ScopeConsolidated,
+			Value:         int64(math.Round(*v.Number)),
+			Unit:          v.Currency,
+			ReceiptNumber: receiptNumber,
+			Source:        models.FinancialFactSourceXBRL,
+		}
+		if statement == xbrl.StatementIncomeStatement {
+			start, err := time.Parse("2006-01-02", v.StartDate)
+			if err != nil || !start.AddDate(0, months[periodType], 0).Equal(end.AddDate(0, 0, 1)) {
+				continue
+			}
+			fact.Statement = models.StatementIncomeStatement
+		}
+		facts = append(facts, fact)
+	}
+	return facts
+}
+
+// reportStatements are the statements of an analysis, by period key and account. The amounts are kept raw
+// so that an account the analysis left out or could not read is told apart from a zero.
+type reportStatements struct {
+	BalanceSheet    map[string]map[string]json.RawMessage `json:"balance_sheet"`
+	IncomeStatement map[string]map[string]json.RawMessage `json:"income_statement"`
+}
+
+// the accounts of the analysis statements stored as financial facts
+var (
+	consolidatedBalanceSheetAccounts    = []string{"total_assets", "total_liabilities", "total_equity", "equity_attributable_to_owners", "non_controlling_interests", "capital"}
+	consolidatedIncomeStatementAccounts = []string{"sales", "operating_income", "net_income", "owners_net_income"}
+	separateBalanceSheetAccounts        = []string{"total_assets", "total_liabilities", "total_equity", "capital"}
+	separateIncomeStatementAccounts     = []string{"sales", "operating_income", "net_income"}
+)
+
+// factsFromReport converts the financial statements of an analysis to financial facts in KRW.
+// Period keys without a date are read with the fiscal year ending in fiscalEnd.
+func factsFromReport(corpCode, receiptNumber string, fiscalEnd time.Month, analysis json.RawMessage) ([]models.FinancialFact, error) {
+	var report struct {
+		Consolidated reportStatements `json:"consolidated_financials_million_krw"`
+		Separate     reportStatements `json:"separate_financials_million_krw"`
+	}
+	if err := json.Unmarshal(analysis, &report); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal statements: %w", err)
+	}
+
+	facts := []models.FinancialFact{}
+	add := func(statements map[string]map[string]json.RawMessage, statement string, consolidated bool, accounts []string) {
+		tableStatement := xbrl.StatementIncomeStatement
+		if statement == models.StatementBalanceSheet {
+			tableStatement = xbrl.StatementBalanceSheet
+		}
+
+		// a period written twice, e.g. period_2025_Q3 and period_2025_09_30, is read from the key tablePeriodKey
+		// writes, which comes first
+		type period struct {
+			key        string
+			end        time.Time
+			periodType string
+			preferred  bool
+		}
+		var periods []period
+		for key := range statements {
+			periodEnd, periodType, ok := parsePeriodKey(key, fiscalEnd)
+			if !ok {
+				log.Printf("unknown period key %q in %s", key, receiptNumber)
+				continue
+			}
+			periods = append(periods, period{key, periodEnd, periodType, key == tablePeriodKey(tableStatement, periodEnd, fiscalEnd)})
+		}
+		slices.SortFunc(periods, func(a, b period) int {
+			if a.preferred != b.preferred {
+				if a.preferred {
+					return -1
+				}
+				return 1
+			}
+			return strings.Compare(a.key, b.key)
+		})
+
+		for _, pk := range periods {
+			key, periodEnd, periodType, values := pk.key, pk.end, pk.periodType, statements[pk.key]
+
+			for _, account := range accounts {
+				raw, ok := values[account]
+				if !ok {
+					continue
+				}
+				value, ok := analysisAmount(raw)
+				if !ok {
+					log.Printf("unreadable %s %s %s in %s: %s", statement, key, account, receiptNumber, raw)
+					continue
+				}
+
+				facts = append(facts, models.FinancialFact{
+					CorpCode:      corpCode,
+					PeriodEnd:     periodEnd,
+					PeriodType:    periodType,
+					Statement:     statement,
+					Account:       account,
+					Consolidated:  consolidated,
+					Value:         value * millionKRW,
+					Unit:          "KRW",
+					ReceiptNumber: receiptNumber,
+					Source:        models.FinancialFactSourceLLM,
+				})
+			}
+		}
+	}
+
+	add(report.Consolidated.BalanceSheet, models.StatementBalanceSheet, true, consolidatedBalanceSheetAccounts)
+	add(report.Consolidated.IncomeStatement, models.StatementIncomeStatement, true, consolidatedIncomeStatementAccounts)
+	add(report.Separate.BalanceSheet, models.StatementBalanceSheet, false, separateBalanceSheetAccounts)
+	add(report.Separate.IncomeStatement, models.StatementIncomeStatement, false, separateIncomeStatementAccounts)
+
+	return uniqueFacts(facts), nil
+}
+
+// analysisAmount reads an amount of the analysis, null or a value that is not a number is no amount
+func analysisAmount(raw json.RawMessage) (int64, bool) {
+	var n json.Number
+	if err := json.Unmarshal(raw, &n); err != nil || n == "" {
+		return 0, false
+	}
+	if v, err := n.Int64(); err == nil {
+		return v, true
+	}
+	if f, err := n.Float64(); err == nil {
+		return int64(math.Round(f)), true
+	}
+	return 0, false
+}
+
+// parsePeriodKey returns the period end and type of an analysis period key. Keys without a date name the
+// period of the fiscal year ending in fiscalEnd that ends in their year, e.g. period_2025_H1 of a fiscal year
+// ending in March ends on 2025-09-30.
+func parsePeriodKey(key string, fiscalEnd time.Month) (time.Time, string, bool) {
+	m := rePeriodKey.FindStringSubmatch(key)
+	if m == nil {
+		return time.Time{}, "", false
+	}
+
+	year, _ := strconv.Atoi(m[1])
+	if m[2] != "" {
+		month, _ := strconv.Atoi(m[2])
+		day, _ := strconv.Atoi(m[3])
+		d := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+		if d.Month() != time.Month(month) || d.Day() != day {
+			return time.Time{}, "", false
+		}
+
+		return d, periodTypeOfMonth(d.Month(), fiscalEnd), true
+	}
+
+	months, periodType := 12, models.PeriodTypeFY
+	switch m[4] {
+	case "Q1":
+		months, periodType = 3, models.PeriodTypeQ1
+	case "H1":
+		months, periodType = 6, models.PeriodTypeH1
+	case "Q3":
+		months, periodType = 9, models.PeriodTypeQ3
+	}
+
+	// the last day of the month the period ends in
+	month := time.Month((int(fiscalEnd)+months-1)%12 + 1)
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC), periodType, true
+}
+
+// periodTypeOfMonth returns the period type of a period ending in month of a fiscal year ending in fiscalEnd
+func periodTypeOfMonth(month, fiscalEnd time.Month) string {
+	switch (int(month) - int(fiscalEnd) + 12) % 12 {
+	case 3:
+		return models.PeriodTypeQ1
+	case 6:
+		return models.PeriodTypeH1
+	case 9:
+		return models.PeriodTypeQ3
+	}
+
+	return models.PeriodTypeFY
+}
+
+// accountPeriodEnd returns the end of the current period of an account row,
+// falling back to a fiscal year ending in December when DART omits the date
+func accountPeriodEnd(item dart.AccountItem) (time.Time, bool) {
+	if m := reAccountDate.FindStringSubmatch(item.ThstrmDt); m != nil {
+		d, err := time.Parse("20060102", m[1]+m[2]+m[3])
+		if err == nil {
+			return d, true
+		}
+	}
+
+	year, err := strconv.Atoi(item.BsnsYear)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	switch item.ReprtCode {
+	case dart.FIRST_QUARTER:
+		return time.Date(year, time.March, 31, 0, 0, 0, 0, time.UTC), true
+	case dart.HALF_YEAR:
+		return time.Date(year, time.June, 30, 0, 0, 0, 0, time.UTC), true
+	case dart.THIRD_QUARTER:
+		return time.Date(year, time.September, 30, 0, 0, 0, 0, time.UTC), true
+	case dart.BUSINESS_REPORT:
+		return time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC), true
+	}
+
+	return time.Time{}, false
+}
@@ -0,0 +1,575 @@
+package tasks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"kosis/internal/models"
+	"kosis/internal/pkg/dart"
+	"kosis/internal/pkg/openai"
+	"kosis/internal/pkg/xbrl"
+	"log"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxFilingAttempts is how many times a failed filing is retried before it is left alone
+const maxFilingAttempts = 3
+
+// ingestFiling moves a single filing through fetched → parsed → analyzed → stored.
+// Every transition is persisted in the transaction of the step it completes, so a
+// restarted worker resumes from the last completed state and never pays for the same
+// analysis twice. Failures are recorded on the filing state before they are returned,
+// so asynq can retry the filing on its own; a missing document is not retried, and a
+// filing is not charged an attempt for it or when DART calls are halted.
+func (p *TaskProcessor) ingestFiling(ctx context.Context, item dart.List) error {
+	state, err := p.loadFilingState(ctx, item)
+	if err != nil {
+		return err
+	}
+	if state == nil {
+		return nil
+	}
+
+	current := state.State
+	if current == models.FilingStateFailed {
+		current = state.PreviousState
+	}
+
+	var rawReport *models.RawReport
+	var doc *xbrl.UsefulReport
+	if current == "" {
+		if err := p.checkDart(); err != nil {
+			return err
+		}
+		rawReport, doc, err = p.fetchFiling(ctx, item, state)
+		if err != nil {
+			return p.failFiling(ctx, state, err)
+		}
+		current = models.FilingStateFetched
+	} else {
+		r, err := gorm.G[models.RawReport](p.DB).Where("receipt_number = ?", item.RceptNo).First(ctx)
+		if err != nil {
+			return p.failFiling(ctx, state, fmt.Errorf("failed to load raw report: %w", err))
+		}
+		rawReport = &r
+	}
+
+	if current == models.FilingStateFetched {
+		doc, err = p.parseFiling(ctx, rawReport, doc, state)
+		if err != nil {
+			return p.failFiling(ctx, state, err)
+		}
+		current = models.FilingStateParsed
+	} else {
+		doc = &xbrl.UsefulReport{}
+		if err := json.Unmarshal(rawReport.JSONData, doc); err != nil {
+			return p.failFiling(ctx, state, fmt.Errorf("failed to unmarshal parsed document: %w", err))
+		}
+	}
+
+	if current == models.FilingStateParsed {
+		analysis, usedTokens, analyzer, err := p.analyzeFiling(ctx, rawReport, doc)
+		if err != nil {
+			return p.failFiling(ctx, state, err)
+		}
+
+		state.Analysis = analysis
+		state.UsedTokens = usedTokens
+		state.Analyzer = analyzer
+		if err := p.advanceFiling(ctx, state, models.FilingStateAnalyzed, nil); err != nil {
+			return p.failFiling(ctx, state, err)
+		}
+		current = models.FilingStateAnalyzed
+	}
+
+	if current == models.FilingStateAnalyzed {
+		if err := p.storeFiling(ctx, rawReport, doc, state); err != nil {
+			return p.failFiling(ctx, state, err)
+		}
+	}
+
+	log.Printf("processed raw report: %s, %s, %d, %d", rawReport.ReceiptNumber, rawReport.CorpCode, rawReport.BlobSize, state.UsedTokens)
+	return nil
+}
+
+// loadFilingState returns the state of a filing, creating it when the filing is new.
+// It returns nil when there is nothing left to do for the filing.
+func (p *TaskProcessor) loadFilingState(ctx context.Context, item dart.List) (*models.FilingState, error) {
+	state, err := gorm.G[models.FilingState](p.DB).Where("receipt_number = ?", item.RceptNo).First(ctx)
+	if err == nil {
+		switch {
+		case state.State == models.FilingStateStored:
+			log.Printf("raw report already stored: %s", item.RceptNo)
+			return nil, nil
+		case state.State == models.FilingStateFailed && state.Attempts >= maxFilingAttempts:
+			log.Printf("giving up on raw report after %d attempts: %s (%s)", state.Attempts, item.RceptNo, state.FailureReason)
+			return nil, nil
+		}
+
+		log.Printf("resuming raw report %s from state %s", item.RceptNo, state.State)
+		return &state, nil
+	}
+
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	// raw reports stored before filing states existed are complete
+	count, err := gorm.G[models.RawReport](p.DB).Where("receipt_number = ?", item.RceptNo).Count(ctx, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	if count > 0 {
+		log.Printf("raw report already exists: %s", item.RceptNo)
+		return nil, nil
+	}
+
+	state = models.FilingState{
+		ReceiptNumber: item.RceptNo,
+		CorpCode:      item.CorpCode,
+		ReportName:    item.ReportNm,
+	}
+
+	if err := gorm.G[models.FilingState](p.DB).Create(ctx, &state); err != nil {
+		return nil, err
+	}
+
+	return &state, nil
+}
+
+// advanceFiling runs the writes of a step and moves the filing to next in one transaction, so a step
+// is either done and recorded or not done at all. The state in memory only changes once it is committed.
+func (p *TaskProcessor) advanceFiling(ctx context.Context, state *models.FilingState, next string, step func(tx *gorm.DB) error) error {
+	updated := *state
+	updated.State = next
+	updated.PreviousState = ""
+	updated.FailureReason = ""
+
+	err := p.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if step != nil {
+			if err := step(tx); err != nil {
+				return err
+			}
+		}
+		return tx.Model(&updated).Select("state", "previous_state", "failure_reason", "analysis", "used_tokens", "analyzer").Updates(&updated).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	*state = updated
+	return nil
+}
+
+func (p *TaskProcessor) failFiling(ctx context.Context, state *models.FilingState, cause error) error {
+	log.Printf("failed to process raw report %s: %v", state.ReceiptNumber, cause)
+
+	if state.State != models.FilingStateFailed {
+		state.PreviousState = state.State
+	}
+	state.State = models.FilingStateFailed
+	state.FailureReason = cause.Error()
+	// the filing is not at fault when the key cannot call DART, and a document DART does not have
+	// yet is looked for again rather than given up on: the task is archived, by SkipRetry or after
+	// its last retry, and the next listing enqueues the filing again, see enqueueFiling
+	if classifyDartError(cause) != dartStop && !errors.Is(cause, dart.ErrDocumentNotFound) {
+		state.Attempts++
+	}
+
+	if err := p.DB.WithContext(ctx).Model(state).Select("state", "previous_state", "failure_reason", "attempts").Updates(state).Error; err != nil {
+		return err
+	}
+
+	return fmt.Errorf("%s: %w", state.ReceiptNumber, p.dartError(cause))
+}
+
+// isFilingDone reports whether a filing needs no further work
+func (p *TaskProcessor) isFilingDone(ctx context.Context, receiptNumber string) (bool, error) {
+	state, err := gorm.G[models.FilingState](p.DB).Where("receipt_number = ?", receiptNumber).First(ctx)
+	if err == nil {
+		return state.State == models.FilingStateStored || (state.State == models.FilingStateFailed && state.Attempts >= maxFilingAttempts), nil
+	}
+
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, err
+	}
+
+	count, err := gorm.G[models.RawReport](p.DB).Where("receipt_number = ?", receiptNumber).Count(ctx, "id")
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// fetchFiling downloads the document archive of a filing and stores its files, the filing is then fetched.
+// The main document is parsed as it is read from the archive, a document that cannot be parsed is not stored.
+func (p *TaskProcessor) fetchFiling(ctx context.Context, item dart.List, state *models.FilingState) (*models.RawReport, *xbrl.UsefulReport, error) {
+	document, err := p.dartClient.GetDocumentStream(item.RceptNo)
+	if errors.Is(err, dart.ErrDocumentNotFound) {
+		log.Printf("document not found: %s %s %s %s", item.RceptDt, item.RceptNo, item.CorpName, item.ReportNm)
+		return nil, nil, err
+	}
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get document: %w", err)
+	}
+	defer document.Close()
+
+	mainFile := document.Main()
+	if mainFile == nil {
+		return nil, nil, fmt.Errorf("document archive of %s is empty", item.RceptNo)
+	}
+
+	// the archive stays on disk, its files are read one at a time
+	blob, doc, err := p.readMainDocument(item.ReportNm, mainFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rawReport := models.RawReport{
+		ReceiptNumber: item.RceptNo,
+		ReportName:    item.ReportNm,
+		CorpCode:      item.CorpCode,
+		BlobData:      blob,
+		BlobSize:      len(blob),
+		JSONData:      json.RawMessage(`{}`),
+	}
+
+	err = p.advanceFiling(ctx, state, models.FilingStateFetched, func(tx *gorm.DB) error {
+		if err := tx.Create(&rawReport).Error; err != nil {
+			return err
+		}
+
+		// the main document is the blob of the raw report, its file only lists it
+		for _, f := range document.Files {
+			file := models.RawReportFile{
+				ReceiptNumber: item.RceptNo,
+				Filename:      f.Name,
+				Main:          f.Main,
+				BlobSize:      len(blob),
+			}
+			if !f.Main {
+				if file.BlobData, err = readDocumentFile(f); err != nil {
+					return err
+				}
+				file.BlobSize = len(file.BlobData)
+			}
+
+			if err := tx.Create(&file).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &rawReport, doc, nil
+}
+
+// readMainDocument parses the main document of a filing as it is decompressed and returns its bytes with it
+func (p *TaskProcessor) readMainDocument(reportName string, f *dart.DocumentFile) ([]byte, *xbrl.UsefulReport, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rc.Close()
+
+	var blob bytes.Buffer
+	r := io.TeeReader(rc, &blob)
+
+	doc, err := p.parsers.ParseReader(reportName, f.Name, r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse XBRL document: %w", err)
+	}
+	// the parser may stop before the end of the document
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", f.Name, err)
+	}
+	return blob.Bytes(), doc, nil
+}
+
+func readDocumentFile(f *dart.DocumentFile) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	b, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", f.Name, err)
+	}
+	return b, nil
+}
+
+// parseFiling adds the facts of the attachments to the main document of a filing and keeps its JSON, the filing is
+// then parsed. doc is nil when the filing was fetched by an earlier run, the main document is then parsed again.
+func (p *TaskProcessor) parseFiling(ctx context.Context, rawReport *models.RawReport, doc *xbrl.UsefulReport, state *models.FilingState) (*xbrl.UsefulReport, error) {
+	if doc == nil {
+		var err error
+		doc, err = p.parsers.ParseReader(rawReport.ReportName, rawReport.ReceiptNumber+".xml", bytes.NewReader(rawReport.BlobData))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse XBRL document: %w", err)
+		}
+	}
+
+	// the financial statements of a filing come as XBRL instances among its files
+	facts, err := p.parseFilingFiles(ctx, rawReport)
+	if err != nil {
+		return nil, err
+	}
+	doc.Facts = append(doc.Facts, facts...)
+
+	// jsonb does not keep the indentation, the document is marshalled compact
+	j, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	err = p.advanceFiling(ctx, state, models.FilingStateParsed, func(tx *gorm.DB) error {
+		return tx.Model(rawReport).Update("json_data", json.RawMessage(j)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rawReport.JSONData = j
+	return doc, nil
+}
+
+// parseFilingFiles parses every attachment of the archive on its own, so each keeps its own document and company
+// name. It returns the facts of the XBRL instances among them. The main document is parsed as the raw report.
+func (p *TaskProcessor) parseFilingFiles(ctx context.Context, rawReport *models.RawReport) ([]xbrl.FactValue, error) {
+	var ids []uint
+	err := p.DB.WithContext(ctx).Model(&models.RawReportFile{}).Where("receipt_number = ? AND NOT main", rawReport.ReceiptNumber).Order("id").Pluck("id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var facts []xbrl.FactValue
+	// one file in memory at a time
+	for _, id := range ids {
+		file, err := gorm.G[models.RawReportFile](p.DB).Where("id = ?", id).First(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		doc, err := p.parsers.Parse(rawReport.ReportName, file.Filename, file.BlobData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file.Filename, err)
+		}
+		facts = append(facts, doc.Facts...)
+
+		j, err := json.Marshal(doc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal JSON of %s: %w", file.Filename, err)
+		}
+
+		if err := p.DB.WithContext(ctx).Model(&file).Update("json_data", json.RawMessage(j)).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return facts, nil
+}
+
+// analyzeFiling runs the dedicated parser for the report name and falls back to OpenAI
+// when there is none or its output misses critical fields
+func (p *TaskProcessor) analyzeFiling(ctx context.Context, rawReport *models.RawReport, doc *xbrl.UsefulReport) (json.RawMessage, int64, string, error) {
+	parsed, err := dart.ParseFiling(rawReport.ReportName, string(rawReport.BlobData), rawReport.ReceiptNumber)
+	switch {
+	case err == nil:
+		if bond, ok := parsed.(*dart.BondIssuanceDoc); ok {
+			if err := p.applyDilution(ctx, rawReport, bond); err != nil {
+				log.Printf("failed to compute dilution for %s: %v", rawReport.ReceiptNumber, err)
+			}
+		}
+
+		parsedJSON, err := json.MarshalIndent(parsed, "", "  ")
+		if err != nil {
+			return nil, 0, "", fmt.Errorf("failed to marshal parsed report: %w", err)
+		}
+
+		log.Printf("parsed report without OpenAI: %s %s", rawReport.ReceiptNumber, rawReport.ReportName)
+		return parsedJSON, 0, models.AnalyzerParser, nil
+	case errors.Is(err, dart.ErrNoParser):
+	default:
+		log.Printf("falling back to OpenAI for %s %s: %v", rawReport.ReceiptNumber, rawReport.ReportName, err)
+	}
+
+	reportType := reportTypeOf(doc)
+	contents, err := promptContents(doc)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	var analysis interface{}
+	var usedTokens int64
+	if len(contents) > openai.PreviewByteLimit {
+		log.Printf("analyzing report with batch API: %s", rawReport.ReceiptNumber)
+		analysis, usedTokens, err = p.fileAnalyzer.AnalyzeReportBatch(ctx, contents, reportType)
+	} else {
+		analysis, usedTokens, err = p.fileAnalyzer.AnalyzeReport(ctx, contents, reportType)
+	}
+	if err != nil {
+		return nil, 0, "", fmt.Errorf("failed to analyze report: %w", err)
+	}
+
+	analysisJSON, err := json.MarshalIndent(analysis, "", "  ")
+	if err != nil {
+		return nil, 0, "", fmt.Errorf("failed to marshal analysis: %w", err)
+	}
+
+	return analysisJSON, usedTokens, models.AnalyzerOpenAI, nil
+}
+
+// applyDilution computes the dilution of a bond issuance against the shares outstanding before it
+func (p *TaskProcessor) applyDilution(ctx context.Context, rawReport *models.RawReport, bond *dart.BondIssuanceDoc) error {
+	if bond.BondKind == dart.BondKindExchangeable {
+		return nil
+	}
+
+	shares, err := p.outstandingShares(ctx, rawReport)
+	if err != nil || shares == nil {
+		return err
+	}
+	bond.ApplyDilution(*shares)
+
+	return nil
+}
+
+// storeFiling keeps the analysis of a filing and what it tells, the filing is then stored. The typed rows
+// are upserted and the analysis is written with the state, so storing a filing again changes nothing.
+func (p *TaskProcessor) storeFiling(ctx context.Context, rawReport *models.RawReport, doc *xbrl.UsefulReport, state *models.FilingState) error {
+	// DART may be asked for the original of a correction, it is not done in the transaction
+	original, err := p.originalOf(ctx, rawReport)
+	if err != nil {
+		return fmt.Errorf("failed to find original of correction: %w", err)
+	}
+
+	analysisJSON := state.Analysis
+
+	// filings analyzed before the analyzer was recorded went through OpenAI
+	analyzer := state.Analyzer
+	if analyzer == "" {
+		analyzer = models.AnalyzerOpenAI
+	}
+
+	switch {
+	case analyzer == models.AnalyzerParser:
+		// typed parser output is stored as is
+		switch name, _ := dart.ParserFor(rawReport.ReportName); name {
+		case "ownership_change":
+			if err := p.storeInsiderTransactions(ctx, rawReport, state.Analysis); err != nil {
+				return fmt.Errorf("failed to store insider transactions: %w", err)
+			}
+		case "treasury_stock":
+			if err := p.storeTreasuryStockEvent(ctx, rawReport, original, state.Analysis); err != nil {
+				return fmt.Errorf("failed to store treasury stock event: %w", err)
+			}
+		}
+	case reportTypeOf(doc) != "report":
+		var v openai.DefaultReport
+		if err := json.Unmarshal(state.Analysis, &v); err != nil {
+			return fmt.Errorf("failed to unmarshal analysis: %w", err)
+		}
+
+		if v.CompanyName == "" {
+			company, err := gorm.G[models.Company](p.DB).Where("corp_code = ?", rawReport.CorpCode).First(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to get company: %w", err)
+			}
+			v.CompanyName = company.CorpName
+		}
+
+		if v.SchemaSuggestion != "" {
+			structureJSON, err := json.Marshal(v.SchemaSuggestion)
+			if err != nil {
+				return fmt.Errorf("failed to marshal schema suggestion: %w", err)
+			}
+
+			rt := models.ReportType{
+				Name:              v.Type,
+				Structure:         json.RawMessage(structureJSON),
+				SourceRawReportID: rawReport.ID,
+			}
+
+			// a filing stored again suggested its report type the first time
+			count, err := gorm.G[models.ReportType](p.DB).Where("source_raw_report_id = ? AND name = ?", rawReport.ID, v.Type).Count(ctx, "id")
+			if err != nil {
+				return err
+			}
+			if count == 0 {
+				if err := gorm.G[models.ReportType](p.DB).Create(ctx, &rt); err != nil {
+					return err
+				}
+			}
+
+			v.SchemaSuggestion = ""
+		}
+
+		j, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal analysis: %w", err)
+		}
+		analysisJSON = j
+	default:
+		j, err := p.storeReportFacts(ctx, rawReport, doc, state.Analysis)
+		if err != nil {
+			return err
+		}
+		analysisJSON = j
+	}
+
+	analysis := models.Analysis{
+		RawReportID: rawReport.ID,
+		UsedTokens:  state.UsedTokens,
+		Analyzer:    analyzer,
+		Analysis:    analysisJSON,
+	}
+
+	return p.advanceFiling(ctx, state, models.FilingStateStored, func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "raw_report_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"used_tokens", "analyzer", "analysis", "updated_at"}),
+		}).Create(&analysis).Error
+		if err != nil {
+			return err
+		}
+		return p.linkCorrections(tx, rawReport, original, analysisJSON)
+	})
+}
+
+// promptContents is the parsed document sent to OpenAI. The section tree repeats its tables
+// and paragraphs, so it is left out, as are the XBRL facts, too many for a prompt.
+func promptContents(doc *xbrl.UsefulReport) (string, error) {
+	flat := *doc
+	flat.Sections = nil
+	flat.Facts = nil
+
+	j, err := json.MarshalIndent(flat, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal prompt contents: %w", err)
+	}
+	return string(j), nil
+}
+
+// reportTypeOf returns the prompt type for a parsed document
+func reportTypeOf(doc *xbrl.UsefulReport) string {
+	if strings.Contains(doc.ReportTitle, "분기보고서") || strings.Contains(doc.ReportTitle, "사업보고서") || strings.Contains(doc.ReportTitle, "반기보고서") {
+		return "report"
+	}
+
+	log.Printf("unknown report type: %s", doc.ReportTitle)
+	return ""
+}
@@ -0,0 +1,53 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"kosis/internal/models"
+	"kosis/internal/pkg/dart"
+
+	"gorm.io/gorm/clause"
+)
+
+// storeInsiderTransactions stores the holder changes of a parsed ownership report
+func (p *TaskProcessor) storeInsiderTransactions(ctx context.Context, rawReport *models.RawReport, analysis json.RawMessage) error {
+	var doc dart.OwnershipChangeDoc
+	if err := json.Unmarshal(analysis, &doc); err != nil {
+		return fmt.Errorf("failed to unmarshal ownership change: %w", err)
+	}
+
+	transactions := []models.InsiderTransaction{}
+	for i, c := range doc.Changes {
+		if c.Date == nil || c.SharesChange == nil {
+			continue
+		}
+
+		transactions = append(transactions, models.InsiderTransaction{
+			CorpCode:      rawReport.CorpCode,
+			ReceiptNumber: rawReport.ReceiptNumber,
+			Seq:           i,
+			HolderName:    c.Name,
+			Relation:      c.Relation,
+			ChangeDate:    *c.Date,
+			Reason:        c.Reason,
+			ShareKind:     c.ShareKind,
+			SharesBefore:  c.SharesBefore,
+			SharesChange:  *c.SharesChange,
+			SharesAfter:   c.SharesAfter,
+			Price:         c.Price,
+		})
+	}
+
+	if len(transactions) == 0 {
+		return nil
+	}
+
+	return p.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "receipt_number"}, {Name: "seq"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"holder_name", "relation", "change_date", "reason", "share_kind",
+			"shares_before", "shares_change", "shares_after", "price", "updated_at",
+		}),
+	}).Create(&transactions).Error
+}
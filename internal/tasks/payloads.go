@@ -2,6 +2,8 @@ package tasks
 
 import (
 	"encoding/json"
+	"kosis/internal/pkg/dart"
+	"time"
 
 	"github.com/hibiken/asynq"
 )
@@ -10,17 +12,44 @@ import (
 
 // Task type names
 const (
-	TypeTaskAnalyzeReport  = "task:analyze_report"
-	TypeTaskFetchReports   = "task:fetch_reports"
-	TypeTaskFetchCompanies = "task:fetch_companies"
+	TypeTaskAnalyzeReport   = "task:analyze_report"
+	TypeTaskFetchReports    = "task:fetch_reports"
+	TypeTaskFetchCompanies  = "task:fetch_companies"
+	TypeTaskFetchFinancials = "task:fetch_financials"
+	TypeTaskFetchDividends  = "task:fetch_dividends"
 )
 
 // --- FetchFinancials Task ---
 
 // FetchFinancialsPayload is the data a job needs to run
 type FetchReportsPayload struct {
-	CorpCode *string `json:"corp_code"`
-	Limit    *int    `json:"limit"`
+	CorpCode  *string `json:"corp_code"`
+	StartDate *string `json:"start_date"` // YYYY-MM-DD
+	EndDate   *string `json:"end_date"`   // YYYY-MM-DD
+	Limit     *int    `json:"limit"`
+}
+
+// AnalyzeReportPayload is the data a job needs to run
+type AnalyzeReportPayload struct {
+	RceptNo  string `json:"rcept_no"`
+	CorpCode string `json:"corp_code"`
+	CorpName string `json:"corp_name"`
+	ReportNm string `json:"report_nm"`
+	RceptDt  string `json:"rcept_dt"`
+}
+
+// FetchFinancialsPayload is the data a job needs to run
+type FetchFinancialsPayload struct {
+	CorpCode  string          `json:"corp_code"`
+	BsnsYear  string          `json:"bsns_year"`
+	ReprtCode dart.ReportType `json:"reprt_code"`
+}
+
+// FetchDividendsPayload is the data a job needs to run
+type FetchDividendsPayload struct {
+	CorpCode  *string          `json:"corp_code"`  // all listed companies when missing
+	BsnsYear  *string          `json:"bsns_year"`  // the year of the latest report of the kind when missing
+	ReprtCode *dart.ReportType `json:"reprt_code"` // the business report when missing
 }
 
 // FetchCompaniesPayload is the data a job needs to run
@@ -42,6 +71,85 @@ func NewFetchReportsTask(corpCode *string, limit *int) (*asynq.Task, error) {
 	return asynq.NewTask(TypeTaskFetchReports, payloadBytes), nil
 }
 
+// NewBackfillReportsTask creates a fetch reports task for an explicit date range,
+// optionally narrowed to a single company
+func NewBackfillReportsTask(corpCode *string, startDate, endDate time.Time, limit *int) (*asynq.Task, error) {
+	start := startDate.Format("2006-01-02")
+	end := endDate.Format("2006-01-02")
+	payload := FetchReportsPayload{
+		CorpCode:  corpCode,
+		StartDate: &start,
+		EndDate:   &end,
+		Limit:     limit,
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(TypeTaskFetchReports, payloadBytes), nil
+}
+
+// NewAnalyzeReportTask creates a new task for asynq that fetches and analyzes a single filing.
+// It is enqueued with the receipt number as its ID, see enqueueFiling.
+func NewAnalyzeReportTask(item dart.List) (*asynq.Task, error) {
+	payload := AnalyzeReportPayload{
+		RceptNo:  item.RceptNo,
+		CorpCode: item.CorpCode,
+		CorpName: item.CorpName,
+		ReportNm: item.ReportNm,
+		RceptDt:  item.RceptDt,
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(
+		TypeTaskAnalyzeReport,
+		payloadBytes,
+		asynq.MaxRetry(maxFilingAttempts),
+		// large reports go through the batch API, which can take hours
+		asynq.Timeout(6*time.Hour),
+	), nil
+}
+
+// NewFetchFinancialsTask creates a new task for asynq that stores the DART financial
+// statements of a company's periodic report
+func NewFetchFinancialsTask(corpCode, bsnsYear string, reportCode dart.ReportType) (*asynq.Task, error) {
+	payload := FetchFinancialsPayload{
+		CorpCode:  corpCode,
+		BsnsYear:  bsnsYear,
+		ReprtCode: reportCode,
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(TypeTaskFetchFinancials, payloadBytes, asynq.Unique(24*time.Hour)), nil
+}
+
+// NewFetchDividendsTask creates a new task for asynq that stores the dividends of a periodic
+// report of a company, or of every listed company when corpCode is nil
+func NewFetchDividendsTask(corpCode *string, bsnsYear *string, reportCode *dart.ReportType) (*asynq.Task, error) {
+	payload := FetchDividendsPayload{
+		CorpCode:  corpCode,
+		BsnsYear:  bsnsYear,
+		ReprtCode: reportCode,
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(TypeTaskFetchDividends, payloadBytes, asynq.Unique(24*time.Hour)), nil
+}
+
 // NewFetchCompaniesTask creates a new task for asynq
 func NewFetchCompaniesTask() (*asynq.Task, error) {
 	payload := FetchCompaniesPayload{}
@@ -51,5 +159,6 @@ func NewFetchCompaniesTask() (*asynq.Task, error) {
 		return nil, err
 	}
 
-	return asynq.NewTask(TypeTaskFetchCompanies, payloadBytes), nil
+	// keep the FetchCompaniesResult around for inspection
+	return asynq.NewTask(TypeTaskFetchCompanies, payloadBytes, asynq.Retention(7*24*time.Hour)), nil
 }
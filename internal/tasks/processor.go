@@ -3,36 +3,65 @@ package tasks
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"kosis/internal/config"
-	"kosis/internal/models"
 	"kosis/internal/pkg/dart"
 	"kosis/internal/pkg/openai"
 	"kosis/internal/pkg/xbrl"
 	"log"
-	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/hibiken/asynq"
 	"gorm.io/gorm"
 )
 
+// Enqueuer submits follow-up tasks and looks up the ones already submitted, AsynqEnqueuer satisfies it
+type Enqueuer interface {
+	EnqueueContext(ctx context.Context, task *asynq.Task, opts ...asynq.Option) (*asynq.TaskInfo, error)
+	GetTaskInfo(queue, id string) (*asynq.TaskInfo, error)
+	DeleteTask(queue, id string) error
+}
+
+// AsynqEnqueuer enqueues with an asynq client and looks tasks up with an inspector of the same Redis
+type AsynqEnqueuer struct {
+	*asynq.Client
+	*asynq.Inspector
+}
+
+// taskQueue is the queue follow-up tasks are enqueued to
+const taskQueue = "default"
+
 // TaskProcessor holds dependencies for our task handlers
 type TaskProcessor struct {
 	DB           *gorm.DB
 	config       *config.Config
 	dartClient   *dart.DartClient
 	fileAnalyzer *openai.FileAnalyzer
+	enqueuer     Enqueuer
+	parsers      *xbrl.Parsers            // the parsing strategy of each document type
+	halt         atomic.Pointer[dartHalt] // set when DART answered with a stopping error
 }
 
-// NewTaskProcessor creates a new TaskProcessor
-func NewTaskProcessor(db *gorm.DB, config *config.Config) *TaskProcessor {
+// NewTaskProcessor creates a new TaskProcessor, it fails when XBRL_PARSERS is invalid
+func NewTaskProcessor(db *gorm.DB, config *config.Config, enqueuer Enqueuer) (*TaskProcessor, error) {
+	parsers, err := xbrl.NewParsers(config.XBRLParsers)
+	if err != nil {
+		return nil, fmt.Errorf("invalid XBRL_PARSERS: %w", err)
+	}
+
+	dartClient := dart.New(config.DartAPIKey)
+	dartClient.UseQuota(&DartQuotaStore{DB: db}, dart.DefaultDailyLimit)
+
 	return &TaskProcessor{
 		DB:           db,
 		config:       config,
-		dartClient:   dart.New(config.DartAPIKey),
+		dartClient:   dartClient,
 		fileAnalyzer: openai.NewFileAnalyzer(config.OpenAIAPIKey),
-	}
+		enqueuer:     enqueuer,
+		parsers:      parsers,
+	}, nil
 }
 
 func (p *TaskProcessor) HandleFetchReportsTask(ctx context.Context, t *asynq.Task) error {
@@ -45,154 +74,160 @@ func (p *TaskProcessor) HandleFetchReportsTask(ctx context.Context, t *asynq.Tas
 
 	log.Printf("Fetching reports for %+v", payload)
 
-	rawReports, err := p.dartClient.GetRecentRawReports()
+	if err := p.checkDart(); err != nil {
+		return err
+	}
+
+	rawReports, err := p.listReports(payload)
+	if err == errInvalidPayload {
+		return fmt.Errorf("invalid payload %+v: %w", payload, asynq.SkipRetry)
+	}
 	if err != nil {
+		// the next run lists the same filings again, only a stopping error is worth keeping
 		log.Printf("failed to fetch reports: %v", err)
+		p.haltDart(err)
 		return nil
 	}
 
-	totalUsedTokens := int64(0)
-	var analyses []models.Analysis
+	enqueued := 0
 	for _, rawReport := range rawReports {
-		count, err := gorm.G[models.RawReport](p.DB).Where("receipt_number = ?", rawReport.RceptNo).Count(ctx, "id")
+		done, err := p.isFilingDone(ctx, rawReport.RceptNo)
 		if err != nil {
 			return err
 		}
-
-		if count > 0 {
-			log.Printf("raw report already exists: %s", rawReport.RceptNo)
-			continue
-		}
-
-		rawDocument, err := p.dartClient.GetDocument(rawReport.RceptNo)
-		if err == dart.ErrDocumentNotFound {
-			log.Printf("document not found: %s %s %s %s", rawReport.RceptDt, rawReport.RceptNo, rawReport.CorpName, rawReport.ReportNm)
+		if done {
 			continue
 		}
 
+		ok, err := p.enqueueFiling(ctx, rawReport)
 		if err != nil {
-			log.Printf("failed to get document: %v", err)
 			return err
 		}
-
-		doc, err := xbrl.ParseXBRL([]byte(rawDocument))
-		if err != nil {
-			log.Printf("failed to parse XBRL document: %v", err)
-			return err
+		if ok {
+			enqueued++
 		}
+	}
 
-		j, err := json.MarshalIndent(doc, "", "  ")
-		if err != nil {
-			log.Printf("failed to marshal JSON: %v", err)
-			return err
-		}
+	log.Printf("Reports fetched successfully, enqueued %d of %d", enqueued, len(rawReports))
+	return nil
+}
 
-		rawReport := models.RawReport{
-			ReceiptNumber: rawReport.RceptNo,
-			ReportName:    rawReport.ReportNm,
-			CorpCode:      rawReport.CorpCode,
-			BlobData:      []byte(rawDocument),
-			BlobSize:      len(rawDocument),
-			JSONData:      j,
-		}
+// enqueueFiling enqueues the analyze report task of a filing and reports whether it did. The task is identified
+// by the receipt number, so a listing row renamed or corrected later does not make a second task for the filing
+// while the first one is queued or retried. asynq keeps the ID of an archived task, after SkipRetry or its last
+// retry, for as long as it keeps the task: that task is deleted so the filing is taken up again.
+func (p *TaskProcessor) enqueueFiling(ctx context.Context, item dart.List) (bool, error) {
+	task, err := NewAnalyzeReportTask(item)
+	if err != nil {
+		return false, err
+	}
 
-		result := gorm.WithResult()
-		err = gorm.G[models.RawReport](p.DB, result).Create(ctx, &rawReport)
-		if err != nil {
-			return err
+	_, err = p.enqueuer.EnqueueContext(ctx, task, asynq.TaskID(item.RceptNo))
+	if errors.Is(err, asynq.ErrTaskIDConflict) {
+		info, lookupErr := p.enqueuer.GetTaskInfo(taskQueue, item.RceptNo)
+		switch {
+		case errors.Is(lookupErr, asynq.ErrTaskNotFound):
+			// the task finished in between
+		case lookupErr != nil:
+			return false, fmt.Errorf("failed to look up analyze report task: %w", lookupErr)
+		case info.State != asynq.TaskStateArchived:
+			log.Printf("analyze report task already queued: %s", item.RceptNo)
+			return false, nil
+		default:
+			if err := p.enqueuer.DeleteTask(taskQueue, item.RceptNo); err != nil && !errors.Is(err, asynq.ErrTaskNotFound) {
+				return false, fmt.Errorf("failed to delete archived analyze report task: %w", err)
+			}
+			log.Printf("analyze report task archived, enqueued again: %s", item.RceptNo)
 		}
+		_, err = p.enqueuer.EnqueueContext(ctx, task, asynq.TaskID(item.RceptNo))
+	}
+	if errors.Is(err, asynq.ErrTaskIDConflict) {
+		log.Printf("analyze report task already queued: %s", item.RceptNo)
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to enqueue analyze report task: %w", err)
+	}
+	return true, nil
+}
 
-		reportType := ""
-		if strings.Contains(doc.ReportTitle, "분기보고서") || strings.Contains(doc.ReportTitle, "사업보고서") || strings.Contains(doc.ReportTitle, "반기보고서") {
-			reportType = "report"
-		} else {
-			log.Printf("unknown report type: %s", doc.ReportTitle)
-		}
+// HandleAnalyzeReportTask fetches, parses, analyzes and stores a single filing
+func (p *TaskProcessor) HandleAnalyzeReportTask(ctx context.Context, t *asynq.Task) error {
+	var payload AnalyzeReportPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
 
-		reportLength := len(j)
-		var analysis interface{}
-		var usedTokens int64
-		if reportLength > openai.PreviewByteLimit {
-			log.Printf("analyzing report with batch API: %s", rawReport.ReceiptNumber)
-			analysis, usedTokens, err = p.fileAnalyzer.AnalyzeReportBatch(ctx, string(j), reportType)
-			if err != nil {
-				log.Printf("failed to analyze report: %v", err)
-				continue
-			}
-		} else {
-			analysis, usedTokens, err = p.fileAnalyzer.AnalyzeReport(ctx, string(j), reportType)
-			if err != nil {
-				log.Printf("failed to analyze report: %v", err)
-				continue
-			}
-		}
+	if payload.RceptNo == "" {
+		return fmt.Errorf("rcept_no is required: %w", asynq.SkipRetry)
+	}
 
-		if v, ok := analysis.(*openai.DefaultReport); ok {
-			if v.CompanyName == "" {
-				company, err := gorm.G[models.Company](p.DB).Where("corp_code = ?", rawReport.CorpCode).First(ctx)
-				if err != nil {
-					log.Printf("failed to get company: %v", err)
-					continue
-				}
-				v.CompanyName = company.CorpName
-			}
+	log.Printf("Analyzing report %s %s %s", payload.RceptNo, payload.CorpName, payload.ReportNm)
 
-			if v.SchemaSuggestion != "" {
-				structureJSON, err := json.Marshal(v.SchemaSuggestion)
-				if err != nil {
-					log.Printf("failed to marshal schema suggestion: %v", err)
-					continue
-				}
-
-				rt := models.ReportType{
-					Name:              v.Type,
-					Structure:         json.RawMessage(structureJSON),
-					SourceRawReportID: rawReport.ID,
-				}
-
-				result := gorm.WithResult()
-				err = gorm.G[models.ReportType](p.DB, result).Create(ctx, &rt)
-				if err != nil {
-					return err
-				}
-
-				v.SchemaSuggestion = ""
-				analysis = v
-			}
-		}
+	err := p.ingestFiling(ctx, dart.List{
+		RceptNo:  payload.RceptNo,
+		CorpCode: payload.CorpCode,
+		CorpName: payload.CorpName,
+		ReportNm: payload.ReportNm,
+		RceptDt:  payload.RceptDt,
+	})
+	if err != nil {
+		return err
+	}
 
-		analysisJSON, err := json.MarshalIndent(analysis, "", "  ")
-		if err != nil {
-			log.Printf("failed to marshal analysis: %v", err)
-			continue
-		}
+	// the analysis may misread figures, DART publishes the periodic ones as structured data
+	if err := p.enqueueFetchFinancials(ctx, payload.CorpCode, payload.ReportNm); err != nil {
+		log.Printf("failed to enqueue fetch financials for %s: %v", payload.RceptNo, err)
+	}
 
-		analyses = append(analyses, models.Analysis{
-			RawReportID: rawReport.ID,
-			UsedTokens:  usedTokens,
-			Analysis:    analysisJSON,
-		})
+	return nil
+}
 
-		log.Printf("processed raw report: %s, %s, %d, %d", rawReport.ReceiptNumber, rawReport.CorpCode, rawReport.BlobSize, usedTokens)
+var errInvalidPayload = errors.New("invalid payload")
 
-		totalUsedTokens += usedTokens
+// listReports returns the filings selected by the payload. Without a corp code,
+// a date range or a limit it falls back to the filings of the last five days.
+func (p *TaskProcessor) listReports(payload FetchReportsPayload) ([]dart.List, error) {
+	if payload.CorpCode == nil && payload.StartDate == nil && payload.EndDate == nil && payload.Limit == nil {
+		return p.dartClient.GetRecentRawReports()
+	}
 
-		log.Printf("total used tokens: %d", totalUsedTokens)
+	endDate := time.Now()
+	if payload.EndDate != nil {
+		d, err := time.Parse("2006-01-02", *payload.EndDate)
+		if err != nil {
+			log.Printf("failed to parse end date: %v", err)
+			return nil, errInvalidPayload
+		}
+		endDate = d
 	}
 
-	if len(analyses) > 0 {
-		log.Printf("storing %d analyses in batch", len(analyses))
-		result := gorm.WithResult()
-		err := gorm.G[models.Analysis](p.DB, result).CreateInBatches(ctx, &analyses, len(analyses))
+	startDate := endDate.AddDate(0, 0, -5)
+	if payload.StartDate != nil {
+		d, err := time.Parse("2006-01-02", *payload.StartDate)
 		if err != nil {
-			log.Printf("failed to store analyses in batch: %v", err)
-			return err
+			log.Printf("failed to parse start date: %v", err)
+			return nil, errInvalidPayload
 		}
-		log.Printf("successfully stored %d analyses in batch", len(analyses))
+		startDate = d
 	}
 
-	log.Println("Reports fetched successfully")
-	return nil
+	if endDate.Before(startDate) {
+		log.Printf("end date %s is before start date %s", endDate.Format("2006-01-02"), startDate.Format("2006-01-02"))
+		return nil, errInvalidPayload
+	}
+
+	corpCode := ""
+	if payload.CorpCode != nil {
+		corpCode = *payload.CorpCode
+	}
+
+	limit := 0
+	if payload.Limit != nil {
+		limit = *payload.Limit
+	}
+
+	return p.dartClient.GetAllRawReports(corpCode, startDate, endDate, limit)
 }
 
 func (p *TaskProcessor) HandleFetchCompaniesTask(ctx context.Context, t *asynq.Task) error {
@@ -203,55 +238,25 @@ func (p *TaskProcessor) HandleFetchCompaniesTask(ctx context.Context, t *asynq.T
 		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
 	}
 
-	log.Printf("Fetching companies")
-
-	companies, err := p.dartClient.GetCompanies()
-	if err != nil {
-		log.Printf("failed to fetch companies: %v", err)
+	if err := p.checkDart(); err != nil {
 		return err
 	}
 
-	log.Printf("fetched %d companies", len(companies))
-
-	for _, company := range companies {
-		existingCompany, err := gorm.G[models.Company](p.DB).Where("corp_code = ?", company.CorpCode).First(ctx)
-		if err == gorm.ErrRecordNotFound {
-			lastModifiedDate, err := time.Parse("20060102", company.ModifyDate)
-			if err != nil {
-				log.Printf("failed to parse last modified date: %v", err)
-				return err
-			}
-			company := models.Company{
-				CorpCode:         company.CorpCode,
-				CorpName:         company.CorpName,
-				CorpEngName:      company.CorpEngName,
-				LastModifiedDate: lastModifiedDate,
-			}
-
-			result := gorm.WithResult()
-			err = gorm.G[models.Company](p.DB, result).Create(ctx, &company)
-			if err != nil {
-				return err
-			}
-			continue
-		}
-
-		if err != nil {
-			return err
-		}
+	result, err := p.syncCompanies(ctx)
+	if err != nil {
+		log.Printf("failed to sync companies: %v", err)
+		return p.dartError(err)
+	}
 
-		log.Printf("existingCompany: %+v", existingCompany)
+	log.Printf("synced companies: %d inserted, %d updated, %d unchanged, %d refreshed from company.json", result.Inserted, result.Updated, result.Unchanged, result.InfoUpdated)
 
-		existingCompany.CorpName = company.CorpName
-		existingCompany.CorpEngName = company.CorpEngName
-		existingCompany.LastModifiedDate, err = time.Parse("20060102", company.ModifyDate)
+	// tasks built by hand, e.g. in tests, have no result writer
+	if w := t.ResultWriter(); w != nil {
+		res, err := json.Marshal(result)
 		if err != nil {
 			return err
 		}
-
-		result := gorm.WithResult()
-		_, err = gorm.G[models.Company](p.DB, result).Updates(ctx, existingCompany)
-		if err != nil {
+		if _, err := w.Write(res); err != nil {
 			return err
 		}
 	}
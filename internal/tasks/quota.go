@@ -0,0 +1,45 @@
+package tasks
+
+import (
+	"context"
+	"kosis/internal/pkg/dart"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"gorm.io/gorm"
+)
+
+// DartQuotaStore keeps the daily DART call counter in dart_quotas
+type DartQuotaStore struct {
+	DB *gorm.DB
+}
+
+func (s *DartQuotaStore) Use(ctx context.Context, day string, limit int) error {
+	result := s.DB.WithContext(ctx).Exec(`INSERT INTO dart_quotas (day, calls) VALUES (?, 1)
+		ON CONFLICT (day) DO UPDATE SET calls = dart_quotas.calls + 1, updated_at = now()
+		WHERE NOT dart_quotas.exhausted AND dart_quotas.calls < ?`, day, limit)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return dart.ErrQuotaExceeded
+	}
+	return nil
+}
+
+func (s *DartQuotaStore) Exhaust(ctx context.Context, day string) error {
+	return s.DB.WithContext(ctx).Exec(`INSERT INTO dart_quotas (day, exhausted) VALUES (?, true)
+		ON CONFLICT (day) DO UPDATE SET exhausted = true, updated_at = now()`, day).Error
+}
+
+// RetryDelay is the asynq RetryDelayFunc of the worker. Tasks that ran out of DART quota,
+// or whose key DART refused, wait for the quota to reset rather than retry within the day.
+// Each such retry still counts against MaxRetry: an analyze report task archived that way
+// is enqueued again by the next listing, see enqueueFiling.
+func RetryDelay(n int, err error, t *asynq.Task) time.Duration {
+	if classifyDartError(err) == dartStop {
+		now := time.Now()
+		return dart.NextQuotaReset(now).Sub(now)
+	}
+	return asynq.DefaultRetryDelayFunc(n, err, t)
+}
@@ -0,0 +1,79 @@
+package tasks_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kosis/internal/config"
+	"kosis/internal/db"
+	"kosis/internal/models"
+	"kosis/internal/pkg/dart"
+	"kosis/internal/tasks"
+	"kosis/internal/testhelpers"
+	"time"
+
+	"github.com/hibiken/asynq"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("DartQuotaStore", func() {
+	var dbConn *gorm.DB
+	var store *tasks.DartQuotaStore
+
+	BeforeEach(func() {
+		cfg, err := config.LoadConfig()
+		Expect(err).NotTo(HaveOccurred())
+
+		dbConn, err = db.InitDB(cfg.DatabaseURL)
+		Expect(err).NotTo(HaveOccurred())
+
+		testhelpers.CleanupDB(dbConn)
+
+		store = &tasks.DartQuotaStore{DB: dbConn}
+	})
+
+	It("counts calls until the daily limit", func() {
+		ctx := context.Background()
+		Expect(store.Use(ctx, "2025-07-01", 2)).To(Succeed())
+		Expect(store.Use(ctx, "2025-07-01", 2)).To(Succeed())
+		Expect(store.Use(ctx, "2025-07-01", 2)).To(MatchError(dart.ErrQuotaExceeded))
+
+		// a new day starts over
+		Expect(store.Use(ctx, "2025-07-02", 2)).To(Succeed())
+
+		quota, err := gorm.G[models.DartQuota](dbConn).Where("day = ?", "2025-07-01").First(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(quota.Calls).To(Equal(2))
+	})
+
+	It("refuses calls on an exhausted day", func() {
+		ctx := context.Background()
+		Expect(store.Use(ctx, "2025-07-01", 100)).To(Succeed())
+		Expect(store.Exhaust(ctx, "2025-07-01")).To(Succeed())
+		Expect(store.Use(ctx, "2025-07-01", 100)).To(MatchError(dart.ErrQuotaExceeded))
+	})
+})
+
+var _ = Describe("RetryDelay", func() {
+	It("waits for the quota to reset", func() {
+		delay := tasks.RetryDelay(1, dart.ErrQuotaExceeded, asynq.NewTask("test", nil))
+		Expect(delay).To(BeNumerically(">", 0))
+		Expect(delay).To(BeNumerically("<=", 24*time.Hour))
+	})
+
+	It("waits for the quota to reset when DART refuses the key", func() {
+		for _, status := range []string{dart.StatusUnregisteredKey, dart.StatusForbiddenIP, dart.StatusAccountExpired} {
+			err := fmt.Errorf("failed to get document: %w", &dart.DartError{Status: status, Message: "사용할 수 없는 키입니다."})
+			delay := tasks.RetryDelay(1, err, asynq.NewTask("test", nil))
+			Expect(delay).To(BeNumerically(">", 0), status)
+			Expect(delay).To(BeNumerically("<=", 24*time.Hour), status)
+		}
+	})
+
+	It("falls back to the default delay", func() {
+		delay := tasks.RetryDelay(1, errors.New("boom"), asynq.NewTask("test", nil))
+		Expect(delay).To(BeNumerically("<", 2*time.Minute))
+	})
+})
@@ -0,0 +1,204 @@
+package tasks
+
+import (
+	"encoding/json"
+	"fmt"
+	"kosis/internal/models"
+	"kosis/internal/pkg/openai"
+	"kosis/internal/pkg/xbrl"
+	"log"
+	"math"
+	"time"
+)
+
+// tableAccounts keeps one amount per account and period of the accounts recognized in the tables of a report:
+// the statements over 요약재무정보, which is rounded, and cumulative income over the quarter alone,
+// which is only kept for a first quarter, when both are the same
+func tableAccounts(values []xbrl.AccountValue, fiscalEnd time.Month) []xbrl.AccountValue {
+	type key struct{ scope, account, end string }
+	rank := func(v xbrl.AccountValue) int {
+		r := 0
+		if v.Summary {
+			r += 2
+		}
+		if v.ThreeMonths {
+			r++
+		}
+		return r
+	}
+
+	best := map[key]int{} // index in out
+	var out []xbrl.AccountValue
+	for _, v := range values {
+		end, err := time.Parse("2006-01-02", v.PeriodEnd)
+		if err != nil || v.ThreeMonths && periodTypeOfMonth(end.Month(), fiscalEnd) != models.PeriodTypeQ1 {
+			continue
+		}
+
+		k := key{v.Scope, v.Account, v.PeriodEnd}
+		i, ok := best[k]
+		switch {
+		case !ok:
+			best[k] = len(out)
+			out = append(out, v)
+		case rank(v) < rank(out[i]):
+			out[i] = v
+		}
+	}
+	return out
+}
+
+// factsFromTables converts the accounts recognized in the tables of a report to financial facts in KRW
+func factsFromTables(corpCode, receiptNumber string, accounts []xbrl.AccountValue, fiscalEnd time.Month) []models.FinancialFact {
+	facts := []models.FinancialFact{}
+	for _, a := range accounts {
+		end, err := time.Parse("2006-01-02", a.PeriodEnd)
+		if err != nil {
+			continue
+		}
+
+		statement := models.StatementBalanceSheet
+		if a.Statement == xbrl.StatementIncomeStatement {
+			statement = models.StatementIncomeStatement
+		}
+
+		facts = append(facts, models.FinancialFact{
+			CorpCode:      corpCode,
+			PeriodEnd:     end,
+			PeriodType:    periodTypeOfMonth(end.Month(), fiscalEnd),
+			Statement:     statement,
+			Account:       a.Account,
+			Consolidated:  a.Scope == xbrl.ScopeConsolidated,
+			Value:         int64(math.Round(a.Value)),
+			Unit:          "KRW",
+			ReceiptNumber: receiptNumber,
+			Source:        models.FinancialFactSourceReportTable,
+		})
+	}
+	return facts
+}
+
+// applyTableAccounts sets the statements of an analysis to the amounts read from the tables of the report.
+// The amounts the analysis had are checked against them, a disagreement is logged.
+func applyTableAccounts(report *openai.Report, accounts []xbrl.AccountValue, receiptNumber string, fiscalEnd time.Month) {
+	for _, a := range accounts {
+		end, err := time.Parse("2006-01-02", a.PeriodEnd)
+		if err != nil {
+			continue
+		}
+		key := tablePeriodKey(a.Statement, end, fiscalEnd)
+		value := int64(math.Round(a.Value / millionKRW))
+
+		set := func(fields map[string]*int64) {
+			field, ok := fields[a.Account]
+			if !ok {
+				return
+			}
+			// the analysis rounds to million KRW on its own
+			if *field != 0 && math.Abs(float64(*field-value)) > 1 {
+				log.Printf("analysis of %s disagrees with its tables on %s %s %s: %d, tables say %d", receiptNumber, a.Scope, key, a.Account, *field, value)
+			}
+			*field = value
+		}
+
+		consolidated := a.Scope == xbrl.ScopeConsolidated
+		switch {
+		case consolidated && a.Statement == xbrl.StatementBalanceSheet:
+			if report.Consolidated.BalanceSheet == nil {
+				report.Consolidated.BalanceSheet = map[string]openai.BS{}
+			}
+			bs := report.Consolidated.BalanceSheet[key]
+			set(map[string]*int64{
+				xbrl.AccountTotalAssets:             &bs.TotalAssets,
+				xbrl.AccountTotalLiabilities:        &bs.TotalLiabilities,
+				xbrl.AccountTotalEquity:             &bs.TotalEquity,
+				xbrl.AccountEquityOwners:            &bs.EquityOwners,
+				xbrl.AccountNonControllingInterests: &bs.NonControllingInterests,
+				xbrl.AccountCapital:                 &bs.Capital,
+			})
+			report.Consolidated.BalanceSheet[key] = bs
+		case consolidated:
+			if report.Consolidated.IncomeStatement == nil {
+				report.Consolidated.IncomeStatement = map[string]openai.IS{}
+			}
+			is := report.Consolidated.IncomeStatement[key]
+			set(map[string]*int64{
+				xbrl.AccountSales:           &is.Sales,
+				xbrl.AccountOperatingIncome: &is.OperatingIncome,
+				xbrl.AccountNetIncome:       &is.NetIncome,
+				xbrl.AccountOwnersNetIncome: &is.OwnersNetIncome,
+			})
+			report.Consolidated.IncomeStatement[key] = is
+		case a.Statement == xbrl.StatementBalanceSheet:
+			if report.Separate.BalanceSheet == nil {
+				report.Separate.BalanceSheet = map[string]openai.BS2{}
+			}
+			bs := report.Separate.BalanceSheet[key]
+			set(map[string]*int64{
+				xbrl.AccountTotalAssets:      &bs.TotalAssets,
+				xbrl.AccountTotalLiabilities: &bs.TotalLiabilities,
+				xbrl.AccountTotalEquity:      &bs.TotalEquity,
+				xbrl.AccountCapital:          &bs.Capital,
+			})
+			report.Separate.BalanceSheet[key] = bs
+		default:
+			if report.Separate.IncomeStatement == nil {
+				report.Separate.IncomeStatement = map[string]openai.IS2{}
+			}
+			is := report.Separate.IncomeStatement[key]
+			set(map[string]*int64{
+				xbrl.AccountSales:           &is.Sales,
+				xbrl.AccountOperatingIncome: &is.OperatingIncome,
+				xbrl.AccountNetIncome:       &is.NetIncome,
+			})
+			report.Separate.IncomeStatement[key] = is
+		}
+	}
+}
+
+// tablePeriodKey returns the analysis period key of an account, see parsePeriodKey
+func tablePeriodKey(statement string, end time.Time, fiscalEnd time.Month) string {
+	// income is keyed by the period it covers when it ends on a month end
+	if statement == xbrl.StatementBalanceSheet || end.AddDate(0, 0, 1).Day() != 1 {
+		return end.Format("period_2006_01_02")
+	}
+
+	switch periodTypeOfMonth(end.Month(), fiscalEnd) {
+	case models.PeriodTypeQ1:
+		return end.Format("period_2006_Q1")
+	case models.PeriodTypeH1:
+		return end.Format("period_2006_H1")
+	case models.PeriodTypeQ3:
+		return end.Format("period_2006_Q3")
+	}
+	if end.Month() == fiscalEnd {
+		return end.Format("period_2006")
+	}
+	return end.Format("period_2006_01_02")
+}
+
+// replaceStatements writes the statements of report into the analysis it was read from,
+// keeping the fields openai.Report does not know
+func replaceStatements(analysis json.RawMessage, report *openai.Report) (json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(analysis, &fields); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal analysis: %w", err)
+	}
+
+	for name, v := range map[string]any{
+		"consolidated_financials_million_krw": report.Consolidated,
+		"separate_financials_million_krw":     report.Separate,
+	} {
+		j, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s: %w", name, err)
+		}
+		fields[name] = j
+	}
+
+	j, err := json.MarshalIndent(fields, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal analysis: %w", err)
+	}
+	return j, nil
+}
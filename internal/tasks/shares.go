@@ -0,0 +1,130 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"kosis/internal/models"
+	"kosis/internal/pkg/dart"
+	"kosis/internal/pkg/openai"
+	"log"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// shareCountMaxMonths is how many months before a filing the period of the periodic report its share count
+// is read from may end. The next periodic report is due at most six months after the end of the last one.
+const shareCountMaxMonths = 7
+
+// outstandingShares returns the common shares the company had outstanding when it filed rawReport, nil when
+// they are not known. They are read from the analysis of the latest periodic report filed before it when its
+// period is recent enough, and asked to DART otherwise.
+func (p *TaskProcessor) outstandingShares(ctx context.Context, rawReport *models.RawReport) (*dart.ShareCount, error) {
+	filed, err := time.Parse("20060102", rawReport.ReceiptNumber[:min(8, len(rawReport.ReceiptNumber))])
+	if err != nil {
+		return nil, fmt.Errorf("invalid receipt number %q: %w", rawReport.ReceiptNumber, err)
+	}
+
+	bsnsYear, reportCode := latestDuePeriod(filed)
+
+	var periodic models.RawReport
+	err = p.DB.WithContext(ctx).
+		Where("corp_code = ? AND receipt_number < ?", rawReport.CorpCode, rawReport.ReceiptNumber).
+		Where("report_name ~ ?", `(사업|반기|분기)보고서`).
+		Order("receipt_number DESC").
+		First(&periodic).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+	case err != nil:
+		return nil, err
+	default:
+		periodEnd, ok := periodEndOfReport(periodic.ReportName)
+		if !ok || periodEnd.Before(filed.AddDate(0, -shareCountMaxMonths, 0)) {
+			break
+		}
+
+		shares, err := p.reportShareCount(ctx, &periodic, periodEnd)
+		if err != nil || shares != nil {
+			return shares, err
+		}
+		bsnsYear, reportCode, _ = periodOfReport(periodic.ReportName)
+	}
+
+	if err := p.checkDart(); err != nil {
+		log.Printf("no share count for %s: %v", rawReport.ReceiptNumber, err)
+		return nil, nil
+	}
+
+	items, err := p.dartClient.GetStockTotals(rawReport.CorpCode, bsnsYear, reportCode)
+	if err != nil {
+		// the filing is stored without a share count rather than failed
+		p.haltDart(err)
+		log.Printf("failed to get stock totals of %s %s %s: %v", rawReport.CorpCode, bsnsYear, reportCode, err)
+		return nil, nil
+	}
+
+	shares, ok := dart.CommonShareCount(items)
+	if !ok {
+		return nil, nil
+	}
+	return shares, nil
+}
+
+// reportShareCount returns the outstanding common shares of the OpenAI analysis of a periodic report,
+// nil when it has none
+func (p *TaskProcessor) reportShareCount(ctx context.Context, periodic *models.RawReport, periodEnd time.Time) (*dart.ShareCount, error) {
+	analysis, err := gorm.G[models.Analysis](p.DB).
+		Where("raw_report_id = ? AND analyzer = ?", periodic.ID, models.AnalyzerOpenAI).
+		First(ctx)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var report openai.Report
+	if err := json.Unmarshal(analysis.Analysis, &report); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal analysis: %w", err)
+	}
+
+	outstanding := report.ShareInfo.OutstandingCommon
+	if outstanding <= 0 {
+		outstanding = report.ShareInfo.IssuedCommon
+	}
+	if outstanding <= 0 {
+		return nil, nil
+	}
+
+	return &dart.ShareCount{
+		Outstanding:   outstanding,
+		AsOf:          periodEnd,
+		Source:        dart.ShareSourceReport,
+		ReceiptNumber: periodic.ReceiptNumber,
+	}, nil
+}
+
+// latestDuePeriod returns the business year and report code of the latest periodic report due by date,
+// for a fiscal year ending in December: quarterly reports are due 45 days after the quarter and the
+// business report 90 days after the year
+func latestDuePeriod(date time.Time) (string, dart.ReportType) {
+	year := date.Year()
+	due := func(month time.Month, day int) bool {
+		return date.After(time.Date(year, month, day, 0, 0, 0, 0, date.Location()))
+	}
+
+	switch {
+	case due(time.November, 14):
+		return strconv.Itoa(year), dart.THIRD_QUARTER
+	case due(time.August, 14):
+		return strconv.Itoa(year), dart.HALF_YEAR
+	case due(time.May, 15):
+		return strconv.Itoa(year), dart.FIRST_QUARTER
+	case due(time.March, 31):
+		return strconv.Itoa(year - 1), dart.BUSINESS_REPORT
+	}
+	return strconv.Itoa(year - 1), dart.THIRD_QUARTER
+}
@@ -0,0 +1,69 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"kosis/internal/models"
+	"kosis/internal/pkg/dart"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// storeTreasuryStockEvent stores a parsed treasury stock decision along with the market cap at its reference price.
+// The decision of a correction replaces the one of its original and of the corrections before it, original is
+// empty when the filing is not a correction or its original is not known.
+func (p *TaskProcessor) storeTreasuryStockEvent(ctx context.Context, rawReport *models.RawReport, original string, analysis json.RawMessage) error {
+	var doc dart.TreasuryStockDoc
+	if err := json.Unmarshal(analysis, &doc); err != nil {
+		return fmt.Errorf("failed to unmarshal treasury stock decision: %w", err)
+	}
+
+	event := models.TreasuryStockEvent{
+		CorpCode:       rawReport.CorpCode,
+		ReceiptNumber:  rawReport.ReceiptNumber,
+		Event:          doc.Event,
+		CommonShares:   doc.CommonShares,
+		OtherShares:    doc.OtherShares,
+		Amount:         doc.AmountKRW,
+		ReferencePrice: doc.ReferencePrice(),
+		PeriodFrom:     doc.PeriodFrom,
+		PeriodTo:       doc.PeriodTo,
+		Purpose:        doc.Purpose,
+		Method:         doc.Method,
+		BoardDate:      doc.BoardDate,
+	}
+
+	shares, err := p.outstandingShares(ctx, rawReport)
+	if err != nil {
+		return err
+	}
+	if shares != nil {
+		outstanding := shares.Outstanding
+		event.OutstandingShares = &outstanding
+		if event.ReferencePrice != nil {
+			marketCap := outstanding * *event.ReferencePrice
+			event.MarketCap = &marketCap
+		}
+	}
+
+	return p.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if original != "" {
+			err := tx.Where("receipt_number = ? OR receipt_number IN (?)", original,
+				tx.Model(&models.RawReport{}).Select("receipt_number").Where("corrects_receipt_number = ? AND receipt_number < ?", original, rawReport.ReceiptNumber),
+			).Delete(&models.TreasuryStockEvent{}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "receipt_number"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"event", "common_shares", "other_shares", "amount", "reference_price", "period_from", "period_to",
+				"purpose", "method", "board_date", "outstanding_shares", "market_cap", "updated_at",
+			}),
+		}).Create(&event).Error
+	})
+}
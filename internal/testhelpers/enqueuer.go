@@ -0,0 +1,56 @@
+package testhelpers
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hibiken/asynq"
+)
+
+// RecordingEnqueuer keeps enqueued tasks in memory instead of sending them to Redis.
+// Existing holds the tasks already in the queue by ID, enqueueing one of their IDs again conflicts.
+type RecordingEnqueuer struct {
+	Tasks    []*asynq.Task
+	Existing map[string]*asynq.TaskInfo
+	mutex    sync.Mutex
+}
+
+func (e *RecordingEnqueuer) EnqueueContext(_ context.Context, task *asynq.Task, opts ...asynq.Option) (*asynq.TaskInfo, error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	id := ""
+	for _, opt := range opts {
+		if opt.Type() == asynq.TaskIDOpt {
+			id = opt.Value().(string)
+		}
+	}
+	if _, ok := e.Existing[id]; ok && id != "" {
+		return nil, asynq.ErrTaskIDConflict
+	}
+
+	e.Tasks = append(e.Tasks, task)
+	return &asynq.TaskInfo{ID: id, Type: task.Type(), Payload: task.Payload()}, nil
+}
+
+func (e *RecordingEnqueuer) GetTaskInfo(_, id string) (*asynq.TaskInfo, error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	info, ok := e.Existing[id]
+	if !ok {
+		return nil, asynq.ErrTaskNotFound
+	}
+	return info, nil
+}
+
+func (e *RecordingEnqueuer) DeleteTask(_, id string) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if _, ok := e.Existing[id]; !ok {
+		return asynq.ErrTaskNotFound
+	}
+	delete(e.Existing, id)
+	return nil
+}
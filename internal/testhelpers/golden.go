@@ -0,0 +1,37 @@
+package testhelpers
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// update rewrites the golden files instead of comparing with them, e.g. go test ./internal/pkg/xbrl -update
+var update = flag.Bool("update", false, "rewrite the golden files with the current output")
+
+// Golden returns the golden output of a test at path, the JSON of v indented. With -update the file is
+// rewritten with it first, so that a change of the output shows as a diff of the golden files to review.
+func Golden(path string, v any) (actual, expected []byte, err error) {
+	actual, err = json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal %s: %w", path, err)
+	}
+	actual = append(actual, '\n')
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, nil, err
+		}
+		if err := os.WriteFile(path, actual, 0o644); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	expected, err = os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read golden file, run the tests with -update to write it: %w", err)
+	}
+	return actual, expected, nil
+}
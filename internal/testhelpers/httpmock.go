@@ -244,16 +244,20 @@ func (t *MockTransport) buildResponse(exp *Expectation, req *http.Request) *http
 }
 
 func CreateMockZipArchive(filename string, data []byte) ([]byte, error) {
+	return CreateMockZipArchiveFiles(MockZipFile{Name: filename, Body: string(data)})
+}
+
+// MockZipFile is a file of an archive built by CreateMockZipArchiveFiles
+type MockZipFile struct {
+	Name, Body string
+}
+
+// CreateMockZipArchiveFiles builds an archive of several files, e.g. a document and its attachments
+func CreateMockZipArchiveFiles(files ...MockZipFile) ([]byte, error) {
 	buf := new(bytes.Buffer)
 
 	zipWriter := zip.NewWriter(buf)
 
-	var files = []struct {
-		Name, Body string
-	}{
-		{filename, string(data)},
-	}
-
 	for _, file := range files {
 		f, err := zipWriter.Create(file.Name)
 		if err != nil {